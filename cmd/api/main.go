@@ -8,13 +8,18 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // outlet timezones are resolved even on images without zoneinfo
 
 	"github.com/goplateframework/config"
 	"github.com/goplateframework/internal/httpserver"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/worker/pb"
 	"github.com/goplateframework/pkg/db"
 	"github.com/goplateframework/pkg/grpcclient"
 	"github.com/goplateframework/pkg/logger"
+	"github.com/goplateframework/pkg/mailer"
+	"github.com/goplateframework/pkg/oidcprovider"
+	"github.com/goplateframework/pkg/paymentprovider"
 	"github.com/goplateframework/pkg/redisdb"
 )
 
@@ -31,6 +36,11 @@ func main() {
 		panic("parse config error, " + err.Error())
 	}
 
+	// load asymmetric keys to sign access tokens, shared secret is used whenever no key is configured
+	if err := tokenutil.LoadKeys(conf); err != nil {
+		panic("load jwt keys error, " + err.Error())
+	}
+
 	log := logger.Init(conf)
 
 	ctx := context.Background()
@@ -79,6 +89,26 @@ func run(ctx context.Context, conf *config.Config, log *logger.Log) error {
 	}
 	defer grpcconn.Close()
 
+	// initialize mailer, messages are only logged unless smtp driver is configured
+
+	mail, err := mailer.Init(conf, log)
+	if err != nil {
+		log.Fatalf("mailer error, %v", err)
+		return err
+	} else {
+		log.Infof("mailer initialized, driver: %s", conf.Mailer.Driver)
+	}
+
+	// initialize payment provider, charges are kept in memory unless a real provider is configured
+
+	payment, err := paymentprovider.Init(conf)
+	if err != nil {
+		log.Fatalf("payment provider error, %v", err)
+		return err
+	} else {
+		log.Infof("payment provider initialized, driver: %s", payment.Name())
+	}
+
 	// channel to receive shutdownCh signal, for graceful shutdownCh
 	shutdownCh := make(chan os.Signal, 1)
 	signal.Notify(shutdownCh, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
@@ -90,6 +120,9 @@ func run(ctx context.Context, conf *config.Config, log *logger.Log) error {
 		Log:      log,
 		ServConf: conf,
 		Worker:   pb.NewWorkerClient(grpcconn),
+		Mailer:   mail,
+		OIDC:     oidcprovider.Init(conf),
+		Payment:  payment,
 	})
 
 	// channel for handling server errors which may occur during listening and serving
@@ -9,6 +9,14 @@ type Config struct {
 	Logger        loggerConfig
 	GRPCWorker    grpcWorkerConfig
 	GoogleStorage googleStorageConfig
+	Mailer        mailerConfig
+	Account       accountConfig
+	LoginThrottle loginThrottleConfig
+	MFA           mfaConfig
+	OIDC          oidcConfig
+	Cart          cartConfig
+	Payment       paymentConfig
+	Public        publicConfig
 }
 
 type serverConfig struct {
@@ -21,12 +29,29 @@ type serverConfig struct {
 	Host                  string
 	JWTRefreshTokenSecret string
 	JWTAccessTokenSecret  string
+	JWTSigningKeyID       string
+	JWTKeys               []jwtKeyConfig
+	JWTIssuer             string
+	JWTAudience           string
+	AccessTokenTTL        time.Duration // in seconds
+	RefreshTokenTTL       time.Duration // in seconds
+	RefreshTokenSliding   bool          // whenever true, every refresh extends session by RefreshTokenTTL
 	Mode                  string
 	Port                  string
 	ReadTimeout           time.Duration
+	TrustedProxies        []string // CIDR ranges of reverse proxies whose X-Forwarded-For is trusted, client ip is the peer address whenever empty
 	WriteTimeout          time.Duration
 }
 
+// jwtKeyConfig is an asymmetric key pair to sign access tokens,
+// key without private key path is a retired key which only verifies tokens issued before rotation
+type jwtKeyConfig struct {
+	ID             string
+	Algorithm      string // RS256 | EdDSA
+	PrivateKeyPath string
+	PublicKeyPath  string
+}
+
 type loggerConfig struct {
 	Development bool
 	Encoding    string
@@ -61,3 +86,71 @@ type googleStorageConfig struct {
 	Path       string
 	BucketName string
 }
+
+type mailerConfig struct {
+	Driver   string // smtp | file | log, log is used whenever it is empty
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Filepath string // file driver only, every message is appended to this file
+}
+
+type accountConfig struct {
+	ResetPasswordURL string        // link on reset password email, token is appended as query param
+	ResetPasswordTTL time.Duration // in seconds
+
+	RequireEmailVerification bool          // whenever true, unverified accounts could not login
+	VerifyEmailURL           string        // link on verification email, token is appended as query param
+	VerifyEmailTTL           time.Duration // in seconds
+	VerifyEmailCooldown      time.Duration // in seconds, minimum interval between verification emails
+}
+
+// loginThrottleConfig locks login out temporarily after too many failures within window,
+// every consecutive lockout doubles its duration up to MaxLockoutDuration
+type loginThrottleConfig struct {
+	MaxAttemptsPerEmail int
+	MaxAttemptsPerIP    int
+	Window              time.Duration // in seconds
+	LockoutDuration     time.Duration // in seconds
+	MaxLockoutDuration  time.Duration // in seconds
+}
+
+type mfaConfig struct {
+	Issuer        string        // shown on authenticator apps
+	RequiredRoles []string      // accounts of these roles could not login until they enroll
+	ChallengeTTL  time.Duration // in seconds
+}
+
+type oidcConfig struct {
+	StateTTL  time.Duration                 // in seconds, how long an authorization request could be completed
+	Providers map[string]oidcProviderConfig // keyed by provider name on /api/v1/auth/oidc/:provider
+}
+
+type oidcProviderConfig struct {
+	Issuer       string // discovery is performed against <Issuer>/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // should point to /api/v1/auth/oidc/:provider/callback
+	Scopes       []string // openid scope is always requested
+}
+
+type cartConfig struct {
+	TTL      time.Duration // in seconds, cart expires once it is left untouched this long
+	MaxItems int
+}
+
+type paymentConfig struct {
+	Driver        string // only mock is supported so far, it settles charges through signed webhooks
+	Currency      string // ISO 4217 code of every charge
+	WebhookSecret string // shared with provider to sign webhook payloads with HMAC-SHA256
+	MaxAttempts   int    // charge attempts of a payment before it could no longer be retried
+}
+
+// publicConfig tunes the anonymous catalog on /api/v1/public, which is rate limited per client ip on its own
+type publicConfig struct {
+	RateLimit  int           // requests of a client ip within RateWindow
+	RateWindow time.Duration // in seconds
+	MaxAge     time.Duration // in seconds, how long clients and proxies could reuse a response without revalidating
+}
@@ -3,11 +3,12 @@ package accountrepo
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/bytedance/sonic"
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/pkg/cacheaside"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -17,27 +18,100 @@ const (
 
 type Cache struct {
 	*redis.Client
+	me *cacheaside.Cache[account.AccountDTO]
 }
 
 func NewCache(client *redis.Client) *Cache {
-	return &Cache{client}
+	return &Cache{
+		Client: client,
+		me:     cacheaside.New[account.AccountDTO](client, "me", meExpires),
+	}
 }
 
 func (c *Cache) SetMe(ctx context.Context, accountPayload *account.AccountDTO) error {
-	data, err := sonic.Marshal(accountPayload)
+	return c.me.Set(ctx, accountPayload.ID.String(), *accountPayload)
+}
+
+func (c *Cache) GetMe(ctx context.Context, id uuid.UUID) (*account.AccountDTO, error) {
+	a, ok, err := c.me.Get(ctx, id.String())
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (c *Cache) RemoveMe(ctx context.Context, id uuid.UUID) error {
+	return c.me.Invalidate(ctx, id.String())
+}
+
+// one-time token purposes, each purpose is namespaced on its own keys
+const (
+	tokenResetPassword = "reset_password"
+	tokenVerifyEmail   = "verify_email"
+)
+
+// SetResetToken stores hash of reset password token which belongs to an account,
+// previous token of the account is discarded so only the latest email could be used
+func (c *Cache) SetResetToken(ctx context.Context, accountID uuid.UUID, tokenHash string, exp time.Duration) error {
+	return c.setToken(ctx, tokenResetPassword, accountID, tokenHash, exp)
+}
+
+// ConsumeResetToken returns account id of given token hash and removes it at once, so the token is single use.
+// It returns nil whenever token does not exist or has expired
+func (c *Cache) ConsumeResetToken(ctx context.Context, tokenHash string) (*uuid.UUID, error) {
+	return c.consumeToken(ctx, tokenResetPassword, tokenHash)
+}
+
+// SetVerifyToken stores hash of email verification token which belongs to an account,
+// previous token of the account is discarded so only the latest email could be used
+func (c *Cache) SetVerifyToken(ctx context.Context, accountID uuid.UUID, tokenHash string, exp time.Duration) error {
+	return c.setToken(ctx, tokenVerifyEmail, accountID, tokenHash, exp)
+}
 
+// ConsumeVerifyToken returns account id of given token hash and removes it at once, so the token is single use.
+// It returns nil whenever token does not exist or has expired
+func (c *Cache) ConsumeVerifyToken(ctx context.Context, tokenHash string) (*uuid.UUID, error) {
+	return c.consumeToken(ctx, tokenVerifyEmail, tokenHash)
+}
+
+// AcquireVerifyCooldown starts cooldown of sending verification email to given email.
+// It returns remaining time whenever cooldown is still running, in which no email should be sent
+func (c *Cache) AcquireVerifyCooldown(ctx context.Context, email string, cooldown time.Duration) (time.Duration, error) {
+	key := getVerifyCooldownKey(email)
+
+	ok, err := c.SetNX(ctx, key, 1, cooldown).Result()
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	if ok {
+		return 0, nil
 	}
 
-	key := getMeKey(accountPayload.ID)
-	return c.Set(ctx, key, data, meExpires).Err()
+	return c.TTL(ctx, key).Result()
 }
 
-func (c *Cache) GetMe(ctx context.Context, id uuid.UUID) (*account.AccountDTO, error) {
-	key := getMeKey(id)
+func (c *Cache) setToken(ctx context.Context, purpose string, accountID uuid.UUID, tokenHash string, exp time.Duration) error {
+	prev, err := c.Get(ctx, getTokenAccountKey(purpose, accountID)).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	_, err = c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if prev != "" {
+			pipe.Del(ctx, getTokenKey(purpose, prev))
+		}
+		pipe.Set(ctx, getTokenKey(purpose, tokenHash), accountID.String(), exp)
+		pipe.Set(ctx, getTokenAccountKey(purpose, accountID), tokenHash, exp)
+		return nil
+	})
 
-	data, err := c.Get(ctx, key).Result()
+	return err
+}
+
+func (c *Cache) consumeToken(ctx context.Context, purpose, tokenHash string) (*uuid.UUID, error) {
+	val, err := c.GetDel(ctx, getTokenKey(purpose, tokenHash)).Result()
 	if err == redis.Nil {
 		return nil, nil
 	}
@@ -46,18 +120,26 @@ func (c *Cache) GetMe(ctx context.Context, id uuid.UUID) (*account.AccountDTO, e
 		return nil, err
 	}
 
-	a := new(Model)
-	if err := sonic.Unmarshal([]byte(data), &a); err != nil {
+	id, err := uuid.Parse(val)
+	if err != nil {
 		return nil, err
 	}
 
-	return a.intoDTO(), nil
+	if err := c.Del(ctx, getTokenAccountKey(purpose, id)).Err(); err != nil {
+		return nil, err
+	}
+
+	return &id, nil
 }
 
-func (c *Cache) RemoveMe(ctx context.Context, id uuid.UUID) error {
-	return c.Del(ctx, getMeKey(id)).Err()
+func getTokenKey(purpose, tokenHash string) string {
+	return fmt.Sprintf("%s:%s", purpose, tokenHash)
+}
+
+func getTokenAccountKey(purpose string, id uuid.UUID) string {
+	return fmt.Sprintf("%s_account:%s", purpose, id.String())
 }
 
-func getMeKey(id uuid.UUID) string {
-	return fmt.Sprintf("me:%s", id.String())
+func getVerifyCooldownKey(email string) string {
+	return fmt.Sprintf("verify_email_cooldown:%s", strings.ToLower(email))
 }
@@ -0,0 +1,35 @@
+package accountrepo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goplateframework/internal/domain/account/accountweb"
+)
+
+// buildFilter never includes deleted accounts, regardless of given query params
+func (dbrepo *repository) buildFilter(args map[string]any, qp *accountweb.QueryParams) string {
+	filters := []string{" deleted_at IS NULL"}
+
+	if qp.Filter.Role != "" {
+		args["role"] = qp.Filter.Role
+		filters = append(filters, " role = :role")
+	}
+
+	if qp.Filter.Email != "" {
+		args["email"] = "%" + qp.Filter.Email + "%"
+		filters = append(filters, " email ILIKE :email")
+	}
+
+	if qp.Filter.CreatedAfter != nil {
+		args["created_after"] = *qp.Filter.CreatedAfter
+		filters = append(filters, " created_at >= :created_after")
+	}
+
+	if qp.Filter.CreatedUntil != nil {
+		args["created_until"] = *qp.Filter.CreatedUntil
+		filters = append(filters, " created_at < :created_until")
+	}
+
+	return fmt.Sprintf(" WHERE %s", strings.Join(filters, " AND "))
+}
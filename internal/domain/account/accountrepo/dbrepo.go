@@ -2,9 +2,13 @@ package accountrepo
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/account/accountweb"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -21,7 +25,7 @@ func (dbrepo *repository) GetOneByEmail(ctx context.Context, email string) (*acc
 
 	q := `
 	SELECT * FROM accounts
-	WHERE email = $1
+	WHERE email = $1 AND deleted_at IS NULL
 	LIMIT 1
 	`
 
@@ -37,7 +41,7 @@ func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*account.Ac
 
 	q := `
 	SELECT * FROM accounts
-	WHERE id = $1
+	WHERE id = $1 AND deleted_at IS NULL
 	LIMIT 1
 	`
 
@@ -51,20 +55,183 @@ func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*account.Ac
 func (dbrepo *repository) Create(ctx context.Context, a *account.AccountDTO) error {
 	q := `
 	INSERT INTO accounts
-		(id, firstname, lastname, email, password, phone, role, created_at, updated_at)
+		(id, firstname, lastname, email, password, phone, role, created_at, updated_at, email_verified_at)
 	VALUES
-		(:id, :firstname, :lastname, :email, :password, :phone, :role, :created_at, :updated_at)`
+		(:id, :firstname, :lastname, :email, :password, :phone, :role, :created_at, :updated_at, :email_verified_at)`
 
 	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(a))
 	return err
 }
 
-func (repo *repository) ChangePassword(ctx context.Context, email, password string) error {
+// MarkEmailVerified stamps verification time on account, an already verified account keeps its first stamp
+func (dbrepo *repository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	q := `
+	UPDATE accounts
+	SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1`
+
+	_, err := dbrepo.ExecContext(ctx, q, id)
+	return err
+}
+
+// UpdateProfile stores profile fields of an account, along with its email verification state
+func (dbrepo *repository) UpdateProfile(ctx context.Context, a *account.AccountDTO) error {
+	q := `
+	UPDATE accounts
+	SET
+		firstname = :firstname,
+		lastname = :lastname,
+		email = :email,
+		phone = :phone,
+		email_verified_at = :email_verified_at,
+		updated_at = :updated_at
+	WHERE id = :id AND deleted_at IS NULL`
+
+	m := intoModel(a)
+	m.UpdatedAt = time.Now()
+
+	_, err := dbrepo.NamedExecContext(ctx, q, m)
+	return err
+}
+
+// SoftDelete anonymizes an account, so its email could be registered again, and detaches everything
+// which could authenticate as the account. The row itself is kept for records which refer to it
+func (dbrepo *repository) SoftDelete(ctx context.Context, id uuid.UUID, unusablePassword string) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE accounts
+	SET
+		firstname = 'Deleted',
+		lastname = 'Account',
+		email = $2,
+		phone = '',
+		password = $3,
+		deleted_at = CURRENT_TIMESTAMP,
+		updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND deleted_at IS NULL`
+
+	anonymizedEmail := fmt.Sprintf("%s@deleted.invalid", strings.ReplaceAll(id.String(), "-", ""))
+	if _, err := tx.ExecContext(ctx, q, id, anonymizedEmail, unusablePassword); err != nil {
+		return err
+	}
+
+	detach := []string{
+		`DELETE FROM account_identities WHERE account_id = $1`,
+		`DELETE FROM account_recovery_codes WHERE account_id = $1`,
+		`DELETE FROM account_mfa WHERE account_id = $1`,
+		`DELETE FROM outlet_staff WHERE account_id = $1`,
+		`UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE account_id = $1 AND revoked_at IS NULL`,
+	}
+
+	for _, q := range detach {
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HasIdentity tells whether an account is linked to any provider identity
+func (dbrepo *repository) HasIdentity(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+
+	q := `SELECT EXISTS (SELECT 1 FROM account_identities WHERE account_id = $1)`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (repo *repository) ChangePassword(ctx context.Context, id uuid.UUID, password string) error {
 	q := `
 	UPDATE accounts
 	SET password = $1
-	WHERE email = $2`
+	WHERE id = $2`
+
+	_, err := repo.ExecContext(ctx, q, password, id)
+	return err
+}
+
+// Count returns total of accounts which match filters of given query params
+func (dbrepo *repository) Count(ctx context.Context, qp *accountweb.QueryParams) (int, error) {
+	args := map[string]any{}
+	q := "SELECT COUNT(*) AS total FROM accounts" + dbrepo.buildFilter(args, qp)
+
+	stmt, err := dbrepo.PrepareNamedContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var count struct {
+		Total int `db:"total"`
+	}
+
+	if err := stmt.GetContext(ctx, &count, args); err != nil {
+		return 0, err
+	}
+
+	return count.Total, nil
+}
+
+func (dbrepo *repository) GetAll(ctx context.Context, qp *accountweb.QueryParams) ([]account.AccountDTO, error) {
+	args := map[string]any{
+		"size":   qp.Page.Size,
+		"offset": qp.Page.Offset,
+	}
+
+	var qb strings.Builder
+	qb.WriteString(`
+		SELECT * FROM accounts
+	`)
+
+	qb.WriteString(dbrepo.buildFilter(args, qp))
+	qb.WriteString(fmt.Sprintf(" ORDER BY %s %s", qp.OrderBy.Field, qp.OrderBy.Direction))
+	qb.WriteString(" OFFSET :offset LIMIT :size")
+
+	rows, err := dbrepo.NamedQueryContext(ctx, qb.String(), args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []account.AccountDTO
+	for rows.Next() {
+		a := new(Model)
+		if err := rows.StructScan(a); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *a.intoDTO())
+	}
+
+	return accounts, nil
+}
+
+func (dbrepo *repository) ChangeRole(ctx context.Context, id uuid.UUID, role string) error {
+	q := `
+	UPDATE accounts
+	SET role = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND deleted_at IS NULL`
+
+	_, err := dbrepo.ExecContext(ctx, q, id, role)
+	return err
+}
+
+// SetSuspended suspends an account at given time, or reactivates it whenever given time is nil
+func (dbrepo *repository) SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time) error {
+	q := `
+	UPDATE accounts
+	SET suspended_at = $2, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND deleted_at IS NULL`
 
-	_, err := repo.ExecContext(ctx, q, password, email)
+	_, err := dbrepo.ExecContext(ctx, q, id, suspendedAt)
 	return err
 }
@@ -17,6 +17,10 @@ type Model struct {
 	Role      string    `db:"role"` // ENUM: user, admin, superadmin
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+	SuspendedAt     *time.Time `db:"suspended_at"`
+	DeletedAt       *time.Time `db:"deleted_at"`
 }
 
 func (m *Model) intoDTO() *account.AccountDTO {
@@ -28,6 +32,11 @@ func (m *Model) intoDTO() *account.AccountDTO {
 		Email:     m.Email,
 		Phone:     m.Phone,
 		Role:      m.Role,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+
+		EmailVerifiedAt: m.EmailVerifiedAt,
+		SuspendedAt:     m.SuspendedAt,
 	}
 }
 
@@ -40,5 +49,10 @@ func intoModel(a *account.AccountDTO) *Model {
 		Password:  a.Password,
 		Phone:     a.Phone,
 		Role:      a.Role,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+
+		EmailVerifiedAt: a.EmailVerifiedAt,
+		SuspendedAt:     a.SuspendedAt,
 	}
 }
@@ -0,0 +1,149 @@
+package accountuc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/account/accountweb"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/web/result"
+)
+
+// GetAll lists accounts which match filters of given query params
+func (uc *Usecase) GetAll(ctx context.Context, qp *accountweb.QueryParams) (*result.Result[account.AccountDTO], error) {
+	total, err := uc.dbRepo.Count(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if total > 0 && !qp.Page.CanPaginate(total) {
+		e := errshttp.New(errshttp.InvalidArgument, "Page requested is out of range")
+		e.AddDetail(fmt.Sprintf("pagination: page number must be between 1 and %d", total))
+		return nil, e
+	}
+
+	a, err := uc.dbRepo.GetAll(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return result.New(a, total, qp.Page.Number, qp.Page.Size), nil
+}
+
+// ChangeRole changes role of an account, then logs it out everywhere, so its tokens do not carry previous role
+func (uc *Usecase) ChangeRole(ctx context.Context, id uuid.UUID, role string, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error) {
+	a, err := uc.otherAccount(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.dbRepo.ChangeRole(ctx, a.ID, role); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.logoutEverywhere(ctx, a.ID); err != nil {
+		return nil, err
+	}
+
+	a.Role = role
+	return a, nil
+}
+
+// Suspend prevents an account from logging in, refreshing its tokens and using its api keys,
+// then logs it out everywhere
+func (uc *Usecase) Suspend(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error) {
+	a, err := uc.otherAccount(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.SuspendedAt != nil {
+		return a, nil
+	}
+
+	now := time.Now()
+	if err := uc.dbRepo.SetSuspended(ctx, a.ID, &now); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.logoutEverywhere(ctx, a.ID); err != nil {
+		return nil, err
+	}
+
+	a.SuspendedAt = &now
+	return a, nil
+}
+
+func (uc *Usecase) Reactivate(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error) {
+	a, err := uc.otherAccount(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.SuspendedAt == nil {
+		return a, nil
+	}
+
+	if err := uc.dbRepo.SetSuspended(ctx, a.ID, nil); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, a.ID); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	a.SuspendedAt = nil
+	return a, nil
+}
+
+// ForceLogout revokes every session of an account, access tokens of those sessions are rejected right away
+func (uc *Usecase) ForceLogout(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	a, err := uc.otherAccount(ctx, id, claims)
+	if err != nil {
+		return err
+	}
+
+	return uc.logoutEverywhere(ctx, a.ID)
+}
+
+// otherAccount gets an account to be managed, superadmin is not allowed to manage its own account,
+// so it could not lock itself out
+func (uc *Usecase) otherAccount(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error) {
+	if id == claims.AccountID {
+		e := errshttp.New(errshttp.FailedPrecondition, "Could not manage own account")
+		e.AddDetail("id: should be id of another account")
+		return nil, e
+	}
+
+	a, err := uc.dbRepo.GetOne(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			e := errshttp.New(errshttp.NotFound, "Account could not be found")
+			e.AddDetail(fmt.Sprintf("data: account with id %s not found", id))
+			return nil, e
+		}
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return a, nil
+}
+
+func (uc *Usecase) logoutEverywhere(ctx context.Context, accountID uuid.UUID) error {
+	if err := uc.sessionRepo.RevokeAllSessions(ctx, accountID); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to revoke sessions")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, accountID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
@@ -2,14 +2,21 @@ package accountuc
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/goplateframework/config"
 	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/account/accountweb"
+	"github.com/goplateframework/internal/domain/auth"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/pkg/logger"
+	"github.com/goplateframework/pkg/mailer"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -17,27 +24,51 @@ type iDBRepository interface {
 	GetOne(ctx context.Context, id uuid.UUID) (*account.AccountDTO, error)
 	GetOneByEmail(ctx context.Context, email string) (*account.AccountDTO, error)
 	Create(ctx context.Context, a *account.AccountDTO) error
-	ChangePassword(ctx context.Context, email, password string) error
+	ChangePassword(ctx context.Context, id uuid.UUID, password string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	UpdateProfile(ctx context.Context, a *account.AccountDTO) error
+	SoftDelete(ctx context.Context, id uuid.UUID, unusablePassword string) error
+	HasIdentity(ctx context.Context, id uuid.UUID) (bool, error)
+	Count(ctx context.Context, qp *accountweb.QueryParams) (int, error)
+	GetAll(ctx context.Context, qp *accountweb.QueryParams) ([]account.AccountDTO, error)
+	ChangeRole(ctx context.Context, id uuid.UUID, role string) error
+	SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time) error
 }
 
 type iCacheRepository interface {
 	SetMe(ctx context.Context, accountPayload *account.AccountDTO) error
 	GetMe(ctx context.Context, id uuid.UUID) (*account.AccountDTO, error)
+	RemoveMe(ctx context.Context, id uuid.UUID) error
+	SetResetToken(ctx context.Context, accountID uuid.UUID, tokenHash string, exp time.Duration) error
+	ConsumeResetToken(ctx context.Context, tokenHash string) (*uuid.UUID, error)
+	SetVerifyToken(ctx context.Context, accountID uuid.UUID, tokenHash string, exp time.Duration) error
+	ConsumeVerifyToken(ctx context.Context, tokenHash string) (*uuid.UUID, error)
+	AcquireVerifyCooldown(ctx context.Context, email string, cooldown time.Duration) (time.Duration, error)
+}
+
+type iSessionRepository interface {
+	RevokeAllSessions(ctx context.Context, accountID uuid.UUID) error
+	GetSessions(ctx context.Context, accountID uuid.UUID) ([]auth.SessionDTO, error)
+	RevokeSession(ctx context.Context, accountID, sessionID uuid.UUID) error
 }
 
 type Usecase struct {
-	conf      *config.Config
-	log       *logger.Log
-	dbRepo    iDBRepository
-	cacheRepo iCacheRepository
+	conf        *config.Config
+	log         *logger.Log
+	dbRepo      iDBRepository
+	cacheRepo   iCacheRepository
+	sessionRepo iSessionRepository
+	mail        mailer.Mailer
 }
 
-func New(conf *config.Config, log *logger.Log, dbRepo iDBRepository, cacheRepo iCacheRepository) *Usecase {
+func New(conf *config.Config, log *logger.Log, dbRepo iDBRepository, cacheRepo iCacheRepository, sessionRepo iSessionRepository, mail mailer.Mailer) *Usecase {
 	return &Usecase{
-		conf:      conf,
-		log:       log,
-		dbRepo:    dbRepo,
-		cacheRepo: cacheRepo,
+		conf:        conf,
+		log:         log,
+		dbRepo:      dbRepo,
+		cacheRepo:   cacheRepo,
+		sessionRepo: sessionRepo,
+		mail:        mail,
 	}
 }
 
@@ -62,7 +93,7 @@ func (uc *Usecase) Register(ctx context.Context, na *account.NewAccouuntDTO) (*a
 		Phone:     na.Phone,
 		Email:     na.Email,
 		Password:  string(hashedPass),
-		Role:      "user",
+		Role:      account.RoleUser,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -71,13 +102,109 @@ func (uc *Usecase) Register(ctx context.Context, na *account.NewAccouuntDTO) (*a
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
+	// account is created regardless, verification email could be requested again whenever it fails
+	if _, err := uc.cacheRepo.AcquireVerifyCooldown(ctx, a.Email, uc.verifyEmailCooldown()); err != nil {
+		uc.log.Errorf("failed to start verification cooldown of account %s, %v", a.ID, err)
+	}
+
+	if err := uc.sendVerification(ctx, a); err != nil {
+		uc.log.Errorf("failed to send verification email to account %s, %v", a.ID, err)
+	}
+
 	return a, nil
 }
 
-func (uc *Usecase) ChangePassword(ctx context.Context, cp *account.ChangePasswordDTO, email string) error {
+// VerifyEmail marks email of the owner of given token as verified
+func (uc *Usecase) VerifyEmail(ctx context.Context, token string) error {
+	accountID, err := uc.cacheRepo.ConsumeVerifyToken(ctx, tokenutil.HashOpaque(token))
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if accountID == nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Verification token is invalid or has expired")
+		e.AddDetail("token: verification token is either invalid, expired or already used")
+		return e
+	}
+
+	if err := uc.dbRepo.MarkEmailVerified(ctx, *accountID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, *accountID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// ResendVerification emails a new verification token whenever given email is registered and not verified yet.
+// Cooldown applies to any email, so it does not tell whether the email exists
+func (uc *Usecase) ResendVerification(ctx context.Context, email string) error {
+	remaining, err := uc.cacheRepo.AcquireVerifyCooldown(ctx, email, uc.verifyEmailCooldown())
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if remaining > 0 {
+		e := errshttp.New(errshttp.ResourceExhausted, "Verification email has been sent recently")
+		e.AddDetail(fmt.Sprintf("email: try again in %d seconds", int(remaining.Seconds())+1))
+		return e
+	}
+
 	a, err := uc.dbRepo.GetOneByEmail(ctx, email)
 	if err != nil {
-		errshttp.New(errshttp.Internal, "Something went wrong")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if a.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	if err := uc.sendVerification(ctx, a); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to send verification email")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	return nil
+}
+
+// sendVerification stores a new verification token of given account, then emails it in background
+func (uc *Usecase) sendVerification(ctx context.Context, a *account.AccountDTO) error {
+	token, tokenHash, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return err
+	}
+
+	ttl := uc.verifyEmailTTL()
+	if err := uc.cacheRepo.SetVerifyToken(ctx, a.ID, tokenHash, ttl); err != nil {
+		return err
+	}
+
+	msg := &mailer.Message{
+		To:      a.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email, it expires in %d hours.\n\n%s",
+			a.Firstname, int(ttl.Hours()), tokenLink(uc.conf.Account.VerifyEmailURL, token)),
+	}
+
+	go func() {
+		if err := uc.mail.Send(context.Background(), msg); err != nil {
+			uc.log.Errorf("failed to send verification email to account %s, %v", a.ID, err)
+		}
+	}()
+
+	return nil
+}
+
+func (uc *Usecase) ChangePassword(ctx context.Context, cp *account.ChangePasswordDTO, accountID uuid.UUID) error {
+	a, err := uc.dbRepo.GetOne(ctx, accountID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(a.Password), []byte(cp.OldPassword)); err != nil {
@@ -89,13 +216,132 @@ func (uc *Usecase) ChangePassword(ctx context.Context, cp *account.ChangePasswor
 		return errshttp.New(errshttp.Internal, "Failed to perform password hashing")
 	}
 
-	if err := uc.dbRepo.ChangePassword(ctx, email, string(hashedPass)); err != nil {
-		errshttp.New(errshttp.Internal, "Something went wrong")
+	if err := uc.dbRepo.ChangePassword(ctx, a.ID, string(hashedPass)); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, a.ID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// ForgotPassword emails a one-time reset password token whenever given email is registered.
+// It never tells whether the email exists, so accounts could not be enumerated
+func (uc *Usecase) ForgotPassword(ctx context.Context, email string) error {
+	a, err := uc.dbRepo.GetOneByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	token, tokenHash, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Failed to generate reset password token")
+	}
+
+	ttl := uc.resetPasswordTTL()
+	if err := uc.cacheRepo.SetResetToken(ctx, a.ID, tokenHash, ttl); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to store reset password token")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	msg := &mailer.Message{
+		To:      a.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password, it expires in %d minutes.\n\n%s\n\nIgnore this email if you did not request it.",
+			a.Firstname, int(ttl.Minutes()), tokenLink(uc.conf.Account.ResetPasswordURL, token)),
 	}
 
+	// mail is delivered in background, so response time does not reveal whether the email exists
+	go func() {
+		if err := uc.mail.Send(context.Background(), msg); err != nil {
+			uc.log.Errorf("failed to send reset password email to account %s, %v", a.ID, err)
+		}
+	}()
+
 	return nil
 }
 
+// ResetPassword replaces password of the owner of given token, then revokes every session of the account
+func (uc *Usecase) ResetPassword(ctx context.Context, rp *account.ResetPasswordDTO) error {
+	accountID, err := uc.cacheRepo.ConsumeResetToken(ctx, tokenutil.HashOpaque(rp.Token))
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if accountID == nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Reset password token is invalid or has expired")
+		e.AddDetail("token: reset password token is either invalid, expired or already used")
+		return e
+	}
+
+	a, err := uc.dbRepo.GetOne(ctx, *accountID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	hashedPass, err := bcrypt.GenerateFromPassword([]byte(rp.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Failed to perform password hashing")
+	}
+
+	if err := uc.dbRepo.ChangePassword(ctx, a.ID, string(hashedPass)); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.sessionRepo.RevokeAllSessions(ctx, a.ID); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to revoke sessions")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, a.ID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+func (uc *Usecase) resetPasswordTTL() time.Duration {
+	if uc.conf.Account.ResetPasswordTTL <= 0 {
+		return 30 * time.Minute
+	}
+	return uc.conf.Account.ResetPasswordTTL * time.Second
+}
+
+func (uc *Usecase) verifyEmailTTL() time.Duration {
+	if uc.conf.Account.VerifyEmailTTL <= 0 {
+		return 24 * time.Hour
+	}
+	return uc.conf.Account.VerifyEmailTTL * time.Second
+}
+
+func (uc *Usecase) verifyEmailCooldown() time.Duration {
+	if uc.conf.Account.VerifyEmailCooldown <= 0 {
+		return time.Minute
+	}
+	return uc.conf.Account.VerifyEmailCooldown * time.Second
+}
+
+// tokenLink appends token as query param of given link, token itself is returned whenever link is not configured
+func tokenLink(link, token string) string {
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		return token
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
 func (uc *Usecase) Me(ctx context.Context, accountID uuid.UUID) (*account.AccountDTO, error) {
 	meCache, err := uc.cacheRepo.GetMe(ctx, accountID)
 	if err != nil {
@@ -119,3 +365,191 @@ func (uc *Usecase) Me(ctx context.Context, accountID uuid.UUID) (*account.Accoun
 
 	return a, nil
 }
+
+// UpdateMe updates profile of the account which owns given claims. Changing email is confirmed like deleting account,
+// since whoever owns the email could reset password. Old address is notified, other sessions are revoked
+// and verification is reset, then a verification email is sent to the new address
+func (uc *Usecase) UpdateMe(ctx context.Context, up *account.UpdateProfileDTO, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error) {
+	a, err := uc.dbRepo.GetOne(ctx, claims.AccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errshttp.New(errshttp.NotFound, "Account not found")
+		}
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	oldEmail := a.Email
+	emailChanged := up.Email != "" && up.Email != a.Email
+	if emailChanged {
+		if err := uc.confirmIdentity(ctx, a, up.Password, claims); err != nil {
+			return nil, err
+		}
+
+		existing, err := uc.dbRepo.GetOneByEmail(ctx, up.Email)
+		if existing != nil && err == nil {
+			e := errshttp.New(errshttp.AlreadyExists, "Email already taken")
+			e.AddDetail(fmt.Sprintf("email: %s already taken", up.Email))
+			return nil, e
+		}
+
+		a.Email = up.Email
+		a.EmailVerifiedAt = nil
+	}
+
+	if up.Firstname != "" {
+		a.Firstname = up.Firstname
+	}
+
+	if up.Lastname != "" {
+		a.Lastname = up.Lastname
+	}
+
+	if up.Phone != "" {
+		a.Phone = up.Phone
+	}
+
+	if err := uc.dbRepo.UpdateProfile(ctx, a); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, a.ID); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if emailChanged {
+		if err := uc.revokeOtherSessions(ctx, a.ID, claims.SessionID); err != nil {
+			e := errshttp.New(errshttp.Internal, "Failed to revoke sessions")
+			e.AddDetail(fmt.Sprintf("data: %v", err))
+			return nil, e
+		}
+
+		uc.notifyEmailChanged(a, oldEmail)
+
+		if err := uc.sendVerification(ctx, a); err != nil {
+			uc.log.Errorf("failed to send verification email to account %s, %v", a.ID, err)
+		}
+	}
+
+	return a, nil
+}
+
+// revokeOtherSessions revokes every session of an account except the one which is currently used
+func (uc *Usecase) revokeOtherSessions(ctx context.Context, accountID, sessionID uuid.UUID) error {
+	sessions, err := uc.sessionRepo.GetSessions(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		if s.ID == sessionID {
+			continue
+		}
+
+		if err := uc.sessionRepo.RevokeSession(ctx, accountID, s.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyEmailChanged tells the old address that email of the account has been changed, so its owner notices
+// whenever somebody else did it
+func (uc *Usecase) notifyEmailChanged(a *account.AccountDTO, oldEmail string) {
+	msg := &mailer.Message{
+		To:      oldEmail,
+		Subject: "Your email has been changed",
+		Body: fmt.Sprintf("Hi %s,\n\nEmail of your account has been changed into %s, and every other session has been logged out.\n\nContact us right away if you did not do it.",
+			a.Firstname, a.Email),
+	}
+
+	go func() {
+		if err := uc.mail.Send(context.Background(), msg); err != nil {
+			uc.log.Errorf("failed to send email change notice to account %s, %v", a.ID, err)
+		}
+	}()
+}
+
+// DeleteMe anonymizes the account which owns given claims once deletion is confirmed, then revokes every session of it
+func (uc *Usecase) DeleteMe(ctx context.Context, da *account.DeleteAccountDTO, claims *tokenutil.AccessTokenClaims) error {
+	a, err := uc.dbRepo.GetOne(ctx, claims.AccountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errshttp.New(errshttp.NotFound, "Account not found")
+		}
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.confirmIdentity(ctx, a, da.Password, claims); err != nil {
+		return err
+	}
+
+	// password is replaced by hash of a random secret nobody knows, so the account could never log in again
+	secret, _, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	unusablePass, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Failed to perform password hashing")
+	}
+
+	if err := uc.dbRepo.SoftDelete(ctx, a.ID, string(unusablePass)); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.sessionRepo.RevokeAllSessions(ctx, a.ID); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to revoke sessions")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	if err := uc.cacheRepo.RemoveMe(ctx, a.ID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// recentLogin is how long after logging in a session is trusted to confirm sensitive changes without password
+const recentLogin = 5 * time.Minute
+
+// confirmIdentity checks password of the account before a sensitive change. Account which is linked to a provider has a random
+// password nobody knows, so it is confirmed by a session which has just logged in instead, such as logging in through the provider again
+func (uc *Usecase) confirmIdentity(ctx context.Context, a *account.AccountDTO, password string, claims *tokenutil.AccessTokenClaims) error {
+	if password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(a.Password), []byte(password)); err != nil {
+			e := errshttp.New(errshttp.InvalidCredentials, "Password is invalid")
+			e.AddDetail("password: does not match password of the account")
+			return e
+		}
+		return nil
+	}
+
+	linked, err := uc.dbRepo.HasIdentity(ctx, a.ID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !linked {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+		e.AddDetail("password: cannot be blank")
+		return e
+	}
+
+	sessions, err := uc.sessionRepo.GetSessions(ctx, a.ID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	for _, s := range sessions {
+		if s.ID == claims.SessionID && time.Since(s.CreatedAt) <= recentLogin {
+			return nil
+		}
+	}
+
+	e := errshttp.New(errshttp.Unauthenticated, "Recent login is required")
+	e.AddDetail(fmt.Sprintf("login: login through provider again, then retry within %d minutes", int(recentLogin.Minutes())))
+	return e
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/account"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/result"
 	"github.com/goplateframework/internal/web/webcontext"
 	"github.com/goplateframework/pkg/logger"
 	"github.com/labstack/echo/v4"
@@ -15,8 +17,19 @@ import (
 
 type iUsecase interface {
 	Register(ctx context.Context, na *account.NewAccouuntDTO) (*account.AccountDTO, error)
-	ChangePassword(ctx context.Context, cp *account.ChangePasswordDTO, email string) error
+	ChangePassword(ctx context.Context, cp *account.ChangePasswordDTO, accountID uuid.UUID) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, rp *account.ResetPasswordDTO) error
 	Me(ctx context.Context, accountID uuid.UUID) (*account.AccountDTO, error)
+	UpdateMe(ctx context.Context, up *account.UpdateProfileDTO, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error)
+	DeleteMe(ctx context.Context, da *account.DeleteAccountDTO, claims *tokenutil.AccessTokenClaims) error
+	GetAll(ctx context.Context, qp *QueryParams) (*result.Result[account.AccountDTO], error)
+	ChangeRole(ctx context.Context, id uuid.UUID, role string, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error)
+	Suspend(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error)
+	Reactivate(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*account.AccountDTO, error)
+	ForceLogout(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
 }
 
 type controller struct {
@@ -79,7 +92,97 @@ func (con *controller) changePassword(c echo.Context) error {
 		return e
 	}
 
-	if err := con.accountUC.ChangePassword(c.Request().Context(), dto, claims.Email); err != nil {
+	if err := con.accountUC.ChangePassword(c.Request().Context(), dto, claims.AccountID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) verifyEmail(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		e := errshttp.New(errshttp.InvalidArgument, "Verification token is missing")
+		e.AddDetail("token: expected token query param")
+		return e
+	}
+
+	if err := con.accountUC.VerifyEmail(c.Request().Context(), token); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) resendVerification(c echo.Context) error {
+	dto := new(account.ResendVerificationDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	if err := con.accountUC.ResendVerification(c.Request().Context(), dto.Email); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) forgotPassword(c echo.Context) error {
+	dto := new(account.ForgotPasswordDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	if err := con.accountUC.ForgotPassword(c.Request().Context(), dto.Email); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) resetPassword(c echo.Context) error {
+	dto := new(account.ResetPasswordDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	if err := con.accountUC.ResetPassword(c.Request().Context(), dto); err != nil {
 		return err
 	}
 
@@ -101,3 +204,173 @@ func (con *controller) me(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, a)
 }
+
+func (con *controller) updateMe(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+	if claims == nil {
+		e := errshttp.New(errshttp.Unauthenticated, "Could not give access to this resource")
+		e.AddDetail("token: access_token is missing")
+		return e
+	}
+
+	dto := new(account.UpdateProfileDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	a, err := con.accountUC.UpdateMe(c.Request().Context(), dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) deleteMe(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+	if claims == nil {
+		e := errshttp.New(errshttp.Unauthenticated, "Could not give access to this resource")
+		e.AddDetail("token: access_token is missing")
+		return e
+	}
+
+	dto := new(account.DeleteAccountDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	if err := con.accountUC.DeleteMe(c.Request().Context(), dto, claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	qp, err := getQueryParams(c).Parse()
+
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
+		e.AddDetail(err.Error())
+		return e
+	}
+
+	a, err := con.accountUC.GetAll(c.Request().Context(), qp)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) changeRole(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	dto := new(account.ChangeRoleDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	a, err := con.accountUC.ChangeRole(c.Request().Context(), id, dto.Role, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) suspend(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	a, err := con.accountUC.Suspend(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) reactivate(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	a, err := con.accountUC.Reactivate(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) forceLogout(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.accountUC.ForceLogout(c.Request().Context(), id, claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
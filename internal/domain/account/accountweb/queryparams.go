@@ -0,0 +1,121 @@
+package accountweb
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/web/queryparams"
+	"github.com/labstack/echo/v4"
+)
+
+// Supported query params for admin account listing
+type UnparsedQueryParams struct {
+	page         string
+	size         string
+	orderBy      string
+	role         string
+	email        string
+	createdAfter string // date, YYYY-MM-DD
+	createdUntil string // date, YYYY-MM-DD
+}
+
+func getQueryParams(c echo.Context) *UnparsedQueryParams {
+	return &UnparsedQueryParams{
+		page:         c.QueryParam("page"),
+		size:         c.QueryParam("size"),
+		orderBy:      c.QueryParam("order_by"),
+		role:         c.QueryParam("role"),
+		email:        c.QueryParam("email"),
+		createdAfter: c.QueryParam("created_after"),
+		createdUntil: c.QueryParam("created_until"),
+	}
+}
+
+// Populated query params to send to repository
+type QueryParams struct {
+	Page    *queryparams.Page
+	OrderBy *queryparams.OrderBy
+	Filter  struct {
+		Role         string
+		Email        string
+		CreatedAfter *time.Time
+		CreatedUntil *time.Time // exclusive, the day after the requested date
+	}
+}
+
+func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
+	qp := new(QueryParams)
+
+	if err := uqp.setPage(qp); err != nil {
+		return nil, err
+	}
+
+	if err := uqp.setOrderBy(qp); err != nil {
+		return nil, err
+	}
+
+	if err := uqp.setFilter(qp); err != nil {
+		return nil, err
+	}
+
+	return qp, nil
+}
+
+func (uqp *UnparsedQueryParams) setPage(qp *QueryParams) error {
+	page, err := queryparams.ParsePage(uqp.page, uqp.size)
+	if err != nil {
+		return err
+	}
+
+	qp.Page = page
+	return nil
+}
+
+var allowedOrderByFields = []string{"email", "firstname", "role", "created_at"}
+
+func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
+	defaultOrderBy := queryparams.NewOrderBy(
+		"created_at",
+		queryparams.DescOrder,
+	)
+
+	orderBy, err := queryparams.ParseOrderBy(allowedOrderByFields, uqp.orderBy, defaultOrderBy)
+	if err != nil {
+		return err
+	}
+
+	qp.OrderBy = orderBy
+	return nil
+}
+
+var roleEnums = []string{account.RoleUser, account.RoleAdmin, account.RoleSuperadmin}
+
+func (uqp *UnparsedQueryParams) setFilter(qp *QueryParams) error {
+	if uqp.role != "" && !slices.Contains(roleEnums, uqp.role) {
+		return fmt.Errorf("role: must be either %s, %s or %s", account.RoleUser, account.RoleAdmin, account.RoleSuperadmin)
+	}
+
+	if uqp.createdAfter != "" {
+		t, err := time.Parse(time.DateOnly, uqp.createdAfter)
+		if err != nil {
+			return fmt.Errorf("created_after: expected date format YYYY-MM-DD")
+		}
+		qp.Filter.CreatedAfter = &t
+	}
+
+	if uqp.createdUntil != "" {
+		t, err := time.Parse(time.DateOnly, uqp.createdUntil)
+		if err != nil {
+			return fmt.Errorf("created_until: expected date format YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		qp.Filter.CreatedUntil = &t
+	}
+
+	qp.Filter.Role = uqp.role
+	qp.Filter.Email = uqp.email
+
+	return nil
+}
@@ -2,6 +2,7 @@ package accountweb
 
 import (
 	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
 	"github.com/goplateframework/pkg/logger"
 )
 
@@ -16,5 +17,18 @@ func Route(web *web.Web, opts *Options) {
 	g := web.Echo.Group("/api/v1/account")
 	g.POST("/register", con.register)
 	g.PUT("/change-password", con.changePassword, web.Mid.Authenticated)
+	g.GET("/verify", con.verifyEmail)
+	g.POST("/verify/resend", con.resendVerification)
+	g.POST("/forgot-password", con.forgotPassword)
+	g.POST("/reset-password", con.resetPassword)
 	g.GET("/me", con.me, web.Mid.Authenticated)
+	g.PATCH("/me", con.updateMe, web.Mid.Authenticated)
+	g.DELETE("/me", con.deleteMe, web.Mid.Authenticated)
+
+	admin := web.Echo.Group("/api/v1/admin/accounts", web.Mid.Authenticated, web.Mid.RequireRole(middlewares.SuperadminRoles...))
+	admin.GET("", con.getAll)
+	admin.PUT("/:id/role", con.changeRole)
+	admin.POST("/:id/suspend", con.suspend)
+	admin.POST("/:id/reactivate", con.reactivate)
+	admin.POST("/:id/logout", con.forceLogout)
 }
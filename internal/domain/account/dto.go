@@ -9,6 +9,13 @@ import (
 	"github.com/goplateframework/internal/sdk/validate"
 )
 
+// roles which are available on accounts table, see role enum on migration
+const (
+	RoleUser       = "user"
+	RoleAdmin      = "admin"
+	RoleSuperadmin = "superadmin"
+)
+
 type AccountDTO struct {
 	ID        uuid.UUID `json:"id"`
 	Firstname string    `json:"firstname"`
@@ -19,6 +26,57 @@ type AccountDTO struct {
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	SuspendedAt     *time.Time `json:"suspended_at"`
+}
+
+var Roles = []interface{}{RoleUser, RoleAdmin, RoleSuperadmin}
+
+// ChangeRoleDTO is what superadmin should send to change role of an account
+type ChangeRoleDTO struct {
+	Role string `json:"role"`
+}
+
+func (d ChangeRoleDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Role, validation.Required, validation.In(Roles...)),
+	)
+}
+
+// UpdateProfileDTO is what client should send to update its own profile, empty field is left unchanged.
+// Changing email is confirmed like deleting account, then the new email requires to be verified again
+type UpdateProfileDTO struct {
+	Firstname string `json:"firstname"`
+	Lastname  string `json:"lastname"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+	Password  string `json:"password"`
+}
+
+func (d UpdateProfileDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Firstname, validation.Length(1, 30)),
+		validation.Field(&d.Lastname, validation.Length(1, 30)),
+		validation.Field(&d.Email, is.Email),
+		validation.Field(&d.Phone, validation.When(d.Phone != "", validate.Phone)),
+		validation.Field(&d.Password, validation.Length(0, 72)),
+	)
+}
+
+// DeleteAccountDTO confirms deletion of own account by its password. Account which is linked to a provider
+// could leave password empty, since logging in again through the provider confirms it as well
+type DeleteAccountDTO struct {
+	Password string `json:"password"`
+}
+
+func (d DeleteAccountDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Password, validation.Length(0, 72)),
+	)
 }
 
 type NewAccouuntDTO struct {
@@ -52,3 +110,38 @@ func (d ChangePasswordDTO) Validate() error {
 		validation.Field(&d.NewPassword, validation.Required),
 	)
 }
+
+type ForgotPasswordDTO struct {
+	Email string `json:"email"`
+}
+
+func (d ForgotPasswordDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Email, validation.Required, is.Email),
+	)
+}
+
+type ResendVerificationDTO struct {
+	Email string `json:"email"`
+}
+
+func (d ResendVerificationDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Email, validation.Required, is.Email),
+	)
+}
+
+type ResetPasswordDTO struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (d ResetPasswordDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Token, validation.Required),
+		validation.Field(&d.NewPassword, validation.Required),
+	)
+}
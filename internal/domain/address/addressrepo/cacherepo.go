@@ -0,0 +1,54 @@
+package addressrepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/address"
+	"github.com/goplateframework/pkg/cacheaside"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+// cachedRepository invalidates cached entries of other repositories which show an address, whenever it changes
+type cachedRepository struct {
+	*repository
+	client *redis.Client
+	log    *logger.Log
+}
+
+func NewCachedDB(db *sqlx.DB, client *redis.Client, log *logger.Log) *cachedRepository {
+	return &cachedRepository{NewDB(db), client, log}
+}
+
+func (repo *cachedRepository) Update(ctx context.Context, na *address.AddressDTO) error {
+	if err := repo.repository.Update(ctx, na); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, AddressTag(na.ID))
+	return nil
+}
+
+func (repo *cachedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := repo.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, AddressTag(id))
+	return nil
+}
+
+// invalidate only logs failures, since the write has already been committed and cached entries expire on their own
+func (repo *cachedRepository) invalidate(ctx context.Context, tags ...string) {
+	if err := cacheaside.InvalidateTags(ctx, repo.client, tags...); err != nil {
+		repo.log.Errorf("failed to invalidate cache tags %v, %v", tags, err)
+	}
+}
+
+// AddressTag tags every cached entry which shows the address
+func AddressTag(id uuid.UUID) string {
+	return fmt.Sprintf("address:%s", id.String())
+}
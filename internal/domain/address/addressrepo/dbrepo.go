@@ -19,9 +19,9 @@ func NewDB(db *sqlx.DB) *repository {
 func (dbrepo *repository) Create(ctx context.Context, a *address.AddressDTO) error {
 	q := `
 	INSERT INTO addresses 
-		(id, street, city, province, postal_code, created_at, updated_at)
+		(id, street, city, province, postal_code, latitude, longitude, created_at, updated_at)
 	VALUES
-		(:id, :street, :city, :province, :postal_code, :created_at, :updated_at)
+		(:id, :street, :city, :province, :postal_code, :latitude, :longitude, :created_at, :updated_at)
 	`
 
 	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(a))
@@ -53,7 +53,9 @@ func (dbrepo *repository) Update(ctx context.Context, na *address.AddressDTO) er
 		street = :street,
 		city = :city,
 		province = :province,
-		postal_code = :postal_code
+		postal_code = :postal_code,
+		latitude = :latitude,
+		longitude = :longitude,
 		updated_at = :updated_at
 	WHERE id = :id
 	`
@@ -13,6 +13,8 @@ type Model struct {
 	City       string    `db:"city"`
 	Province   string    `db:"province"`
 	PostalCode string    `db:"postal_code"`
+	Latitude   *float64  `db:"latitude"`
+	Longitude  *float64  `db:"longitude"`
 	CreatedAt  time.Time `db:"created_at"`
 	UpdatedAt  time.Time `db:"updated_at"`
 }
@@ -24,6 +26,8 @@ func intoModel(a *address.AddressDTO) *Model {
 		City:       a.City,
 		Province:   a.Province,
 		PostalCode: a.PostalCode,
+		Latitude:   a.Latitude,
+		Longitude:  a.Longitude,
 		CreatedAt:  a.CreatedAt,
 		UpdatedAt:  a.UpdatedAt,
 	}
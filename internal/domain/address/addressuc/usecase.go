@@ -41,6 +41,8 @@ func (uc *Usecase) Create(ctx context.Context, na *address.NewAddressDTO) (*addr
 		City:       na.City,
 		Province:   na.Province,
 		PostalCode: na.PostalCode,
+		Latitude:   na.Latitude,
+		Longitude:  na.Longitude,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -75,6 +77,8 @@ func (uc *Usecase) Update(ctx context.Context, na *address.NewAddressDTO, id uui
 		City:       na.City,
 		Province:   na.Province,
 		PostalCode: na.PostalCode,
+		Latitude:   na.Latitude,
+		Longitude:  na.Longitude,
 		UpdatedAt:  time.Now(),
 	}
 
@@ -2,6 +2,7 @@ package addressweb
 
 import (
 	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
 	"github.com/goplateframework/pkg/logger"
 )
 
@@ -13,6 +14,6 @@ type Options struct {
 func Route(web *web.Web, opts *Options) {
 	con := newController(opts.AddressUC, opts.Log)
 
-	g := web.Echo.Group("/api/v1/address", web.Mid.Authenticated)
+	g := web.Echo.Group("/api/v1/address", web.Mid.Authenticated, web.Mid.RequireRole(middlewares.AdminRoles...))
 	g.PUT("/:id", con.update)
 }
@@ -14,16 +14,20 @@ type AddressDTO struct {
 	City       string    `json:"city"`
 	Province   string    `json:"province"`
 	PostalCode string    `json:"postal_code"`
+	Latitude   *float64  `json:"latitude"` // nil whenever address has not been pinned on map
+	Longitude  *float64  `json:"longitude"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
-// NewAddressDTO is what client should send to create new address
+// NewAddressDTO is what client should send to create new address, coordinates are optional yet come in pair
 type NewAddressDTO struct {
-	Street     string `json:"street"`
-	City       string `json:"city"`
-	Province   string `json:"province"`
-	PostalCode string `json:"postal_code"`
+	Street     string   `json:"street"`
+	City       string   `json:"city"`
+	Province   string   `json:"province"`
+	PostalCode string   `json:"postal_code"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
 }
 
 func (n NewAddressDTO) Validate() error {
@@ -33,5 +37,23 @@ func (n NewAddressDTO) Validate() error {
 		validation.Field(&n.City, validation.Required, validation.Length(1, 50)),
 		validation.Field(&n.Province, validation.Required, validation.Length(1, 50)),
 		validation.Field(&n.PostalCode, validation.Required, validation.Length(1, 10)),
+		validation.Field(&n.Latitude, latitudeRules(n.Longitude)...),
+		validation.Field(&n.Longitude, longitudeRules(n.Latitude)...),
 	)
 }
+
+// ValidateCoordinates makes sure coordinates of an address come in pair and within their range
+func ValidateCoordinates(lat, lng *float64) error {
+	return validation.Errors{
+		"latitude":  validation.Validate(lat, latitudeRules(lng)...),
+		"longitude": validation.Validate(lng, longitudeRules(lat)...),
+	}.Filter()
+}
+
+func latitudeRules(lng *float64) []validation.Rule {
+	return []validation.Rule{validation.When(lng != nil, validation.NotNil), validation.Min(-90.0), validation.Max(90.0)}
+}
+
+func longitudeRules(lat *float64) []validation.Rule {
+	return []validation.Rule{validation.When(lat != nil, validation.NotNil), validation.Min(-180.0), validation.Max(180.0)}
+}
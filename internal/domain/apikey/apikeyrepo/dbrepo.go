@@ -0,0 +1,109 @@
+package apikeyrepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/apikey"
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+func (dbrepo *repository) Create(ctx context.Context, k *apikey.APIKeyDTO) error {
+	q := `
+	INSERT INTO api_keys
+		(id, account_id, name, prefix, key_hash, scopes, expires_at, created_at, updated_at)
+	VALUES
+		(:id, :account_id, :name, :prefix, :key_hash, :scopes, :expires_at, :created_at, :updated_at)`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(k))
+	return err
+}
+
+func (dbrepo *repository) GetAll(ctx context.Context, accountID uuid.UUID) ([]apikey.APIKeyDTO, error) {
+	q := `
+	SELECT * FROM api_keys
+	WHERE account_id = $1
+	ORDER BY created_at DESC`
+
+	rows, err := dbrepo.QueryxContext(ctx, q, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []apikey.APIKeyDTO
+	for rows.Next() {
+		k := new(Model)
+		if err := rows.StructScan(k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k.intoDTO())
+	}
+
+	return keys, nil
+}
+
+// GetByPrefix returns an api key along with its owner, sql.ErrNoRows is returned when the prefix does not exist
+func (dbrepo *repository) GetByPrefix(ctx context.Context, prefix string) (*apikey.OwnedAPIKeyDTO, error) {
+	k := new(ModelWithOwner)
+
+	q := `
+	SELECT
+		k.*, a.email, a.role
+	FROM
+		api_keys k
+	INNER JOIN accounts a
+		ON k.account_id = a.id
+	WHERE k.prefix = $1 AND a.deleted_at IS NULL AND a.suspended_at IS NULL
+	LIMIT 1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, prefix).StructScan(k); err != nil {
+		return nil, err
+	}
+
+	return k.intoDTO(), nil
+}
+
+// Revoke stamps revocation time on an api key of an account, sql.ErrNoRows is returned when nothing was revoked
+func (dbrepo *repository) Revoke(ctx context.Context, id, accountID uuid.UUID) error {
+	q := `
+	UPDATE api_keys
+	SET revoked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND account_id = $2 AND revoked_at IS NULL`
+
+	res, err := dbrepo.ExecContext(ctx, q, id, accountID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// TouchLastUsed records usage time of an api key, at most once a minute to keep writes low on busy keys
+func (dbrepo *repository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	q := `
+	UPDATE api_keys
+	SET last_used_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')`
+
+	_, err := dbrepo.ExecContext(ctx, q, id)
+	return err
+}
@@ -0,0 +1,69 @@
+package apikeyrepo
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/apikey"
+)
+
+type Model struct {
+	ID         uuid.UUID  `db:"id"`
+	AccountID  uuid.UUID  `db:"account_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     string     `db:"scopes"` // space separated
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+}
+
+func (m *Model) intoDTO() *apikey.APIKeyDTO {
+	return &apikey.APIKeyDTO{
+		ID:         m.ID,
+		AccountID:  m.AccountID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		KeyHash:    m.KeyHash,
+		Scopes:     strings.Fields(m.Scopes),
+		ExpiresAt:  m.ExpiresAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func intoModel(k *apikey.APIKeyDTO) *Model {
+	return &Model{
+		ID:         k.ID,
+		AccountID:  k.AccountID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		Scopes:     strings.Join(k.Scopes, " "),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+		UpdatedAt:  k.UpdatedAt,
+	}
+}
+
+type ModelWithOwner struct {
+	Model
+	Email string `db:"email"`
+	Role  string `db:"role"`
+}
+
+func (m *ModelWithOwner) intoDTO() *apikey.OwnedAPIKeyDTO {
+	return &apikey.OwnedAPIKeyDTO{
+		APIKeyDTO: *m.Model.intoDTO(),
+		Email:     m.Email,
+		Role:      m.Role,
+	}
+}
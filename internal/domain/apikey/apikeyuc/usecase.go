@@ -0,0 +1,187 @@
+package apikeyuc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/apikey"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/pkg/logger"
+)
+
+// every key looks like gpk_<prefix>_<secret>, prefix identifies the key while secret proves its ownership
+const keyPrefix = "gpk"
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	Create(ctx context.Context, k *apikey.APIKeyDTO) error
+	GetAll(ctx context.Context, accountID uuid.UUID) ([]apikey.APIKeyDTO, error)
+	GetByPrefix(ctx context.Context, prefix string) (*apikey.OwnedAPIKeyDTO, error)
+	Revoke(ctx context.Context, id, accountID uuid.UUID) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type Usecase struct {
+	conf *config.Config
+	log  *logger.Log
+	repo iRepository
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository) *Usecase {
+	return &Usecase{
+		conf: conf,
+		log:  log,
+		repo: repo,
+	}
+}
+
+// Create issues an api key which acts on behalf of the account on given claims, the key is only returned once.
+// Write scopes could only be granted by accounts which are allowed to write themselves
+func (uc *Usecase) Create(ctx context.Context, na *apikey.NewAPIKeyDTO, claims *tokenutil.AccessTokenClaims) (*apikey.CreatedAPIKeyDTO, error) {
+	if claims.Role == account.RoleUser {
+		for _, s := range na.Scopes {
+			if slices.Contains(apikey.WriteScopes, s) {
+				e := errshttp.New(errshttp.PermissionDenied, "Could not grant this scope")
+				e.AddDetail(fmt.Sprintf("scopes: %s could not be granted by %s", strings.ReplaceAll(s, ":", " "), claims.Role))
+				return nil, e
+			}
+		}
+	}
+
+	prefix, secret, err := generateKey()
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate api key")
+	}
+
+	scopes := slices.Clone(na.Scopes)
+	slices.Sort(scopes)
+
+	now := time.Now()
+	k := &apikey.APIKeyDTO{
+		ID:        uuid.New(),
+		AccountID: claims.AccountID,
+		Name:      na.Name,
+		Prefix:    prefix,
+		Scopes:    slices.Compact(scopes),
+		ExpiresAt: na.ExpiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	key := fmt.Sprintf("%s_%s_%s", keyPrefix, prefix, secret)
+	k.KeyHash = tokenutil.HashOpaque(key)
+
+	if err := uc.repo.Create(ctx, k); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return &apikey.CreatedAPIKeyDTO{APIKeyDTO: *k, Key: key}, nil
+}
+
+func (uc *Usecase) GetAll(ctx context.Context, claims *tokenutil.AccessTokenClaims) ([]apikey.APIKeyDTO, error) {
+	keys, err := uc.repo.GetAll(ctx, claims.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if keys == nil {
+		keys = []apikey.APIKeyDTO{}
+	}
+
+	return keys, nil
+}
+
+func (uc *Usecase) Revoke(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	if err := uc.repo.Revoke(ctx, id, claims.AccountID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			e := errshttp.New(errshttp.NotFound, "API key could not be found")
+			e.AddDetail(fmt.Sprintf("id: active api key with id %s not found", id))
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// Authenticate resolves given api key into claims equivalent to an access token of its owner, limited to its scopes
+func (uc *Usecase) Authenticate(ctx context.Context, key string) (*tokenutil.AccessTokenClaims, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return nil, invalidKeyError()
+	}
+
+	k, err := uc.repo.GetByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalidKeyError()
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(k.KeyHash), []byte(tokenutil.HashOpaque(key))) != 1 {
+		return nil, invalidKeyError()
+	}
+
+	if k.RevokedAt != nil {
+		e := errshttp.New(errshttp.Unauthenticated, "API key has been revoked")
+		e.AddDetail("key: X-API-Key has been revoked")
+		return nil, e
+	}
+
+	if k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now()) {
+		e := errshttp.New(errshttp.Unauthenticated, "API key has expired")
+		e.AddDetail("key: X-API-Key has expired")
+		return nil, e
+	}
+
+	go func() {
+		if err := uc.repo.TouchLastUsed(context.Background(), k.ID); err != nil {
+			uc.log.Errorf("failed to record last usage of api key %s, %v", k.ID, err)
+		}
+	}()
+
+	return &tokenutil.AccessTokenClaims{
+		AccessTokenPayload: tokenutil.AccessTokenPayload{
+			Email:     k.Email,
+			Role:      k.Role,
+			AccountID: k.AccountID,
+			Scopes:    k.Scopes,
+		},
+	}, nil
+}
+
+func generateKey() (string, string, error) {
+	prefix := make([]byte, 6)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", "", err
+	}
+
+	// url-safe base64 could contain underscore, which separates parts of the key
+	secret, _, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(prefix), strings.ReplaceAll(secret, "_", "-"), nil
+}
+
+func invalidKeyError() error {
+	e := errshttp.New(errshttp.Unauthenticated, "Invalid API key")
+	e.AddDetail("key: X-API-Key header is invalid")
+	return e
+}
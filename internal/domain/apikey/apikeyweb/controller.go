@@ -0,0 +1,87 @@
+package apikeyweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/apikey"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// required usecase methods which this controller needs to operate the business logic
+type iUsecase interface {
+	Create(ctx context.Context, na *apikey.NewAPIKeyDTO, claims *tokenutil.AccessTokenClaims) (*apikey.CreatedAPIKeyDTO, error)
+	GetAll(ctx context.Context, claims *tokenutil.AccessTokenClaims) ([]apikey.APIKeyDTO, error)
+	Revoke(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+}
+
+type controller struct {
+	apiKeyUC iUsecase
+	log      *logger.Log
+}
+
+func newController(apiKeyUC iUsecase, log *logger.Log) *controller {
+	return &controller{apiKeyUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	na := new(apikey.NewAPIKeyDTO)
+
+	if err := c.Bind(na); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := na.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	k, err := con.apiKeyUC.Create(c.Request().Context(), na, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, k)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	keys, err := con.apiKeyUC.GetAll(c.Request().Context(), claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (con *controller) revoke(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "API key id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.apiKeyUC.Revoke(c.Request().Context(), id, claims); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,20 @@
+package apikeyweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log      *logger.Log
+	APIKeyUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.APIKeyUC, opts.Log)
+
+	g := web.Echo.Group("/api/v1/api-keys", web.Mid.Authenticated)
+	g.POST("", con.create)
+	g.GET("", con.getAll)
+	g.DELETE("/:id", con.revoke)
+}
@@ -0,0 +1,78 @@
+package apikey
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+)
+
+// scopes which could be granted to an api key, formatted as <resource>:<action>
+const (
+	ScopeMenuRead    = "menu:read"
+	ScopeMenuWrite   = "menu:write"
+	ScopeOutletRead  = "outlet:read"
+	ScopeOutletWrite = "outlet:write"
+)
+
+var (
+	Scopes      = []string{ScopeMenuRead, ScopeMenuWrite, ScopeOutletRead, ScopeOutletWrite}
+	WriteScopes = []string{ScopeMenuWrite, ScopeOutletWrite}
+)
+
+// APIKeyDTO is what we send to client, the key itself is never stored so only its prefix is shown
+type APIKeyDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	AccountID  uuid.UUID  `json:"account_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// CreatedAPIKeyDTO is only sent once on creation, since it contains the key
+type CreatedAPIKeyDTO struct {
+	APIKeyDTO
+	Key string `json:"key"`
+}
+
+// OwnedAPIKeyDTO is an api key along with role and email of its owner, which are used to build claims
+type OwnedAPIKeyDTO struct {
+	APIKeyDTO
+	Email string
+	Role  string
+}
+
+// NewAPIKeyDTO is what client should send to create an api key
+type NewAPIKeyDTO struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (na NewAPIKeyDTO) Validate() error {
+	return validation.ValidateStruct(&na,
+		validation.Field(&na.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&na.Scopes, validation.Required, validation.Each(validation.In(toAny(Scopes)...))),
+		validation.Field(&na.ExpiresAt, validation.By(func(value interface{}) error {
+			if t, _ := value.(*time.Time); t != nil && t.Before(time.Now()) {
+				return errors.New("must be in the future")
+			}
+			return nil
+		})),
+	)
+}
+
+func toAny(s []string) []interface{} {
+	res := make([]interface{}, len(s))
+	for i, v := range s {
+		res[i] = v
+	}
+	return res
+}
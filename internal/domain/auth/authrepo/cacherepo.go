@@ -2,12 +2,44 @@ package authrepo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/bytedance/sonic"
 	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/auth"
 	"github.com/redis/go-redis/v9"
 )
 
+var (
+	// ErrTokenReused is returned whenever an already rotated refresh token is presented again
+	ErrTokenReused = errors.New("refresh token reused")
+	// ErrSessionRevoked is returned whenever session of the refresh token is revoked or expired
+	ErrSessionRevoked = errors.New("session revoked")
+)
+
+// rotateScript swaps current refresh token id of a session only when presented token id is the current one,
+// presenting any other token id of the session means it was stolen and reused, so the whole session is revoked
+//
+// KEYS: refresh token family, session, account sessions
+// ARGV: presented token id, next token id, expiration in milliseconds, last seen timestamp, session id
+var rotateScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1], KEYS[2])
+	redis.call('SREM', KEYS[3], ARGV[5])
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+redis.call('HSET', KEYS[2], 'last_seen_at', ARGV[4])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return 1
+`)
+
 type Cache struct {
 	*redis.Client
 }
@@ -21,18 +53,268 @@ func (c *Cache) AddAccessTokenToBlacklist(ctx context.Context, token string, exp
 	return c.Set(ctx, token, token, exp).Err()
 }
 
-// stored refresh token using account id as key on redis
-func (c *Cache) AddRefreshTokenToBlacklist(ctx context.Context, accountID uuid.UUID, token string, exp time.Duration) error {
-	return c.Set(ctx, accountID.String(), token, exp).Err()
+// CreateSession stores session metadata, along with id of the first refresh token issued within the session.
+// Session lives as long as its latest refresh token
+func (c *Cache) CreateSession(ctx context.Context, s *auth.SessionDTO, tokenID string, exp time.Duration) error {
+	_, err := c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, getFamilyKey(s.ID), tokenID, exp)
+		pipe.HSet(ctx, getSessionKey(s.ID), map[string]any{
+			"account_id":   s.AccountID.String(),
+			"user_agent":   s.UserAgent,
+			"ip":           s.IP,
+			"created_at":   s.CreatedAt.Format(time.RFC3339),
+			"last_seen_at": s.LastSeenAt.Format(time.RFC3339),
+		})
+		pipe.Expire(ctx, getSessionKey(s.ID), exp)
+		pipe.SAdd(ctx, getAccountSessionsKey(s.AccountID), s.ID.String())
+		pipe.Expire(ctx, getAccountSessionsKey(s.AccountID), exp)
+		return nil
+	})
+
+	return err
 }
 
-// remove existing refresh token from redis using account id to make redis clean
-func (c *Cache) RemoveRefreshTokenFromBlacklist(ctx context.Context, accountID uuid.UUID) error {
-	_, err := c.Get(ctx, accountID.String()).Result()
+// RotateSession replaces current refresh token id of a session with the next one
+func (c *Cache) RotateSession(ctx context.Context, accountID, sessionID uuid.UUID, tokenID, nextTokenID string, exp time.Duration) error {
+	keys := []string{getFamilyKey(sessionID), getSessionKey(sessionID), getAccountSessionsKey(accountID)}
 
-	if err == redis.Nil {
+	res, err := rotateScript.Run(ctx, c, keys,
+		tokenID,
+		nextTokenID,
+		exp.Milliseconds(),
+		time.Now().Format(time.RFC3339),
+		sessionID.String(),
+	).Int()
+	if err != nil {
+		return err
+	}
+
+	switch res {
+	case -1:
+		return ErrSessionRevoked
+	case 0:
+		return ErrTokenReused
+	}
+
+	// account sessions should outlive its latest session
+	return c.Expire(ctx, getAccountSessionsKey(accountID), exp).Err()
+}
+
+// GetSessions returns every active session of an account, expired sessions are cleaned up along the way
+func (c *Cache) GetSessions(ctx context.Context, accountID uuid.UUID) ([]auth.SessionDTO, error) {
+	members, err := c.SMembers(ctx, getAccountSessionsKey(accountID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		if id, err := uuid.Parse(m); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	_, err = c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(ctx, getSessionKey(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []auth.SessionDTO
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			c.SRem(ctx, getAccountSessionsKey(accountID), ids[i].String())
+			continue
+		}
+
+		createdAt, _ := time.Parse(time.RFC3339, fields["created_at"])
+		lastSeenAt, _ := time.Parse(time.RFC3339, fields["last_seen_at"])
+
+		sessions = append(sessions, auth.SessionDTO{
+			ID:         ids[i],
+			AccountID:  accountID,
+			UserAgent:  fields["user_agent"],
+			IP:         fields["ip"],
+			CreatedAt:  createdAt,
+			LastSeenAt: lastSeenAt,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession removes session along with its refresh token family,
+// neither access token nor refresh token of the session could be used anymore
+func (c *Cache) RevokeSession(ctx context.Context, accountID, sessionID uuid.UUID) error {
+	_, err := c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, getFamilyKey(sessionID), getSessionKey(sessionID))
+		pipe.SRem(ctx, getAccountSessionsKey(accountID), sessionID.String())
 		return nil
+	})
+
+	return err
+}
+
+// RevokeAllSessions revokes every session of an account, such as after password reset
+func (c *Cache) RevokeAllSessions(ctx context.Context, accountID uuid.UUID) error {
+	members, err := c.SMembers(ctx, getAccountSessionsKey(accountID)).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, m := range members {
+			if id, err := uuid.Parse(m); err == nil {
+				pipe.Del(ctx, getFamilyKey(id), getSessionKey(id))
+			}
+		}
+		pipe.Del(ctx, getAccountSessionsKey(accountID))
+		return nil
+	})
+
+	return err
+}
+
+func (c *Cache) IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	n, err := c.Exists(ctx, getSessionKey(sessionID)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// CreateMFAChallenge stores a pending login which waits for second factor, keyed by hash of challenge token
+func (c *Cache) CreateMFAChallenge(ctx context.Context, tokenHash string, ch *auth.MFAChallenge, exp time.Duration) error {
+	_, err := c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, getMFAChallengeKey(tokenHash), map[string]any{
+			"account_id": ch.AccountID.String(),
+			"user_agent": ch.Device.UserAgent,
+			"ip":         ch.Device.IP,
+			"enroll":     ch.Enroll,
+			"attempts":   0,
+		})
+		pipe.Expire(ctx, getMFAChallengeKey(tokenHash), exp)
+		return nil
+	})
+
+	return err
+}
+
+// GetMFAChallenge returns pending login of given challenge token hash, nil is returned whenever it does not exist or has expired
+func (c *Cache) GetMFAChallenge(ctx context.Context, tokenHash string) (*auth.MFAChallenge, error) {
+	fields, err := c.HGetAll(ctx, getMFAChallengeKey(tokenHash)).Result()
+	if err != nil {
+		return nil, err
 	}
 
-	return c.Del(ctx, accountID.String()).Err()
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(fields["account_id"])
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.MFAChallenge{
+		AccountID: id,
+		Device: auth.DeviceDTO{
+			UserAgent: fields["user_agent"],
+			IP:        fields["ip"],
+		},
+		Enroll: fields["enroll"] == "1",
+	}, nil
+}
+
+// FailMFAChallenge counts a wrong code against the challenge, the challenge is discarded once attempts reach the limit.
+// It returns true whenever the challenge has been discarded
+func (c *Cache) FailMFAChallenge(ctx context.Context, tokenHash string, maxAttempts int) (bool, error) {
+	attempts, err := c.HIncrBy(ctx, getMFAChallengeKey(tokenHash), "attempts", 1).Result()
+	if err != nil {
+		return false, err
+	}
+
+	if attempts < int64(maxAttempts) {
+		return false, nil
+	}
+
+	return true, c.Del(ctx, getMFAChallengeKey(tokenHash)).Err()
+}
+
+// ConsumeMFAChallenge removes a challenge once it is passed, it returns false whenever the challenge
+// was consumed by another request in the meantime
+func (c *Cache) ConsumeMFAChallenge(ctx context.Context, tokenHash string) (bool, error) {
+	n, err := c.Del(ctx, getMFAChallengeKey(tokenHash)).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// MarkTOTPUsed remembers an accepted totp code of an account throughout its validity, so it could not be replayed.
+// It returns false whenever the code has been used before
+func (c *Cache) MarkTOTPUsed(ctx context.Context, accountID uuid.UUID, code string, exp time.Duration) (bool, error) {
+	return c.SetNX(ctx, getTOTPUsedKey(accountID, code), 1, exp).Result()
+}
+
+// SaveOIDCState stores a pending authorization request, keyed by its state param
+func (c *Cache) SaveOIDCState(ctx context.Context, state string, s *auth.OIDCState, exp time.Duration) error {
+	data, err := sonic.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return c.Set(ctx, getOIDCStateKey(state), data, exp).Err()
+}
+
+// ConsumeOIDCState returns pending authorization request of given state and removes it at once, so it could not be replayed.
+// It returns nil whenever state does not exist or has expired
+func (c *Cache) ConsumeOIDCState(ctx context.Context, state string) (*auth.OIDCState, error) {
+	data, err := c.GetDel(ctx, getOIDCStateKey(state)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(auth.OIDCState)
+	if err := sonic.Unmarshal([]byte(data), s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func getFamilyKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("rt_family:%s", sessionID.String())
+}
+
+func getSessionKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("session:%s", sessionID.String())
+}
+
+func getAccountSessionsKey(accountID uuid.UUID) string {
+	return fmt.Sprintf("sessions:%s", accountID.String())
+}
+
+func getMFAChallengeKey(tokenHash string) string {
+	return fmt.Sprintf("mfa_challenge:%s", tokenHash)
+}
+
+func getTOTPUsedKey(accountID uuid.UUID, code string) string {
+	return fmt.Sprintf("totp_used:%s:%s", accountID.String(), code)
+}
+
+func getOIDCStateKey(state string) string {
+	return fmt.Sprintf("oidc_state:%s", state)
 }
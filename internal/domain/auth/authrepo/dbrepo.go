@@ -0,0 +1,183 @@
+package authrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/auth"
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// GetMFA returns totp enrollment of an account, nil is returned whenever the account has never enrolled
+func (dbrepo *repository) GetMFA(ctx context.Context, accountID uuid.UUID) (*auth.MFADTO, error) {
+	m := new(MFAModel)
+
+	q := `
+	SELECT * FROM account_mfa
+	WHERE account_id = $1
+	LIMIT 1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, accountID).StructScan(m); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return m.intoDTO(), nil
+}
+
+// SaveMFA stores a pending totp enrollment, replacing previous pending secret of the account
+func (dbrepo *repository) SaveMFA(ctx context.Context, m *auth.MFADTO) error {
+	q := `
+	INSERT INTO account_mfa
+		(account_id, secret, enabled_at, created_at, updated_at)
+	VALUES
+		(:account_id, :secret, :enabled_at, :created_at, :updated_at)
+	ON CONFLICT (account_id) DO UPDATE
+	SET
+		secret = EXCLUDED.secret,
+		enabled_at = EXCLUDED.enabled_at,
+		updated_at = EXCLUDED.updated_at`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoMFAModel(m))
+	return err
+}
+
+// EnableMFA activates pending enrollment of an account along with its recovery codes
+func (dbrepo *repository) EnableMFA(ctx context.Context, accountID uuid.UUID, codeHashes []string) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE account_mfa
+	SET enabled_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE account_id = $1`
+
+	if _, err := tx.ExecContext(ctx, q, accountID); err != nil {
+		return err
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, accountID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes discards every recovery code of an account, used or not, in favor of given ones
+func (dbrepo *repository) ReplaceRecoveryCodes(ctx context.Context, accountID uuid.UUID, codeHashes []string) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, accountID, codeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks a recovery code as used, it returns false whenever the code does not exist or is already used
+func (dbrepo *repository) UseRecoveryCode(ctx context.Context, accountID uuid.UUID, codeHash string) (bool, error) {
+	q := `
+	UPDATE account_recovery_codes
+	SET used_at = CURRENT_TIMESTAMP
+	WHERE account_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	res, err := dbrepo.ExecContext(ctx, q, accountID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// DeleteMFA removes totp enrollment of an account along with its recovery codes
+func (dbrepo *repository) DeleteMFA(ctx context.Context, accountID uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM account_recovery_codes WHERE account_id = $1`, accountID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM account_mfa WHERE account_id = $1`, accountID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetIdentityAccountID returns id of an account which is linked to given provider subject, nil is returned whenever none is linked
+func (dbrepo *repository) GetIdentityAccountID(ctx context.Context, provider, subject string) (*uuid.UUID, error) {
+	var id uuid.UUID
+
+	q := `
+	SELECT account_id FROM account_identities
+	WHERE provider = $1 AND subject = $2
+	LIMIT 1`
+
+	if err := dbrepo.GetContext(ctx, &id, q, provider, subject); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+func (dbrepo *repository) CreateIdentity(ctx context.Context, i *auth.IdentityDTO) error {
+	q := `
+	INSERT INTO account_identities
+		(id, account_id, provider, subject, email, created_at, updated_at)
+	VALUES
+		(:id, :account_id, :provider, :subject, :email, :created_at, :updated_at)`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoIdentityModel(i))
+	return err
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, accountID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM account_recovery_codes WHERE account_id = $1`, accountID); err != nil {
+		return err
+	}
+
+	q := `
+	INSERT INTO account_recovery_codes
+		(account_id, code_hash)
+	VALUES
+		($1, $2)`
+
+	for _, h := range codeHashes {
+		if _, err := tx.ExecContext(ctx, q, accountID, h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package authrepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/auth"
+)
+
+type MFAModel struct {
+	AccountID uuid.UUID  `db:"account_id"`
+	Secret    string     `db:"secret"`
+	EnabledAt *time.Time `db:"enabled_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+func (m *MFAModel) intoDTO() *auth.MFADTO {
+	return &auth.MFADTO{
+		AccountID: m.AccountID,
+		Secret:    m.Secret,
+		EnabledAt: m.EnabledAt,
+	}
+}
+
+func intoMFAModel(m *auth.MFADTO) *MFAModel {
+	now := time.Now()
+
+	return &MFAModel{
+		AccountID: m.AccountID,
+		Secret:    m.Secret,
+		EnabledAt: m.EnabledAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+type IdentityModel struct {
+	ID        uuid.UUID `db:"id"`
+	AccountID uuid.UUID `db:"account_id"`
+	Provider  string    `db:"provider"`
+	Subject   string    `db:"subject"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func intoIdentityModel(i *auth.IdentityDTO) *IdentityModel {
+	return &IdentityModel{
+		ID:        i.ID,
+		AccountID: i.AccountID,
+		Provider:  i.Provider,
+		Subject:   i.Subject,
+		Email:     i.Email,
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+	}
+}
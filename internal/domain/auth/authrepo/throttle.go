@@ -0,0 +1,93 @@
+package authrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockouts are remembered for a day, so repeated lockouts keep growing within that period
+const lockoutMemory = 24 * time.Hour
+
+// failureScript counts a failed login of a subject within window, once attempts reach the limit
+// subject is locked out, every consecutive lockout doubles its duration up to max duration
+//
+// KEYS: attempts, lock, lockouts
+// ARGV: max attempts, window in milliseconds, base lockout in milliseconds, max lockout in milliseconds, lockout memory in milliseconds
+// returns lockout duration in milliseconds, or 0 whenever subject is not locked out
+var failureScript = redis.NewScript(`
+local attempts = redis.call('INCR', KEYS[1])
+if attempts == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if attempts < tonumber(ARGV[1]) then
+	return 0
+end
+local lockouts = redis.call('INCR', KEYS[3])
+redis.call('PEXPIRE', KEYS[3], ARGV[5])
+local duration = math.min(tonumber(ARGV[3]) * 2 ^ (lockouts - 1), tonumber(ARGV[4]))
+redis.call('SET', KEYS[2], lockouts, 'PX', math.floor(duration))
+redis.call('DEL', KEYS[1])
+return math.floor(duration)
+`)
+
+// LoginLockRemaining returns the longest remaining lockout among given subjects, 0 means none is locked out
+func (c *Cache) LoginLockRemaining(ctx context.Context, subjects ...string) (time.Duration, error) {
+	cmds := make([]*redis.DurationCmd, len(subjects))
+
+	_, err := c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, s := range subjects {
+			cmds[i] = pipe.PTTL(ctx, getLoginLockKey(s))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var remaining time.Duration
+	for _, cmd := range cmds {
+		if d := cmd.Val(); d > remaining {
+			remaining = d
+		}
+	}
+
+	return remaining, nil
+}
+
+// RecordLoginFailure counts a failed login of given subject, it returns lockout duration whenever the failure locks subject out
+func (c *Cache) RecordLoginFailure(ctx context.Context, subject string, maxAttempts int, window, lockout, maxLockout time.Duration) (time.Duration, error) {
+	keys := []string{getLoginAttemptsKey(subject), getLoginLockKey(subject), getLoginLockoutsKey(subject)}
+
+	ms, err := failureScript.Run(ctx, c, keys,
+		maxAttempts,
+		window.Milliseconds(),
+		lockout.Milliseconds(),
+		maxLockout.Milliseconds(),
+		lockoutMemory.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// ClearLoginFailures forgets every failed login and lockout of given subject
+func (c *Cache) ClearLoginFailures(ctx context.Context, subject string) error {
+	return c.Del(ctx, getLoginAttemptsKey(subject), getLoginLockKey(subject), getLoginLockoutsKey(subject)).Err()
+}
+
+func getLoginAttemptsKey(subject string) string {
+	return fmt.Sprintf("login_attempts:%s", subject)
+}
+
+func getLoginLockKey(subject string) string {
+	return fmt.Sprintf("login_lock:%s", subject)
+}
+
+func getLoginLockoutsKey(subject string) string {
+	return fmt.Sprintf("login_lockouts:%s", subject)
+}
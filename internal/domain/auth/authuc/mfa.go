@@ -0,0 +1,392 @@
+package authuc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/auth"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	mfaMaxAttempts    = 5
+	recoveryCodeCount = 10
+	// totp code is accepted one period before and after current one, so it is remembered as long as that
+	totpUsedExpires = 90 * time.Second
+)
+
+// VerifyMFA completes a login which waits for second factor, using either totp code or recovery code.
+// Whenever the challenge completes an enrollment, recovery codes are returned along with the tokens
+func (uc *Usecase) VerifyMFA(ctx context.Context, challengeToken, code string) (*auth.AuthDTO, error) {
+	tokenHash := tokenutil.HashOpaque(challengeToken)
+
+	ch, err := uc.authCacheRepo.GetMFAChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if ch == nil {
+		return nil, invalidChallengeError()
+	}
+
+	a, err := uc.accountDBRepo.GetOne(ctx, ch.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	m, err := uc.authDBRepo.GetMFA(ctx, a.ID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if m == nil {
+		e := errshttp.New(errshttp.FailedPrecondition, "MFA has not been enrolled")
+		e.AddDetail("challenge_token: enroll using the challenge token first")
+		return nil, e
+	}
+
+	enabled := m.EnabledAt != nil
+
+	ok, err := uc.validateTOTP(ctx, a.ID, m.Secret, code)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	// recovery codes only exist once enrollment is completed
+	if !ok && enabled {
+		if ok, err = uc.authDBRepo.UseRecoveryCode(ctx, a.ID, hashRecoveryCode(code)); err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+	}
+
+	if !ok {
+		discarded, err := uc.authCacheRepo.FailMFAChallenge(ctx, tokenHash, mfaMaxAttempts)
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		if discarded {
+			e := errshttp.New(errshttp.Unauthenticated, "Too many invalid MFA codes")
+			e.AddDetail("challenge_token: challenge has been discarded, login again")
+			return nil, e
+		}
+
+		return nil, invalidCodeError()
+	}
+
+	consumed, err := uc.authCacheRepo.ConsumeMFAChallenge(ctx, tokenHash)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !consumed {
+		return nil, invalidChallengeError()
+	}
+
+	var recoveryCodes []string
+	if !enabled {
+		codes, hashes, err := generateRecoveryCodes()
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Failed to generate recovery codes")
+		}
+
+		if err := uc.authDBRepo.EnableMFA(ctx, a.ID, hashes); err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		recoveryCodes = codes
+	}
+
+	res, err := uc.startSession(ctx, a, &ch.Device)
+	if err != nil {
+		return nil, err
+	}
+	res.RecoveryCodes = recoveryCodes
+
+	return res, nil
+}
+
+// EnrollMFAChallenge starts enrollment of an account which is required to use mfa, but could not login before enrolling
+func (uc *Usecase) EnrollMFAChallenge(ctx context.Context, challengeToken string) (*auth.MFAEnrollmentDTO, error) {
+	ch, err := uc.authCacheRepo.GetMFAChallenge(ctx, tokenutil.HashOpaque(challengeToken))
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if ch == nil {
+		return nil, invalidChallengeError()
+	}
+
+	if !ch.Enroll {
+		return nil, errshttp.New(errshttp.AlreadyExists, "MFA has been enabled")
+	}
+
+	a, err := uc.accountDBRepo.GetOne(ctx, ch.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.enroll(ctx, a)
+}
+
+// EnrollMFA starts enrollment of logged in account, it is not active until ActivateMFA is called with a valid code
+func (uc *Usecase) EnrollMFA(ctx context.Context, atc *tokenutil.AccessTokenClaims) (*auth.MFAEnrollmentDTO, error) {
+	m, err := uc.authDBRepo.GetMFA(ctx, atc.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if m != nil && m.EnabledAt != nil {
+		return nil, errshttp.New(errshttp.AlreadyExists, "MFA has been enabled")
+	}
+
+	a, err := uc.accountDBRepo.GetOne(ctx, atc.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.enroll(ctx, a)
+}
+
+// ActivateMFA completes enrollment of logged in account, recovery codes are only shown at this point
+func (uc *Usecase) ActivateMFA(ctx context.Context, code string, atc *tokenutil.AccessTokenClaims) (*auth.RecoveryCodesDTO, error) {
+	m, err := uc.authDBRepo.GetMFA(ctx, atc.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if m == nil {
+		return nil, errshttp.New(errshttp.FailedPrecondition, "MFA enrollment has not been started")
+	}
+
+	if m.EnabledAt != nil {
+		return nil, errshttp.New(errshttp.AlreadyExists, "MFA has been enabled")
+	}
+
+	ok, err := uc.validateTOTP(ctx, atc.AccountID, m.Secret, code)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !ok {
+		return nil, invalidCodeError()
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate recovery codes")
+	}
+
+	if err := uc.authDBRepo.EnableMFA(ctx, atc.AccountID, hashes); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return &auth.RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of logged in account, given totp code is required
+func (uc *Usecase) RegenerateRecoveryCodes(ctx context.Context, code string, atc *tokenutil.AccessTokenClaims) (*auth.RecoveryCodesDTO, error) {
+	m, err := uc.enabledMFA(ctx, atc.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.validateTOTP(ctx, atc.AccountID, m.Secret, code)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !ok {
+		return nil, invalidCodeError()
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate recovery codes")
+	}
+
+	if err := uc.authDBRepo.ReplaceRecoveryCodes(ctx, atc.AccountID, hashes); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return &auth.RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA removes enrollment of logged in account, unless mfa is mandatory for its role
+func (uc *Usecase) DisableMFA(ctx context.Context, code string, atc *tokenutil.AccessTokenClaims) error {
+	if uc.mfaRequired(atc.Role) {
+		e := errshttp.New(errshttp.PermissionDenied, "MFA is mandatory")
+		e.AddDetail(fmt.Sprintf("role: mfa could not be disabled by %s", atc.Role))
+		return e
+	}
+
+	m, err := uc.enabledMFA(ctx, atc.AccountID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := uc.validateTOTP(ctx, atc.AccountID, m.Secret, code)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !ok {
+		if ok, err = uc.authDBRepo.UseRecoveryCode(ctx, atc.AccountID, hashRecoveryCode(code)); err != nil {
+			return errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+	}
+
+	if !ok {
+		return invalidCodeError()
+	}
+
+	if err := uc.authDBRepo.DeleteMFA(ctx, atc.AccountID); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// mfaChallenge creates a challenge whenever the account has enabled mfa, or its role requires one.
+// nil is returned whenever the login could proceed right away
+func (uc *Usecase) mfaChallenge(ctx context.Context, a *account.AccountDTO, device *auth.DeviceDTO) (*auth.MFAChallengeDTO, error) {
+	m, err := uc.authDBRepo.GetMFA(ctx, a.ID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	enabled := m != nil && m.EnabledAt != nil
+	if !enabled && !uc.mfaRequired(a.Role) {
+		return nil, nil
+	}
+
+	token, tokenHash, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate mfa challenge")
+	}
+
+	ttl := durationOr(uc.conf.MFA.ChallengeTTL, 5*time.Minute)
+	ch := &auth.MFAChallenge{
+		AccountID: a.ID,
+		Device:    *device,
+		Enroll:    !enabled,
+	}
+
+	if err := uc.authCacheRepo.CreateMFAChallenge(ctx, tokenHash, ch, ttl); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to store mfa challenge")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return nil, e
+	}
+
+	return &auth.MFAChallengeDTO{
+		ChallengeToken:     token,
+		EnrollmentRequired: !enabled,
+		ExpiresAt:          time.Now().Add(ttl),
+	}, nil
+}
+
+func (uc *Usecase) mfaRequired(role string) bool {
+	return slices.Contains(uc.conf.MFA.RequiredRoles, role)
+}
+
+func (uc *Usecase) enabledMFA(ctx context.Context, accountID uuid.UUID) (*auth.MFADTO, error) {
+	m, err := uc.authDBRepo.GetMFA(ctx, accountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if m == nil || m.EnabledAt == nil {
+		return nil, errshttp.New(errshttp.FailedPrecondition, "MFA has not been enabled")
+	}
+
+	return m, nil
+}
+
+// enroll generates a new totp secret for an account, which stays pending until its first valid code
+func (uc *Usecase) enroll(ctx context.Context, a *account.AccountDTO) (*auth.MFAEnrollmentDTO, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      uc.conf.MFA.Issuer,
+		AccountName: a.Email,
+	})
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate mfa secret")
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate mfa qr code")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to generate mfa qr code")
+	}
+
+	if err := uc.authDBRepo.SaveMFA(ctx, &auth.MFADTO{AccountID: a.ID, Secret: key.Secret()}); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return &auth.MFAEnrollmentDTO{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// validateTOTP checks given code against secret, a valid code is accepted only once
+func (uc *Usecase) validateTOTP(ctx context.Context, accountID uuid.UUID, secret, code string) (bool, error) {
+	if !totp.Validate(code, secret) {
+		return false, nil
+	}
+
+	return uc.authCacheRepo.MarkTOTPUsed(ctx, accountID, code, totpUsedExpires)
+}
+
+// generateRecoveryCodes creates a set of recovery codes formatted as xxxxx-xxxxx, along with their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+		codes[i] = s[:5] + "-" + s[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes recovery code regardless of its case and separator
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return tokenutil.HashOpaque(normalized)
+}
+
+func invalidChallengeError() error {
+	e := errshttp.New(errshttp.Unauthenticated, "MFA challenge is invalid or has expired")
+	e.AddDetail("challenge_token: login again to get a new challenge")
+	return e
+}
+
+func invalidCodeError() error {
+	e := errshttp.New(errshttp.InvalidCredentials, "MFA code is invalid")
+	e.AddDetail("code: either totp code or recovery code is invalid")
+	return e
+}
@@ -0,0 +1,279 @@
+package authuc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/auth"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/pkg/oidcprovider"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+)
+
+// idTokenClaims are standard claims of id token which are used to link or create an account
+type idTokenClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Nonce         string `json:"nonce"`
+}
+
+// StartOIDC creates authorization request of given provider using pkce, it returns url where client should be redirected
+func (uc *Usecase) StartOIDC(ctx context.Context, provider string) (string, error) {
+	p, err := uc.oidcProvider(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, _, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return "", errshttp.New(errshttp.Internal, "Failed to generate oidc state")
+	}
+
+	nonce, _, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return "", errshttp.New(errshttp.Internal, "Failed to generate oidc nonce")
+	}
+
+	s := &auth.OIDCState{
+		Provider: provider,
+		Nonce:    nonce,
+		Verifier: oauth2.GenerateVerifier(),
+	}
+
+	if err := uc.authCacheRepo.SaveOIDCState(ctx, state, s, durationOr(uc.conf.OIDC.StateTTL, 10*time.Minute)); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to store oidc state")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return "", e
+	}
+
+	return p.OAuth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(s.Verifier)), nil
+}
+
+// CallbackOIDC exchanges authorization code of given provider, then logs in the account which is linked to the identity.
+// Identity is linked to an existing account, or a new account is created, only when provider has verified its email.
+// Like password login, suspended or unverified accounts are refused and a challenge is returned instead of tokens
+// whenever the account has to pass second factor
+func (uc *Usecase) CallbackOIDC(ctx context.Context, provider, code, state string, device *auth.DeviceDTO) (*auth.AuthDTO, *auth.MFAChallengeDTO, error) {
+	s, err := uc.authCacheRepo.ConsumeOIDCState(ctx, state)
+	if err != nil {
+		return nil, nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if s == nil || s.Provider != provider {
+		e := errshttp.New(errshttp.Unauthenticated, "OIDC state is invalid or has expired")
+		e.AddDetail("state: start the authorization again")
+		return nil, nil, e
+	}
+
+	p, err := uc.oidcProvider(ctx, provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := p.OAuth2.Exchange(ctx, code, oauth2.VerifierOption(s.Verifier))
+	if err != nil {
+		e := errshttp.New(errshttp.Unauthenticated, "Failed to exchange authorization code")
+		e.AddDetail(fmt.Sprintf("code: %v", strings.ReplaceAll(err.Error(), ":", ",")))
+		return nil, nil, e
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		e := errshttp.New(errshttp.Unauthenticated, "Provider did not return id token")
+		e.AddDetail("id_token: missing on token response")
+		return nil, nil, e
+	}
+
+	idToken, err := p.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		e := errshttp.New(errshttp.Unauthenticated, "ID token is invalid")
+		e.AddDetail(fmt.Sprintf("id_token: %v", strings.ReplaceAll(err.Error(), ":", ",")))
+		return nil, nil, e
+	}
+
+	claims := new(idTokenClaims)
+	if err := idToken.Claims(claims); err != nil {
+		return nil, nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if claims.Nonce != s.Nonce {
+		e := errshttp.New(errshttp.Unauthenticated, "ID token is invalid")
+		e.AddDetail("id_token: nonce does not match")
+		return nil, nil, e
+	}
+
+	a, err := uc.resolveIdentity(ctx, provider, idToken.Subject, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.loginAllowed(a); err != nil {
+		return nil, nil, err
+	}
+
+	challenge, err := uc.mfaChallenge(ctx, a, device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	res, err := uc.startSession(ctx, a, device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, nil, nil
+}
+
+func (uc *Usecase) oidcProvider(ctx context.Context, provider string) (*oidcprovider.Provider, error) {
+	p, err := uc.oidcProviders.Get(ctx, provider)
+	if err != nil {
+		if errors.Is(err, oidcprovider.ErrUnknownProvider) {
+			e := errshttp.New(errshttp.NotFound, "OIDC provider could not be found")
+			e.AddDetail(fmt.Sprintf("provider: %s is not configured", provider))
+			return nil, e
+		}
+
+		uc.log.Errorf("oidc provider %s is unavailable, %v", provider, err)
+		return nil, errshttp.New(errshttp.Unavailable, "OIDC provider is unavailable")
+	}
+
+	return p, nil
+}
+
+// resolveIdentity returns account which is linked to provider subject, linking or creating one on its first login
+func (uc *Usecase) resolveIdentity(ctx context.Context, provider, subject string, claims *idTokenClaims) (*account.AccountDTO, error) {
+	accountID, err := uc.authDBRepo.GetIdentityAccountID(ctx, provider, subject)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if accountID != nil {
+		a, err := uc.accountDBRepo.GetOne(ctx, *accountID)
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+		return a, nil
+	}
+
+	if claims.Email == "" {
+		e := errshttp.New(errshttp.FailedPrecondition, "Provider did not share email")
+		e.AddDetail("email: email scope is required to sign in")
+		return nil, e
+	}
+
+	a, err := uc.accountDBRepo.GetOneByEmail(ctx, claims.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	// email which is not verified by provider could belong to anyone, linking it would let them take over the account
+	// and claiming it on a new account would keep its real owner from registering
+	if !claims.EmailVerified {
+		if a != nil {
+			e := errshttp.New(errshttp.AlreadyExists, "Email already taken")
+			e.AddDetail("email: login using password, provider has not verified this email")
+			return nil, e
+		}
+
+		e := errshttp.New(errshttp.FailedPrecondition, "Email has not been verified by provider")
+		e.AddDetail("email: verify this email on provider before signing in")
+		return nil, e
+	}
+
+	// local account which has never verified its email could have been registered by anyone, including whoever
+	// still holds its password, so it is only linked once its owner has proven the email
+	if a != nil && a.EmailVerifiedAt == nil {
+		e := errshttp.New(errshttp.AlreadyExists, "Email already taken")
+		e.AddDetail("email: account of this email has not been verified, verify it and reset password before login using provider")
+		return nil, e
+	}
+
+	if a == nil {
+		if a, err = uc.createOIDCAccount(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	identity := &auth.IdentityDTO{
+		ID:        uuid.New(),
+		AccountID: a.ID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     claims.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.authDBRepo.CreateIdentity(ctx, identity); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return a, nil
+}
+
+// createOIDCAccount creates an account out of id token claims whose email has been verified by provider,
+// its password is random so it could only login through the provider until password is reset
+func (uc *Usecase) createOIDCAccount(ctx context.Context, claims *idTokenClaims) (*account.AccountDTO, error) {
+	password, _, err := tokenutil.GenerateOpaque()
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	hashedPass, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Failed to hash password")
+	}
+
+	firstname, lastname := claims.GivenName, claims.FamilyName
+	if firstname == "" {
+		firstname, lastname, _ = strings.Cut(claims.Name, " ")
+	}
+	if firstname == "" {
+		firstname, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	now := time.Now()
+	a := &account.AccountDTO{
+		ID:        uuid.New(),
+		Firstname: truncate(firstname, 50),
+		Lastname:  truncate(lastname, 50),
+		Email:     claims.Email,
+		Password:  string(hashedPass),
+		Role:      account.RoleUser,
+		CreatedAt: now,
+		UpdatedAt: now,
+
+		EmailVerifiedAt: &now,
+	}
+
+	if err := uc.accountDBRepo.Create(ctx, a); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return a, nil
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
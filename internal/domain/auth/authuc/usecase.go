@@ -2,7 +2,11 @@ package authuc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,58 +14,413 @@ import (
 	"github.com/goplateframework/config"
 	"github.com/goplateframework/internal/domain/account"
 	"github.com/goplateframework/internal/domain/auth"
+	"github.com/goplateframework/internal/domain/auth/authrepo"
 	"github.com/goplateframework/internal/sdk/errshttp"
 	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/pkg/logger"
+	"github.com/goplateframework/pkg/oidcprovider"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type iAuthCacheRepo interface {
 	AddAccessTokenToBlacklist(ctx context.Context, token string, exp time.Duration) error
-	AddRefreshTokenToBlacklist(ctx context.Context, accountID uuid.UUID, token string, exp time.Duration) error
-	RemoveRefreshTokenFromBlacklist(ctx context.Context, accountID uuid.UUID) error
+	CreateSession(ctx context.Context, s *auth.SessionDTO, tokenID string, exp time.Duration) error
+	RotateSession(ctx context.Context, accountID, sessionID uuid.UUID, tokenID, nextTokenID string, exp time.Duration) error
+	GetSessions(ctx context.Context, accountID uuid.UUID) ([]auth.SessionDTO, error)
+	RevokeSession(ctx context.Context, accountID, sessionID uuid.UUID) error
+	LoginLockRemaining(ctx context.Context, subjects ...string) (time.Duration, error)
+	RecordLoginFailure(ctx context.Context, subject string, maxAttempts int, window, lockout, maxLockout time.Duration) (time.Duration, error)
+	ClearLoginFailures(ctx context.Context, subject string) error
+	CreateMFAChallenge(ctx context.Context, tokenHash string, ch *auth.MFAChallenge, exp time.Duration) error
+	GetMFAChallenge(ctx context.Context, tokenHash string) (*auth.MFAChallenge, error)
+	FailMFAChallenge(ctx context.Context, tokenHash string, maxAttempts int) (bool, error)
+	ConsumeMFAChallenge(ctx context.Context, tokenHash string) (bool, error)
+	MarkTOTPUsed(ctx context.Context, accountID uuid.UUID, code string, exp time.Duration) (bool, error)
+	SaveOIDCState(ctx context.Context, state string, s *auth.OIDCState, exp time.Duration) error
+	ConsumeOIDCState(ctx context.Context, state string) (*auth.OIDCState, error)
+}
+
+type iAuthDBRepo interface {
+	GetMFA(ctx context.Context, accountID uuid.UUID) (*auth.MFADTO, error)
+	SaveMFA(ctx context.Context, m *auth.MFADTO) error
+	EnableMFA(ctx context.Context, accountID uuid.UUID, codeHashes []string) error
+	ReplaceRecoveryCodes(ctx context.Context, accountID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, accountID uuid.UUID, codeHash string) (bool, error)
+	DeleteMFA(ctx context.Context, accountID uuid.UUID) error
+	GetIdentityAccountID(ctx context.Context, provider, subject string) (*uuid.UUID, error)
+	CreateIdentity(ctx context.Context, i *auth.IdentityDTO) error
 }
 
 type iAccountDBRepo interface {
 	GetOneByEmail(ctx context.Context, email string) (*account.AccountDTO, error)
 	GetOne(ctx context.Context, id uuid.UUID) (*account.AccountDTO, error)
+	Create(ctx context.Context, a *account.AccountDTO) error
+}
+
+type iOIDCProviders interface {
+	Get(ctx context.Context, name string) (*oidcprovider.Provider, error)
 }
 
 type Usecase struct {
 	authCacheRepo iAuthCacheRepo
+	authDBRepo    iAuthDBRepo
 	conf          *config.Config
 	log           *logger.Log
 	accountDBRepo iAccountDBRepo
+	oidcProviders iOIDCProviders
 }
 
-func New(conf *config.Config, log *logger.Log, authCacheRepo iAuthCacheRepo, accountDBRepo iAccountDBRepo) *Usecase {
+func New(conf *config.Config, log *logger.Log, authCacheRepo iAuthCacheRepo, authDBRepo iAuthDBRepo, accountDBRepo iAccountDBRepo, oidcProviders iOIDCProviders) *Usecase {
 	return &Usecase{
 		accountDBRepo: accountDBRepo,
 		authCacheRepo: authCacheRepo,
+		authDBRepo:    authDBRepo,
+		oidcProviders: oidcProviders,
 		conf:          conf,
 		log:           log,
 	}
 }
 
-func (uc *Usecase) Login(ctx context.Context, email, password string) (*auth.AuthDTO, error) {
+// Login authenticates an account by its password. Whenever the account has to pass second factor,
+// a challenge is returned instead of tokens, which should be completed through VerifyMFA
+func (uc *Usecase) Login(ctx context.Context, email, password string, device *auth.DeviceDTO) (*auth.AuthDTO, *auth.MFAChallengeDTO, error) {
+	emailSubject, ipSubject := emailLoginSubject(email), ipLoginSubject(device.IP)
+
+	remaining, err := uc.authCacheRepo.LoginLockRemaining(ctx, emailSubject, ipSubject)
+	if err != nil {
+		return nil, nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if remaining > 0 {
+		return nil, nil, lockedOutError(remaining)
+	}
+
 	a, err := uc.accountDBRepo.GetOneByEmail(ctx, email)
 
 	if err != nil {
-		return nil, errshttp.New(errshttp.InvalidCredentials, "Credentials are invalid, either email and/or password")
+		return nil, nil, uc.loginFailed(ctx, emailSubject, ipSubject)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(a.Password), []byte(password)); err != nil {
-		return nil, errshttp.New(errshttp.InvalidCredentials, "Credentials are invalid, either email and/or password")
+		return nil, nil, uc.loginFailed(ctx, emailSubject, ipSubject)
+	}
+
+	if err := uc.authCacheRepo.ClearLoginFailures(ctx, emailSubject); err != nil {
+		uc.log.Errorf("failed to clear login failures of account %s, %v", a.ID, err)
+	}
+
+	if err := uc.loginAllowed(a); err != nil {
+		return nil, nil, err
+	}
+
+	challenge, err := uc.mfaChallenge(ctx, a, device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if challenge != nil {
+		return nil, challenge, nil
+	}
+
+	res, err := uc.startSession(ctx, a, device)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return res, nil, nil
+}
+
+// startSession starts a new session of an authenticated account, which is a family of rotated refresh tokens
+func (uc *Usecase) startSession(ctx context.Context, a *account.AccountDTO, device *auth.DeviceDTO) (*auth.AuthDTO, error) {
+	if a.SuspendedAt != nil {
+		return nil, suspendedError()
+	}
+
+	now := time.Now()
+	s := &auth.SessionDTO{
+		ID:         uuid.New(),
+		AccountID:  a.ID,
+		UserAgent:  device.UserAgent,
+		IP:         device.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
 	}
 
-	if err := uc.authCacheRepo.RemoveRefreshTokenFromBlacklist(ctx, a.ID); err != nil {
-		e := errshttp.New(errshttp.Internal, "Failed to refresh token from blacklist")
+	rtExp := tokenutil.RefreshTokenExpiry(uc.conf, nil)
+
+	at, rt, rtID, err := uc.generateTokens(a, s.ID, rtExp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.authCacheRepo.CreateSession(ctx, s, rtID, time.Until(rtExp)); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to store session")
 		e.AddDetail(fmt.Sprintf("data: %v", err))
 		return nil, e
 	}
 
+	return &auth.AuthDTO{
+		Account:      a,
+		AccessToken:  at,
+		RefreshToken: rt,
+	}, nil
+}
+
+func (uc *Usecase) Logout(ctx context.Context, accessToken string, rtc *tokenutil.RefreshTokenClaims) error {
+	// session is revoked first, since it keeps the refresh token alive far longer than the access token
+	if err := uc.authCacheRepo.RevokeSession(ctx, rtc.AccountID, rtc.SessionID); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to revoke session")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	// access token is optional, it may have expired already. Only a valid one of the same session is worth blacklisting
+	if accessToken == "" {
+		return nil
+	}
+
+	atc, err := tokenutil.ValidateAccess(uc.conf, accessToken)
+	if err != nil || atc.SessionID != rtc.SessionID {
+		return nil
+	}
+
+	atTime := tokenutil.RemainingTime(&atc.RegisteredClaims)
+	if err := uc.authCacheRepo.AddAccessTokenToBlacklist(ctx, accessToken, atTime); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to add access token from blacklist")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	return nil
+}
+
+// Refresh rotates given refresh token into a new pair of tokens within the same session,
+// presenting a refresh token which has been rotated before revokes the whole session
+func (uc *Usecase) Refresh(ctx context.Context, rtc *tokenutil.RefreshTokenClaims) (*auth.AuthDTO, error) {
+	a, err := uc.accountDBRepo.GetOne(ctx, rtc.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.NotFound, "Something went wrong")
+	}
+
+	if a == nil {
+		e := errshttp.New(errshttp.NotFound, "Account could not be found")
+		e.AddDetail(fmt.Sprintf("data: account with id %s not found", rtc.AccountID))
+		return nil, e
+	}
+
+	if a.SuspendedAt != nil {
+		return nil, suspendedError()
+	}
+
+	// session keeps its original expiration, unless sliding window is enabled
+	rtExp := tokenutil.RefreshTokenExpiry(uc.conf, rtc)
+
+	at, rt, rtID, err := uc.generateTokens(a, rtc.SessionID, rtExp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.authCacheRepo.RotateSession(ctx, a.ID, rtc.SessionID, rtc.ID, rtID, time.Until(rtExp))
+	if err != nil {
+		if errors.Is(err, authrepo.ErrTokenReused) {
+			uc.log.Warnf("refresh token reuse detected, session %s of account %s revoked", rtc.SessionID, rtc.AccountID)
+
+			e := errshttp.New(errshttp.Unauthenticated, "Refresh token has been used before")
+			e.AddDetail("token: refresh_token reuse detected, the session has been revoked")
+			return nil, e
+		}
+
+		if errors.Is(err, authrepo.ErrSessionRevoked) {
+			return nil, errshttp.New(errshttp.Unauthenticated, "User already logged out")
+		}
+
+		e := errshttp.New(errshttp.Internal, "Failed to rotate refresh token")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return nil, e
+	}
+
+	return &auth.AuthDTO{
+		Account:      a,
+		AccessToken:  at,
+		RefreshToken: rt,
+	}, nil
+}
+
+// GetSessions lists every active session of the account, marking the one which performs the request
+func (uc *Usecase) GetSessions(ctx context.Context, atc *tokenutil.AccessTokenClaims) ([]auth.SessionDTO, error) {
+	sessions, err := uc.authCacheRepo.GetSessions(ctx, atc.AccountID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if sessions == nil {
+		sessions = []auth.SessionDTO{}
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == atc.SessionID
+	}
+
+	return sessions, nil
+}
+
+func (uc *Usecase) RevokeSession(ctx context.Context, atc *tokenutil.AccessTokenClaims, sessionID uuid.UUID) error {
+	sessions, err := uc.authCacheRepo.GetSessions(ctx, atc.AccountID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	found := false
+	for _, s := range sessions {
+		if s.ID == sessionID {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		e := errshttp.New(errshttp.NotFound, "Session not found")
+		e.AddDetail(fmt.Sprintf("id: session with id %s not found", sessionID))
+		return e
+	}
+
+	if err := uc.authCacheRepo.RevokeSession(ctx, atc.AccountID, sessionID); err != nil {
+		return errshttp.New(errshttp.Internal, "Failed to revoke session")
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions logs the account out everywhere else, except on session which performs the request
+func (uc *Usecase) RevokeOtherSessions(ctx context.Context, atc *tokenutil.AccessTokenClaims) error {
+	sessions, err := uc.authCacheRepo.GetSessions(ctx, atc.AccountID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	for _, s := range sessions {
+		if s.ID == atc.SessionID {
+			continue
+		}
+
+		if err := uc.authCacheRepo.RevokeSession(ctx, atc.AccountID, s.ID); err != nil {
+			return errshttp.New(errshttp.Internal, "Failed to revoke session")
+		}
+	}
+
+	return nil
+}
+
+// Unlock lifts login lockout of an account, along with its failed attempts. Lockouts of client ips are left
+// to expire on their own, an ip which has been guessing passwords of the account should not be lifted along with it
+func (uc *Usecase) Unlock(ctx context.Context, accountID uuid.UUID) error {
+	a, err := uc.accountDBRepo.GetOne(ctx, accountID)
+	if err != nil {
+		e := errshttp.New(errshttp.NotFound, "Account could not be found")
+		e.AddDetail(fmt.Sprintf("data: account with id %s not found", accountID))
+		return e
+	}
+
+	if err := uc.authCacheRepo.ClearLoginFailures(ctx, emailLoginSubject(a.Email)); err != nil {
+		e := errshttp.New(errshttp.Internal, "Failed to unlock account")
+		e.AddDetail(fmt.Sprintf("data: %v", err))
+		return e
+	}
+
+	return nil
+}
+
+// loginFailed counts failed login against both email and client ip, so neither guessing passwords of an account
+// nor spraying passwords across accounts is unlimited. It returns the error which should be sent to client
+func (uc *Usecase) loginFailed(ctx context.Context, emailSubject, ipSubject string) error {
+	lt := uc.conf.LoginThrottle
+	window := durationOr(lt.Window, 15*time.Minute)
+	lockout := durationOr(lt.LockoutDuration, time.Minute)
+	maxLockout := durationOr(lt.MaxLockoutDuration, time.Hour)
+
+	limits := map[string]int{
+		emailSubject: intOr(lt.MaxAttemptsPerEmail, 5),
+		ipSubject:    intOr(lt.MaxAttemptsPerIP, 20),
+	}
+
+	var lockedFor time.Duration
+	for subject, maxAttempts := range limits {
+		d, err := uc.authCacheRepo.RecordLoginFailure(ctx, subject, maxAttempts, window, lockout, maxLockout)
+		if err != nil {
+			uc.log.Errorf("failed to record login failure of %s, %v", subject, err)
+			continue
+		}
+
+		if d > lockedFor {
+			lockedFor = d
+		}
+	}
+
+	if lockedFor > 0 {
+		return lockedOutError(lockedFor)
+	}
+
+	return errshttp.New(errshttp.InvalidCredentials, "Credentials are invalid, either email and/or password")
+}
+
+func lockedOutError(remaining time.Duration) error {
+	seconds := int(math.Ceil(remaining.Seconds()))
+
+	e := errshttp.New(errshttp.ResourceExhausted, "Too many failed login attempts")
+	e.AddDetail(fmt.Sprintf("login: temporarily locked, try again in %d seconds", seconds))
+	e.AddHeader("Retry-After", strconv.Itoa(seconds))
+	return e
+}
+
+// loginAllowed refuses accounts which could not start a session, no matter how they have been authenticated
+func (uc *Usecase) loginAllowed(a *account.AccountDTO) error {
+	if a.SuspendedAt != nil {
+		return suspendedError()
+	}
+
+	if uc.conf.Account.RequireEmailVerification && a.EmailVerifiedAt == nil {
+		e := errshttp.New(errshttp.PermissionDenied, "Email has not been verified")
+		e.AddDetail("email: verify email before logging in, verification email could be requested again")
+		return e
+	}
+
+	return nil
+}
+
+func suspendedError() error {
+	e := errshttp.New(errshttp.PermissionDenied, "Account has been suspended")
+	e.AddDetail("account: suspended by administrator")
+	return e
+}
+
+func emailLoginSubject(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipLoginSubject(ip string) string {
+	return "ip:" + ip
+}
+
+func intOr(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+// durationOr converts configured seconds into duration, or falls back to given default when it is not configured
+func durationOr(seconds, def time.Duration) time.Duration {
+	if seconds <= 0 {
+		return def
+	}
+	return seconds * time.Second
+}
+
+// generateTokens signs access token and refresh token of given session concurrently, refresh token expires at rtExp.
+// id of refresh token is returned as well to keep track of the session
+func (uc *Usecase) generateTokens(a *account.AccountDTO, sessionID uuid.UUID, rtExp time.Time) (string, string, string, error) {
 	wg := new(sync.WaitGroup)
-	atCh, rtCh := make(chan *string, 1), make(chan *string, 1) //access token channel & refresh token channel
+	atCh, rtCh := make(chan *string, 1), make(chan *[2]string, 1) //access token channel & refresh token channel
 	wg.Add(2)
 
 	go func(ch chan *string) {
@@ -71,6 +430,7 @@ func (uc *Usecase) Login(ctx context.Context, email, password string) (*auth.Aut
 			AccountID: a.ID,
 			Email:     a.Email,
 			Role:      a.Role,
+			SessionID: sessionID,
 		})
 		if err != nil {
 			e := errshttp.New(errshttp.Internal, fmt.Sprintf("Failed to generate access_token, %v", err))
@@ -81,101 +441,33 @@ func (uc *Usecase) Login(ctx context.Context, email, password string) (*auth.Aut
 		ch <- &at
 	}(atCh)
 
-	go func(ch chan *string) {
+	go func(ch chan *[2]string) {
 		defer wg.Done()
 
-		rt, err := tokenutil.GenerateRefresh(uc.conf, tokenutil.RefreshTokenPayload{
+		rt, rtID, err := tokenutil.GenerateRefresh(uc.conf, tokenutil.RefreshTokenPayload{
 			AccountID: a.ID,
-		})
+			SessionID: sessionID,
+		}, rtExp)
 		if err != nil {
 			e := errshttp.New(errshttp.Internal, fmt.Sprintf("Failed to generate refresh_token, %v", err))
 			uc.log.Error(e.LogForDebug())
 			ch <- nil // store nil pointer to channel
 			return
 		}
-		ch <- &rt
+		ch <- &[2]string{rt, rtID}
 	}(rtCh)
 
 	wg.Wait()
 	at, rt := <-atCh, <-rtCh
 
 	if at == nil || rt == nil {
-		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
-	}
-
-	return &auth.AuthDTO{
-		Account:      a,
-		AccessToken:  *at,
-		RefreshToken: *rt,
-	}, nil
-}
-
-func (uc *Usecase) Logout(ctx context.Context, accessToken, refreshToken string, atc *tokenutil.AccessTokenClaims, rtc *tokenutil.RefreshTokenClaims) error {
-	atTime := tokenutil.RemainingTime(&atc.RegisteredClaims)
-	rtTime := tokenutil.RemainingTime(&rtc.RegisteredClaims)
-
-	chanErrs := make(chan error, 2)
-	wg := new(sync.WaitGroup)
-	wg.Add(2)
-
-	go func(e chan error) {
-		defer wg.Done()
-
-		err := uc.authCacheRepo.AddAccessTokenToBlacklist(ctx, accessToken, atTime)
-		if err != nil {
-			e := errshttp.New(errshttp.Internal, "Failed to add access token from blacklist")
-			e.AddDetail(fmt.Sprintf("data: %v", err))
-			chanErrs <- e
-		}
-		chanErrs <- nil
-	}(chanErrs)
-
-	go func(e chan error) {
-		defer wg.Done()
-
-		err := uc.authCacheRepo.AddRefreshTokenToBlacklist(ctx, rtc.AccountID, refreshToken, rtTime)
-		if err != nil {
-			e := errshttp.New(errshttp.Internal, "Failed to add refresh token from blacklist")
-			e.AddDetail(fmt.Sprintf("data: %v", err))
-			chanErrs <- e
-		}
-		chanErrs <- nil
-	}(chanErrs)
-
-	wg.Wait()
-
-	if e := <-chanErrs; e != nil {
-		return e
+		return "", "", "", errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
-	return nil
+	return *at, rt[0], rt[1], nil
 }
 
-func (uc *Usecase) Refresh(ctx context.Context, refreshToken string, accountID uuid.UUID) (*auth.AuthDTO, error) {
-	a, err := uc.accountDBRepo.GetOne(ctx, accountID)
-	if err != nil {
-		return nil, errshttp.New(errshttp.NotFound, "Something went wrong")
-	}
-
-	if a == nil {
-		e := errshttp.New(errshttp.NotFound, "Account could not be found")
-		e.AddDetail(fmt.Sprintf("data: account with id %s not found", accountID))
-		return nil, e
-	}
-
-	at, err := tokenutil.GenerateAccess(uc.conf, tokenutil.AccessTokenPayload{
-		AccountID: a.ID,
-		Email:     a.Email,
-		Role:      a.Role,
-	})
-
-	if err != nil {
-		return nil, errshttp.New(errshttp.NotFound, "Something went wrong")
-	}
-
-	return &auth.AuthDTO{
-		Account:      a,
-		AccessToken:  at,
-		RefreshToken: refreshToken,
-	}, nil
+// JWKS returns public keys which sign access tokens
+func (uc *Usecase) JWKS() *tokenutil.JWKSet {
+	return tokenutil.JWKS()
 }
@@ -2,7 +2,9 @@ package authweb
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/auth"
@@ -15,9 +17,22 @@ import (
 )
 
 type iUsecase interface {
-	Login(ctx context.Context, email, password string) (*auth.AuthDTO, error)
-	Logout(ctx context.Context, accessToken, refreshToken string, atc *tokenutil.AccessTokenClaims, rtc *tokenutil.RefreshTokenClaims) error
-	Refresh(ctx context.Context, refreshToken string, accountID uuid.UUID) (*auth.AuthDTO, error)
+	Login(ctx context.Context, email, password string, device *auth.DeviceDTO) (*auth.AuthDTO, *auth.MFAChallengeDTO, error)
+	Logout(ctx context.Context, accessToken string, rtc *tokenutil.RefreshTokenClaims) error
+	Refresh(ctx context.Context, rtc *tokenutil.RefreshTokenClaims) (*auth.AuthDTO, error)
+	GetSessions(ctx context.Context, atc *tokenutil.AccessTokenClaims) ([]auth.SessionDTO, error)
+	RevokeSession(ctx context.Context, atc *tokenutil.AccessTokenClaims, sessionID uuid.UUID) error
+	RevokeOtherSessions(ctx context.Context, atc *tokenutil.AccessTokenClaims) error
+	Unlock(ctx context.Context, accountID uuid.UUID) error
+	VerifyMFA(ctx context.Context, challengeToken, code string) (*auth.AuthDTO, error)
+	EnrollMFAChallenge(ctx context.Context, challengeToken string) (*auth.MFAEnrollmentDTO, error)
+	EnrollMFA(ctx context.Context, atc *tokenutil.AccessTokenClaims) (*auth.MFAEnrollmentDTO, error)
+	ActivateMFA(ctx context.Context, code string, atc *tokenutil.AccessTokenClaims) (*auth.RecoveryCodesDTO, error)
+	RegenerateRecoveryCodes(ctx context.Context, code string, atc *tokenutil.AccessTokenClaims) (*auth.RecoveryCodesDTO, error)
+	DisableMFA(ctx context.Context, code string, atc *tokenutil.AccessTokenClaims) error
+	StartOIDC(ctx context.Context, provider string) (string, error)
+	CallbackOIDC(ctx context.Context, provider, code, state string, device *auth.DeviceDTO) (*auth.AuthDTO, *auth.MFAChallengeDTO, error)
+	JWKS() *tokenutil.JWKSet
 }
 
 type controller struct {
@@ -47,41 +62,37 @@ func (con *controller) login(c echo.Context) error {
 		return e
 	}
 
-	a, err := con.authUC.Login(c.Request().Context(), dto.Email, dto.Password)
+	device := &auth.DeviceDTO{
+		UserAgent: c.Request().UserAgent(),
+		IP:        c.RealIP(),
+	}
+
+	a, challenge, err := con.authUC.Login(c.Request().Context(), dto.Email, dto.Password, device)
 	if err != nil {
 		return err
 	}
 
+	// login is not completed until second factor is verified
+	if challenge != nil {
+		return c.JSON(http.StatusAccepted, challenge)
+	}
+
 	return c.JSON(http.StatusOK, a)
 }
 
 func (con *controller) logout(c echo.Context) error {
-	at := webcontext.GetAccessToken(c.Request().Context())
-	rt := webcontext.GetRefreshToken(c.Request().Context())
-
-	if at == "" || rt == "" {
-		e := errshttp.New(errshttp.Unauthenticated, "Could not give access to this resource")
-		if at == "" {
-			e.AddDetail("token: access_token is missing")
-		}
-
-		if rt == "" {
-			e.AddDetail("token: refresh_token is missing")
-		}
-
-		return e
-	}
-
-	atc := webcontext.GetAccessTokenClaims(c.Request().Context())
 	rtc := webcontext.GetRefreshTokenClaims(c.Request().Context())
 
-	if atc == nil || rtc == nil {
+	if rtc == nil {
 		e := errshttp.New(errshttp.Unauthenticated, "Could not give access to this resource")
 		e.AddDetail("data: claims are not found")
 		return e
 	}
 
-	err := con.authUC.Logout(c.Request().Context(), at, rt, atc, rtc)
+	// access token is not required, since it may have expired while its refresh token is still alive
+	at, _ := tokenutil.ExtractBearerToken(c.Request().Header.Get("Authorization"))
+
+	err := con.authUC.Logout(c.Request().Context(), at, rtc)
 	if err != nil {
 		return err
 	}
@@ -106,11 +117,256 @@ func (con *controller) refreshToken(c echo.Context) error {
 		return e
 	}
 
-	a, err := con.authUC.Refresh(c.Request().Context(), rt, claims.AccountID)
+	a, err := con.authUC.Refresh(c.Request().Context(), claims)
+
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) getSessions(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	s, err := con.authUC.GetSessions(c.Request().Context(), claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, s)
+}
+
+func (con *controller) revokeSession(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Session id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.authUC.RevokeSession(c.Request().Context(), claims, id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (con *controller) revokeOtherSessions(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.authUC.RevokeOtherSessions(c.Request().Context(), claims); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (con *controller) unlock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	if err := con.authUC.Unlock(c.Request().Context(), id); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (con *controller) verifyMFA(c echo.Context) error {
+	dto := new(auth.MFAVerifyDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	a, err := con.authUC.VerifyMFA(c.Request().Context(), dto.ChallengeToken, dto.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, a)
+}
+
+func (con *controller) enrollMFAChallenge(c echo.Context) error {
+	dto := new(auth.MFAChallengeTokenDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	res, err := con.authUC.EnrollMFAChallenge(c.Request().Context(), dto.ChallengeToken)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (con *controller) enrollMFA(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	res, err := con.authUC.EnrollMFA(c.Request().Context(), claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (con *controller) activateMFA(c echo.Context) error {
+	dto := new(auth.MFACodeDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	res, err := con.authUC.ActivateMFA(c.Request().Context(), dto.Code, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+func (con *controller) regenerateRecoveryCodes(c echo.Context) error {
+	dto := new(auth.MFACodeDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
 
+	res, err := con.authUC.RegenerateRecoveryCodes(c.Request().Context(), dto.Code, claims)
 	if err != nil {
 		return err
 	}
 
+	return c.JSON(http.StatusOK, res)
+}
+
+func (con *controller) disableMFA(c echo.Context) error {
+	dto := new(auth.MFACodeDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.authUC.DisableMFA(c.Request().Context(), dto.Code, claims); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (con *controller) startOIDC(c echo.Context) error {
+	url, err := con.authUC.StartOIDC(c.Request().Context(), c.Param("provider"))
+	if err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusFound, url)
+}
+
+func (con *controller) callbackOIDC(c echo.Context) error {
+	// provider redirects back with error whenever user denies the authorization
+	if reason := c.QueryParam("error"); reason != "" {
+		e := errshttp.New(errshttp.Unauthenticated, "Authorization was not granted by provider")
+		e.AddDetail(fmt.Sprintf("error: %s", strings.ReplaceAll(reason, ":", ",")))
+		return e
+	}
+
+	code, state := c.QueryParam("code"), c.QueryParam("state")
+	if code == "" || state == "" {
+		e := errshttp.New(errshttp.InvalidArgument, "Authorization response is incomplete")
+		e.AddDetail("code: expected both code and state query params")
+		return e
+	}
+
+	device := &auth.DeviceDTO{
+		UserAgent: c.Request().UserAgent(),
+		IP:        c.RealIP(),
+	}
+
+	a, challenge, err := con.authUC.CallbackOIDC(c.Request().Context(), c.Param("provider"), code, state, device)
+	if err != nil {
+		return err
+	}
+
+	if challenge != nil {
+		return c.JSON(http.StatusAccepted, challenge)
+	}
+
 	return c.JSON(http.StatusOK, a)
 }
+
+func (con *controller) jwks(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(http.StatusOK, con.authUC.JWKS())
+}
@@ -2,6 +2,7 @@ package authweb
 
 import (
 	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
 	"github.com/goplateframework/pkg/logger"
 )
 
@@ -13,8 +14,25 @@ type Options struct {
 func Route(web *web.Web, opts *Options) {
 	con := newController(opts.AuthUC, opts.Log)
 
+	web.Echo.GET("/.well-known/jwks.json", con.jwks)
+
 	g := web.Echo.Group("/api/v1/auth")
 	g.POST("/login", con.login)
-	g.POST("/logout", con.logout, web.Mid.RefreshAuth, web.Mid.Authenticated)
-	g.POST("/refresh", con.refreshToken, web.Mid.RefreshAuth, web.Mid.Authenticated)
+	g.POST("/logout", con.logout, web.Mid.RefreshAuth)
+	g.POST("/refresh", con.refreshToken, web.Mid.RefreshAuth)
+	g.GET("/sessions", con.getSessions, web.Mid.Authenticated)
+	g.DELETE("/sessions", con.revokeOtherSessions, web.Mid.Authenticated)
+	g.DELETE("/sessions/:id", con.revokeSession, web.Mid.Authenticated)
+
+	g.GET("/oidc/:provider/start", con.startOIDC)
+	g.GET("/oidc/:provider/callback", con.callbackOIDC)
+
+	g.POST("/mfa/verify", con.verifyMFA)
+	g.POST("/mfa/challenge/enroll", con.enrollMFAChallenge)
+	g.POST("/mfa/enroll", con.enrollMFA, web.Mid.Authenticated)
+	g.POST("/mfa/activate", con.activateMFA, web.Mid.Authenticated)
+	g.POST("/mfa/recovery-codes", con.regenerateRecoveryCodes, web.Mid.Authenticated)
+	g.DELETE("/mfa", con.disableMFA, web.Mid.Authenticated)
+
+	g.POST("/accounts/:id/unlock", con.unlock, web.Mid.Authenticated, web.Mid.RequireRole(middlewares.AdminRoles...))
 }
@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/account"
 )
 
@@ -10,6 +13,9 @@ type AuthDTO struct {
 	AccessToken  string              `json:"access_token"`
 	RefreshToken string              `json:"refresh_token"`
 	Account      *account.AccountDTO `json:"account"`
+
+	// only given once, whenever the login completes an mfa enrollment
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
 }
 
 type LoginDTO struct {
@@ -24,3 +30,105 @@ func (d LoginDTO) Validate() error {
 		validation.Field(&d.Password, validation.Required),
 	)
 }
+
+// DeviceDTO describes client which performs the login, it is stored along with the session
+type DeviceDTO struct {
+	UserAgent string
+	IP        string
+}
+
+// SessionDTO is a login of an account on a device, it lives as long as its refresh token family
+type SessionDTO struct {
+	ID         uuid.UUID `json:"id"`
+	AccountID  uuid.UUID `json:"-"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+// MFAChallengeDTO is returned by login instead of tokens, whenever the account has to pass second factor.
+// Account which is required to use mfa but has not enrolled yet, has to enroll using the challenge token first
+type MFAChallengeDTO struct {
+	ChallengeToken     string    `json:"challenge_token"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+// MFAChallenge is a pending login which waits for second factor
+type MFAChallenge struct {
+	AccountID uuid.UUID
+	Device    DeviceDTO
+	Enroll    bool
+}
+
+type MFAEnrollmentDTO struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qr_code"` // base64 encoded png
+}
+
+// MFADTO is totp enrollment of an account, it is active once EnabledAt is set
+type MFADTO struct {
+	AccountID uuid.UUID
+	Secret    string
+	EnabledAt *time.Time
+}
+
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFAChallengeTokenDTO struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func (d MFAChallengeTokenDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.ChallengeToken, validation.Required),
+	)
+}
+
+type MFAVerifyDTO struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"` // either totp code or recovery code
+}
+
+func (d MFAVerifyDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.ChallengeToken, validation.Required),
+		validation.Field(&d.Code, validation.Required),
+	)
+}
+
+type MFACodeDTO struct {
+	Code string `json:"code"`
+}
+
+func (d MFACodeDTO) Validate() error {
+	return validation.ValidateStruct(
+		&d,
+		validation.Field(&d.Code, validation.Required),
+	)
+}
+
+// IdentityDTO links an account to its identity on an external oidc provider
+type IdentityDTO struct {
+	ID        uuid.UUID
+	AccountID uuid.UUID
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OIDCState is a pending authorization request, kept until provider redirects back to callback
+type OIDCState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"` // pkce code verifier
+}
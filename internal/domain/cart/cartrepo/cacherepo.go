@@ -0,0 +1,107 @@
+package cartrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/cart"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrConflict is returned whenever cart keeps being changed by other requests while it is being updated
+var ErrConflict = errors.New("cart is being changed concurrently")
+
+// updateRetries is how many times an update is retried on cart which has been changed meanwhile
+const updateRetries = 5
+
+type Cache struct {
+	*redis.Client
+}
+
+func NewCache(client *redis.Client) *Cache {
+	return &Cache{client}
+}
+
+// Get returns cart of an account, nil is returned whenever the account has no cart or it has expired
+func (c *Cache) Get(ctx context.Context, accountID uuid.UUID) (*cart.Cart, error) {
+	return getCart(ctx, c.Client, getCartKey(accountID))
+}
+
+// Update applies fn on cart of an account then stores it, expiration of the cart is restarted and cart without item is
+// discarded. fn receives an empty cart whenever the account has none. Cart is watched meanwhile, so fn is called again on
+// fresh cart whenever it is changed before being stored, ErrConflict is returned once retries run out
+func (c *Cache) Update(ctx context.Context, accountID uuid.UUID, exp time.Duration, fn func(ct *cart.Cart) error) error {
+	key := getCartKey(accountID)
+
+	update := func(tx *redis.Tx) error {
+		ct, err := getCart(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+
+		if ct == nil {
+			ct = &cart.Cart{Items: []cart.Item{}}
+		}
+
+		if err := fn(ct); err != nil {
+			return err
+		}
+
+		var data []byte
+		if len(ct.Items) > 0 {
+			if data, err = sonic.Marshal(ct); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if data == nil {
+				pipe.Del(ctx, key)
+			} else {
+				pipe.Set(ctx, key, data, exp)
+			}
+			return nil
+		})
+		return err
+	}
+
+	for i := 0; i < updateRetries; i++ {
+		err := c.Watch(ctx, update, key)
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+
+	return ErrConflict
+}
+
+// Delete removes cart of an account
+func (c *Cache) Delete(ctx context.Context, accountID uuid.UUID) error {
+	return c.Del(ctx, getCartKey(accountID)).Err()
+}
+
+func getCart(ctx context.Context, client redis.Cmdable, key string) (*cart.Cart, error) {
+	data, err := client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	ct := new(cart.Cart)
+	if err := sonic.Unmarshal(data, ct); err != nil {
+		return nil, err
+	}
+
+	return ct, nil
+}
+
+func getCartKey(accountID uuid.UUID) string {
+	return fmt.Sprintf("cart:%s", accountID.String())
+}
@@ -0,0 +1,514 @@
+package cartuc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/cart"
+	"github.com/goplateframework/internal/domain/cart/cartrepo"
+	"github.com/goplateframework/internal/domain/menu"
+	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/menutopinggroup"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/pkg/logger"
+)
+
+const maxQuantity = 99
+
+// required iCacheRepository methods which this usecase needs to store or retrieve carts
+type iCacheRepository interface {
+	Get(ctx context.Context, accountID uuid.UUID) (*cart.Cart, error)
+	Update(ctx context.Context, accountID uuid.UUID, exp time.Duration, fn func(ct *cart.Cart) error) error
+	Delete(ctx context.Context, accountID uuid.UUID) error
+}
+
+// required menu repository methods to price items of cart
+type iMenuRepository interface {
+	GetMany(ctx context.Context, ids []uuid.UUID) ([]menu.MenuDTO, error)
+}
+
+// required toping repository methods to price selected topings of cart
+type iTopingRepository interface {
+	GetMany(ctx context.Context, ids []uuid.UUID) ([]menutoping.MenuTopingsDTO, error)
+}
+
+// required toping group repository methods to check selected topings against rules of their groups
+type iGroupRepository interface {
+	GetAll(ctx context.Context, menuID uuid.UUID) ([]menutopinggroup.GroupDTO, error)
+	GetTopings(ctx context.Context, menuID uuid.UUID) ([]menutoping.MenuTopingsDTO, error)
+}
+
+type Usecase struct {
+	conf       *config.Config
+	log        *logger.Log
+	cacheRepo  iCacheRepository
+	menuRepo   iMenuRepository
+	topingRepo iTopingRepository
+	groupRepo  iGroupRepository
+}
+
+func New(conf *config.Config, log *logger.Log, cacheRepo iCacheRepository, menuRepo iMenuRepository, topingRepo iTopingRepository, groupRepo iGroupRepository) *Usecase {
+	return &Usecase{
+		conf:       conf,
+		log:        log,
+		cacheRepo:  cacheRepo,
+		menuRepo:   menuRepo,
+		topingRepo: topingRepo,
+		groupRepo:  groupRepo,
+	}
+}
+
+// catalog holds current menus and topings of a cart, keyed by their id. Toping groups and every toping
+// of a menu are only loaded for the menu whose item is being validated, keyed by the menu id
+type catalog struct {
+	menus       map[uuid.UUID]menu.MenuDTO
+	topings     map[uuid.UUID]menutoping.MenuTopingsDTO
+	groups      map[uuid.UUID][]menutopinggroup.GroupDTO
+	menuTopings map[uuid.UUID][]menutoping.MenuTopingsDTO
+}
+
+// Get returns cart of the account which owns given claims, priced by current price of its menus and topings.
+// Items whose menu has been deleted are left out, as well as deleted topings
+func (uc *Usecase) Get(ctx context.Context, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error) {
+	ct, err := uc.load(ctx, claims.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	cat, err := uc.catalog(ctx, ct.Items)
+	if err != nil {
+		return nil, err
+	}
+	cat.prune(ct)
+
+	return uc.intoDTO(ct, cat), nil
+}
+
+// AddItem puts a menu along with its selected topings into cart. Quantity is added into existing item
+// whenever the cart already holds the same menu with the same topings
+func (uc *Usecase) AddItem(ctx context.Context, ni *cart.NewCartItemDTO, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error) {
+	var (
+		res *cart.Cart
+		cat *catalog
+	)
+
+	err := uc.update(ctx, claims.AccountID, func(ct *cart.Cart) error {
+		item := cart.Item{
+			ID:        uuid.New(),
+			MenuID:    uuid.MustParse(ni.MenuID),
+			Quantity:  ni.Quantity,
+			TopingIDs: parseIDs(ni.TopingIDs),
+		}
+
+		var err error
+		cat, err = uc.catalog(ctx, append(slices.Clone(ct.Items), item))
+		if err != nil {
+			return err
+		}
+		cat.prune(ct)
+
+		if err := uc.loadGroups(ctx, cat, item.MenuID); err != nil {
+			return err
+		}
+
+		outletID, err := cat.validate(ct, &item)
+		if err != nil {
+			return err
+		}
+
+		if i := slices.IndexFunc(ct.Items, item.SameSelection); i >= 0 {
+			if ct.Items[i].Quantity+item.Quantity > maxQuantity {
+				e := errshttp.New(errshttp.InvalidArgument, "Quantity of cart item is too much")
+				e.AddDetail(fmt.Sprintf("quantity: quantity of an item must be no greater than %d", maxQuantity))
+				return e
+			}
+			ct.Items[i].Quantity += item.Quantity
+		} else {
+			if len(ct.Items) >= uc.maxItems() {
+				e := errshttp.New(errshttp.ResourceExhausted, "Cart is full")
+				e.AddDetail(fmt.Sprintf("items: cart could hold at most %d items", uc.maxItems()))
+				return e
+			}
+			ct.Items = append(ct.Items, item)
+		}
+
+		ct.OutletID = &outletID
+		res = ct
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.intoDTO(res, cat), nil
+}
+
+// UpdateItem changes quantity and selected topings of an item of cart
+func (uc *Usecase) UpdateItem(ctx context.Context, itemID uuid.UUID, ui *cart.UpdateCartItemDTO, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error) {
+	var (
+		res *cart.Cart
+		cat *catalog
+	)
+
+	topingIDs := parseIDs(ui.TopingIDs)
+
+	err := uc.update(ctx, claims.AccountID, func(ct *cart.Cart) error {
+		var err error
+		cat, err = uc.catalog(ctx, append(slices.Clone(ct.Items), cart.Item{TopingIDs: topingIDs}))
+		if err != nil {
+			return err
+		}
+		cat.prune(ct)
+
+		i, err := findItem(ct, itemID)
+		if err != nil {
+			return err
+		}
+
+		item := ct.Items[i]
+		item.Quantity = ui.Quantity
+		item.TopingIDs = topingIDs
+
+		if err := uc.loadGroups(ctx, cat, item.MenuID); err != nil {
+			return err
+		}
+
+		if _, err := cat.validate(ct, &item); err != nil {
+			return err
+		}
+
+		ct.Items[i] = item
+		res = ct
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.intoDTO(res, cat), nil
+}
+
+// RemoveItem takes an item out of cart, the cart is discarded once its last item is removed
+func (uc *Usecase) RemoveItem(ctx context.Context, itemID uuid.UUID, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error) {
+	var (
+		res *cart.Cart
+		cat *catalog
+	)
+
+	err := uc.update(ctx, claims.AccountID, func(ct *cart.Cart) error {
+		i, err := findItem(ct, itemID)
+		if err != nil {
+			return err
+		}
+		ct.Items = slices.Delete(ct.Items, i, i+1)
+
+		cat, err = uc.catalog(ctx, ct.Items)
+		if err != nil {
+			return err
+		}
+		cat.prune(ct)
+
+		res = ct
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.intoDTO(res, cat), nil
+}
+
+// Clear discards cart of the account which owns given claims
+func (uc *Usecase) Clear(ctx context.Context, claims *tokenutil.AccessTokenClaims) error {
+	if err := uc.cacheRepo.Delete(ctx, claims.AccountID); err != nil {
+		uc.log.Errorf("failed to clear cart of account %s: %v", claims.AccountID, err)
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// load returns stored cart of an account, or an empty cart whenever it has none
+func (uc *Usecase) load(ctx context.Context, accountID uuid.UUID) (*cart.Cart, error) {
+	ct, err := uc.cacheRepo.Get(ctx, accountID)
+	if err != nil {
+		uc.log.Errorf("failed to get cart of account %s: %v", accountID, err)
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if ct == nil {
+		return &cart.Cart{Items: []cart.Item{}}, nil
+	}
+
+	return ct, nil
+}
+
+// update applies fn on stored cart of an account and restarts its expiration, an empty cart is discarded instead.
+// Cart is read and written atomically, fn is called again on fresh cart whenever another request changes it meanwhile,
+// such as the same account editing its cart from another device
+func (uc *Usecase) update(ctx context.Context, accountID uuid.UUID, fn func(ct *cart.Cart) error) error {
+	err := uc.cacheRepo.Update(ctx, accountID, uc.ttl(), func(ct *cart.Cart) error {
+		if err := fn(ct); err != nil {
+			return err
+		}
+
+		ct.UpdatedAt = time.Now()
+		return nil
+	})
+
+	var e *errshttp.ErrorResponse
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &e):
+		return err
+	case errors.Is(err, cartrepo.ErrConflict):
+		e := errshttp.New(errshttp.Aborted, "Cart is being changed by another request")
+		e.AddDetail("cart: changed concurrently too many times, try again")
+		return e
+	}
+
+	uc.log.Errorf("failed to save cart of account %s: %v", accountID, err)
+	return errshttp.New(errshttp.Internal, "Something went wrong")
+}
+
+// catalog loads current menus and topings of given items
+func (uc *Usecase) catalog(ctx context.Context, items []cart.Item) (*catalog, error) {
+	cat := &catalog{
+		menus:       make(map[uuid.UUID]menu.MenuDTO),
+		topings:     make(map[uuid.UUID]menutoping.MenuTopingsDTO),
+		groups:      make(map[uuid.UUID][]menutopinggroup.GroupDTO),
+		menuTopings: make(map[uuid.UUID][]menutoping.MenuTopingsDTO),
+	}
+
+	var menuIDs, topingIDs []uuid.UUID
+	for _, i := range items {
+		if i.MenuID != uuid.Nil {
+			menuIDs = append(menuIDs, i.MenuID)
+		}
+		topingIDs = append(topingIDs, i.TopingIDs...)
+	}
+
+	if len(menuIDs) > 0 {
+		menus, err := uc.menuRepo.GetMany(ctx, menuIDs)
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		for _, m := range menus {
+			cat.menus[m.ID] = m
+		}
+	}
+
+	if len(topingIDs) > 0 {
+		topings, err := uc.topingRepo.GetMany(ctx, topingIDs)
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		for _, t := range topings {
+			cat.topings[t.ID] = t
+		}
+	}
+
+	return cat, nil
+}
+
+// loadGroups loads toping groups and every toping of a menu into catalog, so selected topings of the menu
+// could be checked against rules of their groups
+func (uc *Usecase) loadGroups(ctx context.Context, cat *catalog, menuID uuid.UUID) error {
+	groups, err := uc.groupRepo.GetAll(ctx, menuID)
+	if err != nil {
+		uc.log.Errorf("failed to get toping groups of menu %s: %v", menuID, err)
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	topings, err := uc.groupRepo.GetTopings(ctx, menuID)
+	if err != nil {
+		uc.log.Errorf("failed to get topings of menu %s: %v", menuID, err)
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	cat.groups[menuID] = groups
+	cat.menuTopings[menuID] = topings
+	return nil
+}
+
+// prune drops items whose menu no longer exists and topings which no longer exist,
+// outlet of the cart is released once it has no item left
+func (cat *catalog) prune(ct *cart.Cart) {
+	ct.Items = slices.DeleteFunc(ct.Items, func(i cart.Item) bool {
+		_, ok := cat.menus[i.MenuID]
+		return !ok
+	})
+
+	for i := range ct.Items {
+		ct.Items[i].TopingIDs = slices.DeleteFunc(ct.Items[i].TopingIDs, func(id uuid.UUID) bool {
+			t, ok := cat.topings[id]
+			return !ok || t.MenuID != ct.Items[i].MenuID
+		})
+	}
+
+	if len(ct.Items) == 0 {
+		ct.OutletID = nil
+	}
+}
+
+// validate makes sure given item could be put into cart, it returns outlet of the item's menu
+func (cat *catalog) validate(ct *cart.Cart, item *cart.Item) (uuid.UUID, error) {
+	m, ok := cat.menus[item.MenuID]
+	if !ok {
+		e := errshttp.New(errshttp.NotFound, "Menu not found")
+		e.AddDetail(fmt.Sprintf("menu_id: menu %s not found", item.MenuID))
+		return uuid.Nil, e
+	}
+
+	if !m.IsAvailable {
+		e := errshttp.New(errshttp.FailedPrecondition, "Menu is not available")
+		e.AddDetail(fmt.Sprintf("menu_id: menu %s is not available", item.MenuID))
+		return uuid.Nil, e
+	}
+
+	outletID, err := uuid.Parse(m.OutletID)
+	if err != nil {
+		return uuid.Nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if ct.OutletID != nil && *ct.OutletID != outletID {
+		e := errshttp.New(errshttp.FailedPrecondition, "Cart holds menus of another outlet")
+		e.AddDetail(fmt.Sprintf("menu_id: menu %s does not belong to outlet %s, clear the cart first", item.MenuID, *ct.OutletID))
+		return uuid.Nil, e
+	}
+
+	selected := make(map[uuid.UUID]bool, len(item.TopingIDs))
+	for _, id := range item.TopingIDs {
+		t, ok := cat.topings[id]
+		if !ok || t.MenuID != item.MenuID {
+			e := errshttp.New(errshttp.NotFound, "Menu toping not found")
+			e.AddDetail(fmt.Sprintf("toping_ids: toping %s not found on menu %s", id, item.MenuID))
+			return uuid.Nil, e
+		}
+
+		if !t.IsAvailable {
+			e := errshttp.New(errshttp.FailedPrecondition, "Menu toping is not available")
+			e.AddDetail(fmt.Sprintf("toping_ids: toping %s is not available", id))
+			return uuid.Nil, e
+		}
+
+		if selected[id] {
+			e := errshttp.New(errshttp.InvalidArgument, "Menu toping is selected more than once")
+			e.AddDetail(fmt.Sprintf("toping_ids: toping %s is selected more than once", id))
+			return uuid.Nil, e
+		}
+		selected[id] = true
+	}
+
+	res := menutopinggroup.Check(item.MenuID, cat.groups[item.MenuID], cat.menuTopings[item.MenuID], item.TopingIDs)
+	if !res.Valid {
+		e := errshttp.New(errshttp.InvalidArgument, "Selected topings do not satisfy toping groups of the menu")
+		for _, d := range res.Details("toping_ids") {
+			e.AddDetail(d)
+		}
+		return uuid.Nil, e
+	}
+
+	return outletID, nil
+}
+
+// intoDTO prices given cart by current price of its menus and topings
+func (uc *Usecase) intoDTO(ct *cart.Cart, cat *catalog) *cart.CartDTO {
+	dto := &cart.CartDTO{
+		OutletID: ct.OutletID,
+		Items:    []cart.CartItemDTO{},
+	}
+
+	for _, i := range ct.Items {
+		m := cat.menus[i.MenuID]
+
+		item := cart.CartItemDTO{
+			ID:          i.ID,
+			MenuID:      i.MenuID,
+			Name:        m.Name,
+			Price:       m.Price,
+			Quantity:    i.Quantity,
+			Topings:     []cart.CartTopingDTO{},
+			IsAvailable: m.IsAvailable,
+		}
+
+		unitPrice := m.Price
+		for _, id := range i.TopingIDs {
+			t := cat.topings[id]
+			item.Topings = append(item.Topings, cart.CartTopingDTO{
+				ID:          t.ID,
+				Name:        t.Name,
+				Price:       t.Price,
+				IsAvailable: t.IsAvailable,
+			})
+			item.IsAvailable = item.IsAvailable && t.IsAvailable
+			unitPrice += t.Price
+		}
+
+		item.Total = order.RoundPrice(unitPrice * float64(i.Quantity))
+		if item.IsAvailable {
+			dto.Total = order.RoundPrice(dto.Total + item.Total)
+		}
+		dto.Items = append(dto.Items, item)
+	}
+
+	if len(ct.Items) > 0 {
+		exp := ct.UpdatedAt.Add(uc.ttl())
+		dto.ExpiresAt = &exp
+	}
+
+	return dto
+}
+
+func findItem(ct *cart.Cart, itemID uuid.UUID) (int, error) {
+	i := slices.IndexFunc(ct.Items, func(item cart.Item) bool {
+		return item.ID == itemID
+	})
+
+	if i < 0 {
+		e := errshttp.New(errshttp.NotFound, "Cart item not found")
+		e.AddDetail(fmt.Sprintf("data: cart item with id %s not found", itemID))
+		return 0, e
+	}
+
+	return i, nil
+}
+
+// parseIDs parses validated ids, sorted so the same selection is always stored the same way
+func parseIDs(raw []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, r := range raw {
+		ids = append(ids, uuid.MustParse(r))
+	}
+
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	return ids
+}
+
+func (uc *Usecase) ttl() time.Duration {
+	if uc.conf.Cart.TTL <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return uc.conf.Cart.TTL * time.Second
+}
+
+func (uc *Usecase) maxItems() int {
+	if uc.conf.Cart.MaxItems <= 0 {
+		return 50
+	}
+	return uc.conf.Cart.MaxItems
+}
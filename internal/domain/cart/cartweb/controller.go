@@ -0,0 +1,134 @@
+package cartweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/cart"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type iUsecase interface {
+	Get(ctx context.Context, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error)
+	AddItem(ctx context.Context, ni *cart.NewCartItemDTO, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error)
+	UpdateItem(ctx context.Context, itemID uuid.UUID, ui *cart.UpdateCartItemDTO, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error)
+	RemoveItem(ctx context.Context, itemID uuid.UUID, claims *tokenutil.AccessTokenClaims) (*cart.CartDTO, error)
+	Clear(ctx context.Context, claims *tokenutil.AccessTokenClaims) error
+}
+
+type controller struct {
+	cartUC iUsecase
+	log    *logger.Log
+}
+
+func newController(cartUC iUsecase, log *logger.Log) *controller {
+	return &controller{cartUC, log}
+}
+
+func (con *controller) get(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	ct, err := con.cartUC.Get(c.Request().Context(), claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ct)
+}
+
+func (con *controller) addItem(c echo.Context) error {
+	dto := new(cart.NewCartItemDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	ct, err := con.cartUC.AddItem(c.Request().Context(), dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ct)
+}
+
+func (con *controller) updateItem(c echo.Context) error {
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Cart item id is invalid, should be valid UUID")
+		e.AddDetail("item_id: invalid")
+		return e
+	}
+
+	dto := new(cart.UpdateCartItemDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	ct, err := con.cartUC.UpdateItem(c.Request().Context(), itemID, dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ct)
+}
+
+func (con *controller) removeItem(c echo.Context) error {
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Cart item id is invalid, should be valid UUID")
+		e.AddDetail("item_id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	ct, err := con.cartUC.RemoveItem(c.Request().Context(), itemID, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ct)
+}
+
+func (con *controller) clear(c echo.Context) error {
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.cartUC.Clear(c.Request().Context(), claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,23 @@
+package cartweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log    *logger.Log
+	CartUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.CartUC, opts.Log)
+
+	// cart always belongs to the account of the access token
+	g := web.Echo.Group("/api/v1/cart", web.Mid.Authenticated)
+	g.GET("", con.get)
+	g.DELETE("", con.clear)
+	g.POST("/items", con.addItem)
+	g.PUT("/items/:item_id", con.updateItem)
+	g.DELETE("/items/:item_id", con.removeItem)
+}
@@ -0,0 +1,84 @@
+package cart
+
+import (
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// Cart is what we keep on cache, it only holds selections. Prices are never stored,
+// they are taken from menus and topings whenever the cart is read
+type Cart struct {
+	OutletID  *uuid.UUID `json:"outlet_id"` // nil while cart is empty
+	Items     []Item     `json:"items"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type Item struct {
+	ID        uuid.UUID   `json:"id"`
+	MenuID    uuid.UUID   `json:"menu_id"`
+	Quantity  int         `json:"quantity"`
+	TopingIDs []uuid.UUID `json:"toping_ids"`
+}
+
+// SameSelection tells whether two items hold the same menu with the same topings, toping ids are expected to be sorted
+func (i Item) SameSelection(other Item) bool {
+	return i.MenuID == other.MenuID && slices.Equal(i.TopingIDs, other.TopingIDs)
+}
+
+// CartDTO is what we send to client, priced by current price of menus and topings
+type CartDTO struct {
+	OutletID  *uuid.UUID    `json:"outlet_id"`
+	Items     []CartItemDTO `json:"items"`
+	Total     float64       `json:"total"`      // sum of available items only, since unavailable ones could not be ordered
+	ExpiresAt *time.Time    `json:"expires_at"` // nil while cart is empty
+}
+
+type CartItemDTO struct {
+	ID          uuid.UUID       `json:"id"`
+	MenuID      uuid.UUID       `json:"menu_id"`
+	Name        string          `json:"name"`
+	Price       float64         `json:"price"`
+	Quantity    int             `json:"quantity"`
+	Topings     []CartTopingDTO `json:"topings"`
+	Total       float64         `json:"total"`
+	IsAvailable bool            `json:"is_available"` // false whenever the menu or one of its topings is not available anymore
+}
+
+type CartTopingDTO struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Price       float64   `json:"price"`
+	IsAvailable bool      `json:"is_available"`
+}
+
+// NewCartItemDTO is what client should send to put a menu into cart
+type NewCartItemDTO struct {
+	MenuID    string   `json:"menu_id"`
+	Quantity  int      `json:"quantity"`
+	TopingIDs []string `json:"toping_ids"`
+}
+
+func (d NewCartItemDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuID, validation.Required, is.UUID),
+		validation.Field(&d.Quantity, validation.Required, validation.Min(1), validation.Max(99)),
+		validation.Field(&d.TopingIDs, validation.Length(0, 10), validation.Each(is.UUID)),
+	)
+}
+
+// UpdateCartItemDTO is what client should send to change an item of cart, every selected toping is replaced
+type UpdateCartItemDTO struct {
+	Quantity  int      `json:"quantity"`
+	TopingIDs []string `json:"toping_ids"`
+}
+
+func (d UpdateCartItemDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Quantity, validation.Required, validation.Min(1), validation.Max(99)),
+		validation.Field(&d.TopingIDs, validation.Length(0, 10), validation.Each(is.UUID)),
+	)
+}
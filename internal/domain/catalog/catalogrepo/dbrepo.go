@@ -0,0 +1,96 @@
+package catalogrepo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/catalog"
+	"github.com/jmoiron/sqlx"
+)
+
+// repository reads only what customers are able to order, unavailable menus and toppings are never returned
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// GetMenus returns available menus of an outlet along with their toppings
+func (dbrepo *repository) GetMenus(ctx context.Context, outletID uuid.UUID) ([]catalog.MenuDTO, error) {
+	q := `
+	SELECT id, outlet_id, name, description, price, COALESCE(image_url, '') AS image_url FROM menus
+	WHERE outlet_id = $1 AND is_available
+	ORDER BY name, id`
+
+	models := []MenuModel{}
+	if err := dbrepo.SelectContext(ctx, &models, q, outletID); err != nil {
+		return nil, err
+	}
+
+	menus := make([]catalog.MenuDTO, 0, len(models))
+	for i := range models {
+		menus = append(menus, *models[i].intoDTO())
+	}
+
+	if err := dbrepo.attachTopings(ctx, menus); err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+// GetMenu returns an available menu along with its toppings, sql.ErrNoRows is returned whenever it is unavailable
+func (dbrepo *repository) GetMenu(ctx context.Context, id uuid.UUID) (*catalog.MenuDTO, error) {
+	m := new(MenuModel)
+
+	q := `
+	SELECT id, outlet_id, name, description, price, COALESCE(image_url, '') AS image_url FROM menus
+	WHERE id = $1 AND is_available`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	menus := []catalog.MenuDTO{*m.intoDTO()}
+	if err := dbrepo.attachTopings(ctx, menus); err != nil {
+		return nil, err
+	}
+
+	return &menus[0], nil
+}
+
+// attachTopings fills toppings of given menus, those which are out of stock are left out
+func (dbrepo *repository) attachTopings(ctx context.Context, menus []catalog.MenuDTO) error {
+	if len(menus) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID]int, len(menus))
+	ids := make([]uuid.UUID, 0, len(menus))
+	for i := range menus {
+		index[menus[i].ID] = i
+		ids = append(ids, menus[i].ID)
+	}
+
+	q, args, err := sqlx.In(`
+	SELECT id, menu_id, name, price, COALESCE(image_url, '') AS image_url FROM menu_topings
+	WHERE menu_id IN (?) AND is_available AND stock > 0
+	ORDER BY name, id`, ids)
+	if err != nil {
+		return err
+	}
+
+	topings := []TopingModel{}
+	if err := dbrepo.SelectContext(ctx, &topings, dbrepo.Rebind(q), args...); err != nil {
+		return err
+	}
+
+	for i := range topings {
+		m := &menus[index[topings[i].MenuID]]
+		m.Topings = append(m.Topings, *topings[i].intoDTO())
+	}
+
+	return nil
+}
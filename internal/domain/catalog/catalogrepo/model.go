@@ -0,0 +1,44 @@
+package catalogrepo
+
+import (
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/catalog"
+)
+
+type MenuModel struct {
+	ID          uuid.UUID `db:"id"`
+	OutletID    uuid.UUID `db:"outlet_id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Price       float64   `db:"price"`
+	ImageURL    string    `db:"image_url"`
+}
+
+func (m *MenuModel) intoDTO() *catalog.MenuDTO {
+	return &catalog.MenuDTO{
+		ID:          m.ID,
+		OutletID:    m.OutletID,
+		Name:        m.Name,
+		Description: m.Description,
+		Price:       m.Price,
+		ImageURL:    m.ImageURL,
+		Topings:     []catalog.TopingDTO{},
+	}
+}
+
+type TopingModel struct {
+	ID       uuid.UUID `db:"id"`
+	MenuID   uuid.UUID `db:"menu_id"`
+	Name     string    `db:"name"`
+	Price    float64   `db:"price"`
+	ImageURL string    `db:"image_url"`
+}
+
+func (m *TopingModel) intoDTO() *catalog.TopingDTO {
+	return &catalog.TopingDTO{
+		ID:       m.ID,
+		Name:     m.Name,
+		Price:    m.Price,
+		ImageURL: m.ImageURL,
+	}
+}
@@ -0,0 +1,95 @@
+package cataloguc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/catalog"
+	"github.com/goplateframework/internal/domain/outlet"
+	"github.com/goplateframework/internal/domain/outlet/outletweb"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type iRepository interface {
+	GetMenus(ctx context.Context, outletID uuid.UUID) ([]catalog.MenuDTO, error)
+	GetMenu(ctx context.Context, id uuid.UUID) (*catalog.MenuDTO, error)
+}
+
+// required outlet usecase methods, catalog shows outlets the same way as they are shown to accounts
+type iOutletUsecase interface {
+	GetAll(ctx context.Context, qp *outletweb.QueryParams) (*result.Result[outlet.OutletDTO], error)
+	GetOne(ctx context.Context, id uuid.UUID) (*outlet.OutletDTO, error)
+}
+
+type Usecase struct {
+	conf     *config.Config
+	log      *logger.Log
+	repo     iRepository
+	outletUC iOutletUsecase
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, outletUC iOutletUsecase) *Usecase {
+	return &Usecase{
+		conf:     conf,
+		log:      log,
+		repo:     repo,
+		outletUC: outletUC,
+	}
+}
+
+func (uc *Usecase) GetOutlets(ctx context.Context, qp *outletweb.QueryParams) (*result.Result[catalog.OutletDTO], error) {
+	res, err := uc.outletUC.GetAll(ctx, qp)
+	if err != nil {
+		return nil, err
+	}
+
+	outlets := make([]catalog.OutletDTO, 0, len(res.Items))
+	for i := range res.Items {
+		outlets = append(outlets, *catalog.FromOutlet(&res.Items[i]))
+	}
+
+	return result.New(outlets, res.Total, res.Page, res.Size), nil
+}
+
+func (uc *Usecase) GetOutlet(ctx context.Context, id uuid.UUID) (*catalog.OutletDTO, error) {
+	o, err := uc.outletUC.GetOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.FromOutlet(o), nil
+}
+
+// GetMenus returns every menu of an outlet which customers are able to order
+func (uc *Usecase) GetMenus(ctx context.Context, outletID uuid.UUID) ([]catalog.MenuDTO, error) {
+	if _, err := uc.outletUC.GetOne(ctx, outletID); err != nil {
+		return nil, err
+	}
+
+	menus, err := uc.repo.GetMenus(ctx, outletID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return menus, nil
+}
+
+func (uc *Usecase) GetMenu(ctx context.Context, id uuid.UUID) (*catalog.MenuDTO, error) {
+	m, err := uc.repo.GetMenu(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("data: menu with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return m, nil
+}
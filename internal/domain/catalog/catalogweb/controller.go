@@ -0,0 +1,97 @@
+package catalogweb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/catalog"
+	"github.com/goplateframework/internal/domain/outlet/outletweb"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/web/httpcache"
+	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// required usecase methods which this controller needs to operate the business logic
+type iUsecase interface {
+	GetOutlets(ctx context.Context, qp *outletweb.QueryParams) (*result.Result[catalog.OutletDTO], error)
+	GetOutlet(ctx context.Context, id uuid.UUID) (*catalog.OutletDTO, error)
+	GetMenus(ctx context.Context, outletID uuid.UUID) ([]catalog.MenuDTO, error)
+	GetMenu(ctx context.Context, id uuid.UUID) (*catalog.MenuDTO, error)
+}
+
+type controller struct {
+	catalogUC iUsecase
+	maxAge    time.Duration
+	log       *logger.Log
+}
+
+func newController(catalogUC iUsecase, maxAge time.Duration, log *logger.Log) *controller {
+	return &controller{catalogUC, maxAge, log}
+}
+
+func (con *controller) getOutlets(c echo.Context) error {
+	qp, err := outletweb.GetQueryParams(c).Parse()
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
+		e.AddDetail(err.Error())
+		return e
+	}
+
+	o, err := con.catalogUC.GetOutlets(c.Request().Context(), qp)
+	if err != nil {
+		return err
+	}
+
+	return httpcache.JSON(c, con.maxAge, o)
+}
+
+func (con *controller) getOutlet(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	o, err := con.catalogUC.GetOutlet(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return httpcache.JSON(c, con.maxAge, o)
+}
+
+func (con *controller) getMenus(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	m, err := con.catalogUC.GetMenus(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return httpcache.JSON(c, con.maxAge, m)
+}
+
+func (con *controller) getMenu(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Menu id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	m, err := con.catalogUC.GetMenu(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return httpcache.JSON(c, con.maxAge, m)
+}
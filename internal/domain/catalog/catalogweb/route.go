@@ -0,0 +1,30 @@
+package catalogweb
+
+import (
+	"time"
+
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log       *logger.Log
+	CatalogUC iUsecase
+	MaxAge    time.Duration // in seconds, a minute is used whenever it is not configured
+}
+
+// every route is anonymous and read only, so it is rate limited on its own instead of being authenticated
+func Route(web *web.Web, opts *Options) {
+	maxAge := opts.MaxAge * time.Second
+	if maxAge <= 0 {
+		maxAge = time.Minute
+	}
+
+	con := newController(opts.CatalogUC, maxAge, opts.Log)
+
+	g := web.Echo.Group("/api/v1/public", web.Mid.PublicRateLimit)
+	g.GET("/outlets", con.getOutlets)
+	g.GET("/outlets/:id", con.getOutlet)
+	g.GET("/outlets/:id/menus", con.getMenus)
+	g.GET("/menus/:id", con.getMenu)
+}
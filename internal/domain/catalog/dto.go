@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/outlet"
+)
+
+// OutletDTO is what anonymous customers see of an outlet, it leaves out anything which is meant for staff only
+type OutletDTO struct {
+	ID        uuid.UUID            `json:"id"`
+	Name      string               `json:"name"`
+	Phone     string               `json:"phone"`
+	Timezone  string               `json:"timezone"`
+	IsOpenNow bool                 `json:"is_open_now"`
+	Schedule  []outlet.ScheduleDTO `json:"schedule"`
+	Closures  []ClosureDTO         `json:"closures"`
+	Distance  *float64             `json:"distance_km"`
+	Address   AddressDTO           `json:"address"`
+}
+
+type ClosureDTO struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+type AddressDTO struct {
+	Street     string   `json:"street"`
+	City       string   `json:"city"`
+	Province   string   `json:"province"`
+	PostalCode string   `json:"postal_code"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+}
+
+// FromOutlet picks customer safe fields of an outlet
+func FromOutlet(o *outlet.OutletDTO) *OutletDTO {
+	po := &OutletDTO{
+		ID:        o.ID,
+		Name:      o.Name,
+		Phone:     o.Phone,
+		Timezone:  o.Timezone,
+		IsOpenNow: o.IsOpenNow,
+		Schedule:  o.Schedule,
+		Closures:  make([]ClosureDTO, 0, len(o.Closures)),
+		Distance:  o.Distance,
+	}
+
+	if po.Schedule == nil {
+		po.Schedule = []outlet.ScheduleDTO{}
+	}
+
+	for _, c := range o.Closures {
+		po.Closures = append(po.Closures, ClosureDTO{Date: c.Date, Reason: c.Reason})
+	}
+
+	if o.Address != nil {
+		po.Address = AddressDTO{
+			Street:     o.Address.Street,
+			City:       o.Address.City,
+			Province:   o.Address.Province,
+			PostalCode: o.Address.PostalCode,
+			Latitude:   o.Address.Latitude,
+			Longitude:  o.Address.Longitude,
+		}
+	}
+
+	return po
+}
+
+// MenuDTO is an available menu of an outlet along with its toppings which could be ordered right now
+type MenuDTO struct {
+	ID          uuid.UUID   `json:"id"`
+	OutletID    uuid.UUID   `json:"outlet_id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       float64     `json:"price"`
+	ImageURL    string      `json:"image_url"`
+	Topings     []TopingDTO `json:"topings"`
+}
+
+type TopingDTO struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Price    float64   `json:"price"`
+	ImageURL string    `json:"image_url"`
+}
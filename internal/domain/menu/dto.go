@@ -6,22 +6,25 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menucategory"
 )
 
 // MenuDTO is what we send to client
 type MenuDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	Price       float64   `json:"price"`
-	IsAvailable bool      `json:"is_available"`
-	ImageURL    string    `json:"image_url"`
-	OutletID    string    `json:"outlet_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Price       float64    `json:"price"`
+	IsAvailable bool       `json:"is_available"`
+	ImageURL    string     `json:"image_url"`
+	OutletID    string     `json:"outlet_id"`
+	CategoryID  *uuid.UUID `json:"category_id"` // nil whenever menu is uncategorized
+	Position    int        `json:"position"`    // within its category
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
-// NewMenuDTO is what client should send to create new menu
+// NewMenuDTO is what client should send to create new menu, menu is placed last on its category
 type NewMenuDTO struct {
 	Name        string  `json:"name" form:"name"`
 	Description string  `json:"description" form:"description"`
@@ -29,6 +32,7 @@ type NewMenuDTO struct {
 	ImageURL    string  `json:"image_url" form:"image_url"`
 	IsAvailable bool    `json:"is_available" form:"is_available"`
 	OutletID    string  `json:"outlet_id" form:"outlet_id"`
+	CategoryID  string  `json:"category_id" form:"category_id"`
 }
 
 func (m NewMenuDTO) Validate() error {
@@ -37,5 +41,12 @@ func (m NewMenuDTO) Validate() error {
 		validation.Field(&m.Description, validation.Required, validation.Length(1, 255)),
 		validation.Field(&m.Price, validation.Required),
 		validation.Field(&m.OutletID, validation.Required, is.UUIDv4),
+		validation.Field(&m.CategoryID, is.UUID),
 	)
 }
+
+// GroupDTO is menus of a category on grouped response, uncategorized menus are grouped last without category
+type GroupDTO struct {
+	Category *menucategory.CategoryDTO `json:"category"`
+	Menus    []MenuDTO                 `json:"menus"`
+}
@@ -0,0 +1,103 @@
+package menurepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menu"
+	"github.com/goplateframework/internal/domain/menu/menuweb"
+	"github.com/goplateframework/internal/domain/outlet/outletrepo"
+	"github.com/goplateframework/pkg/cacheaside"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+const menusExpires = 5 * time.Minute
+
+// cachedRepository serves menu pages through cache-aside, pages are tagged by their outlet,
+// so writes on a menu invalidate every cached page of the outlet which the menu belongs to
+type cachedRepository struct {
+	*repository
+	client *redis.Client
+	log    *logger.Log
+	menus  *cacheaside.Cache[[]menu.MenuDTO]
+}
+
+func NewCachedDB(db *sqlx.DB, client *redis.Client, log *logger.Log) *cachedRepository {
+	return &cachedRepository{
+		repository: NewDB(db),
+		client:     client,
+		log:        log,
+		menus:      cacheaside.New[[]menu.MenuDTO](client, "menus", menusExpires),
+	}
+}
+
+func (repo *cachedRepository) GetAll(ctx context.Context, qp *menuweb.QueryParams) ([]menu.MenuDTO, error) {
+	key := fmt.Sprintf("%s:%d:%d:%s:%s:%s:%s",
+		qp.Filter.OutletId, qp.Page.Number, qp.Page.Size, qp.OrderBy.Field, qp.OrderBy.Direction, qp.Filter.CategoryId, qp.Filter.Name)
+
+	return repo.menus.Fetch(ctx, key, func(ctx context.Context) ([]menu.MenuDTO, error) {
+		return repo.repository.GetAll(ctx, qp)
+	}, func([]menu.MenuDTO) []string {
+		tags := []string{menusTag(qp.Filter.OutletId)}
+
+		// menus of a deleted outlet go along with it
+		if outletID, err := uuid.Parse(qp.Filter.OutletId); err == nil {
+			tags = append(tags, outletrepo.OutletTag(outletID))
+		}
+
+		return tags
+	})
+}
+
+func (repo *cachedRepository) Create(ctx context.Context, m *menu.MenuDTO) error {
+	if err := repo.repository.Create(ctx, m); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, menusTag(m.OutletID))
+	return nil
+}
+
+func (repo *cachedRepository) Update(ctx context.Context, m *menu.MenuDTO) error {
+	if err := repo.repository.Update(ctx, m); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, menusTag(m.OutletID))
+	return nil
+}
+
+func (repo *cachedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m, err := repo.repository.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, menusTag(m.OutletID))
+	return nil
+}
+
+// InvalidateOutletMenus removes every cached menu page of an outlet, it is meant for writes which
+// do not go through this repository, such as image url written by worker
+func (repo *cachedRepository) InvalidateOutletMenus(ctx context.Context, outletID string) error {
+	return cacheaside.InvalidateTags(ctx, repo.client, menusTag(outletID))
+}
+
+// invalidate only logs failures, since the write has already been committed and cached entries expire on their own
+func (repo *cachedRepository) invalidate(ctx context.Context, tags ...string) {
+	if err := cacheaside.InvalidateTags(ctx, repo.client, tags...); err != nil {
+		repo.log.Errorf("failed to invalidate cache tags %v, %v", tags, err)
+	}
+}
+
+func menusTag(outletID string) string {
+	return fmt.Sprintf("menus:outlet:%s", outletID)
+}
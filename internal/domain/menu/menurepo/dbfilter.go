@@ -0,0 +1,20 @@
+package menurepo
+
+import "github.com/goplateframework/internal/domain/menu/menuweb"
+
+// buildFilter always scopes menus into an outlet, outlet_id should be set on args by caller
+func (dbrepo *repository) buildFilter(args map[string]any, qp *menuweb.QueryParams) string {
+	q := " WHERE outlet_id = :outlet_id"
+
+	if qp.Filter.Name != "" {
+		q += " AND name LIKE :name"
+		args["name"] = "%" + qp.Filter.Name + "%"
+	}
+
+	if qp.Filter.CategoryId != "" {
+		q += " AND category_id = :category_id"
+		args["category_id"] = qp.Filter.CategoryId
+	}
+
+	return q
+}
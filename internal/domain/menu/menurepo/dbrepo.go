@@ -19,15 +19,41 @@ func NewDB(db *sqlx.DB) *repository {
 	return &repository{db}
 }
 
+// Create places new menu last on its category, position of given menu is filled accordingly
 func (dbrepo *repository) Create(ctx context.Context, m *menu.MenuDTO) error {
 	q := `
 	INSERT INTO menus 
-		(id, name, description, price, is_available, image_url, outlet_id, created_at, updated_at)
+		(id, name, description, price, is_available, image_url, outlet_id, category_id, position, created_at, updated_at)
 	VALUES 
-		(:id, :name, :description, :price, :is_available, :image_url, :outlet_id, :created_at, :updated_at)`
+		(:id, :name, :description, :price, :is_available, :image_url, :outlet_id, :category_id,
+		` + lastPosition + `,
+		:created_at, :updated_at)
+	RETURNING position`
 
-	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(m))
-	return err
+	return dbrepo.namedPosition(ctx, q, m)
+}
+
+// lastPosition is position after every menu of the category, uncategorized menus are all on zero position
+const lastPosition = `(
+	SELECT COALESCE(MAX(c.position) + 1, 0) FROM menus c
+	WHERE CAST(:category_id AS uuid) IS NOT NULL AND c.category_id = :category_id
+)`
+
+// namedPosition runs a write which returns position of the menu, then fills it into given menu
+func (dbrepo *repository) namedPosition(ctx context.Context, q string, m *menu.MenuDTO) error {
+	rows, err := dbrepo.NamedQueryContext(ctx, q, intoModel(m))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&m.Position); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
 }
 
 func (dbrepo *repository) GetAll(ctx context.Context, qp *menuweb.QueryParams) ([]menu.MenuDTO, error) {
@@ -40,15 +66,10 @@ func (dbrepo *repository) GetAll(ctx context.Context, qp *menuweb.QueryParams) (
 	var qb strings.Builder
 	qb.WriteString(`
 		SELECT * FROM menus
-		WHERE outlet_id = :outlet_id
 	`)
 
-	if qp.Filter.Name != "" {
-		qb.WriteString(" AND name LIKE :name")
-		args["name"] = "%" + qp.Filter.Name + "%"
-	}
-
-	qb.WriteString(fmt.Sprintf(" ORDER BY %s %s", qp.OrderBy.Field, qp.OrderBy.Direction))
+	qb.WriteString(dbrepo.buildFilter(args, qp))
+	qb.WriteString(fmt.Sprintf(" ORDER BY %s %s, id", qp.OrderBy.Field, qp.OrderBy.Direction))
 	qb.WriteString(" OFFSET :offset LIMIT :size")
 
 	rows, err := dbrepo.NamedQueryContext(ctx, qb.String(), args)
@@ -82,6 +103,32 @@ func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*menu.MenuD
 	return m.intoDTO(), nil
 }
 
+// GetMany returns menus of given ids, menus which do not exist are left out
+func (dbrepo *repository) GetMany(ctx context.Context, ids []uuid.UUID) ([]menu.MenuDTO, error) {
+	q, args, err := sqlx.In(`SELECT * FROM menus WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbrepo.QueryxContext(ctx, dbrepo.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var menus []menu.MenuDTO
+	for rows.Next() {
+		m := new(Model)
+		if err := rows.StructScan(m); err != nil {
+			return nil, err
+		}
+		menus = append(menus, *m.intoDTO())
+	}
+
+	return menus, nil
+}
+
+// Update keeps position of the menu as long as it stays on its category, otherwise it is placed last on the new one
 func (dbrepo *repository) Update(ctx context.Context, nm *menu.MenuDTO) error {
 	q := `
 	UPDATE 
@@ -92,11 +139,13 @@ func (dbrepo *repository) Update(ctx context.Context, nm *menu.MenuDTO) error {
 		price = :price,
 		is_available = :is_available,
 		image_url = :image_url,
+		position = CASE WHEN category_id IS NOT DISTINCT FROM CAST(:category_id AS uuid) THEN position ELSE ` + lastPosition + ` END,
+		category_id = :category_id,
 		updated_at = :updated_at
-	WHERE id = :id`
+	WHERE id = :id
+	RETURNING position`
 
-	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(nm))
-	return err
+	return dbrepo.namedPosition(ctx, q, nm)
 }
 
 func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -106,19 +155,52 @@ func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
-func (dbrepo *repository) Count(ctx context.Context, outletId string) (int, error) {
-	q := `
-	SELECT COUNT(*) AS total FROM menus
-	WHERE outlet_id = $1`
+// Count returns total of menus which match filters of given query params
+func (dbrepo *repository) Count(ctx context.Context, qp *menuweb.QueryParams) (int, error) {
+	args := map[string]any{
+		"outlet_id": qp.Filter.OutletId,
+	}
+	q := "SELECT COUNT(*) AS total FROM menus" + dbrepo.buildFilter(args, qp)
+
+	stmt, err := dbrepo.PrepareNamedContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
 
 	var count struct {
 		Total int `db:"total"`
 	}
 
-	err := dbrepo.GetContext(ctx, &count, q, outletId)
-	if err != nil {
+	if err := stmt.GetContext(ctx, &count, args); err != nil {
 		return 0, err
 	}
 
 	return count.Total, nil
 }
+
+// GetArranged returns every menu which matches filters of given query params, ordered by position within their category
+// regardless of pagination, so they could be grouped by category
+func (dbrepo *repository) GetArranged(ctx context.Context, qp *menuweb.QueryParams) ([]menu.MenuDTO, error) {
+	args := map[string]any{
+		"outlet_id": qp.Filter.OutletId,
+	}
+	q := "SELECT * FROM menus" + dbrepo.buildFilter(args, qp) + " ORDER BY position, name, id"
+
+	rows, err := dbrepo.NamedQueryContext(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	menus := []menu.MenuDTO{}
+	for rows.Next() {
+		m := new(Model)
+		if err := rows.StructScan(m); err != nil {
+			return nil, err
+		}
+		menus = append(menus, *m.intoDTO())
+	}
+
+	return menus, nil
+}
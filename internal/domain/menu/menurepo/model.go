@@ -8,15 +8,17 @@ import (
 )
 
 type Model struct {
-	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Description string    `db:"description"`
-	Price       float64   `db:"price"`
-	IsAvailable bool      `db:"is_available"`
-	ImageURL    string    `db:"image_url"`
-	OutletID    string    `db:"outlet_id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID          uuid.UUID  `db:"id"`
+	Name        string     `db:"name"`
+	Description string     `db:"description"`
+	Price       float64    `db:"price"`
+	IsAvailable bool       `db:"is_available"`
+	ImageURL    string     `db:"image_url"`
+	OutletID    string     `db:"outlet_id"`
+	CategoryID  *uuid.UUID `db:"category_id"`
+	Position    int        `db:"position"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
 }
 
 func intoModel(m *menu.MenuDTO) *Model {
@@ -28,6 +30,8 @@ func intoModel(m *menu.MenuDTO) *Model {
 		IsAvailable: m.IsAvailable,
 		ImageURL:    m.ImageURL,
 		OutletID:    m.OutletID,
+		CategoryID:  m.CategoryID,
+		Position:    m.Position,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
@@ -42,6 +46,8 @@ func (m *Model) intoDTO() *menu.MenuDTO {
 		IsAvailable: m.IsAvailable,
 		ImageURL:    m.ImageURL,
 		OutletID:    m.OutletID,
+		CategoryID:  m.CategoryID,
+		Position:    m.Position,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
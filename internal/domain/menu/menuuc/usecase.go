@@ -10,7 +10,10 @@ import (
 	"github.com/goplateframework/config"
 	"github.com/goplateframework/internal/domain/menu"
 	"github.com/goplateframework/internal/domain/menu/menuweb"
+	"github.com/goplateframework/internal/domain/menucategory"
+	"github.com/goplateframework/internal/domain/outletstaff"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/web/result"
 	"github.com/goplateframework/internal/worker/pb"
 	"github.com/goplateframework/pkg/logger"
@@ -23,26 +26,55 @@ type iRepository interface {
 	GetOne(ctx context.Context, id uuid.UUID) (*menu.MenuDTO, error)
 	Update(ctx context.Context, nm *menu.MenuDTO) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	Count(ctx context.Context, outletId string) (int, error)
+	Count(ctx context.Context, qp *menuweb.QueryParams) (int, error)
+	GetArranged(ctx context.Context, qp *menuweb.QueryParams) ([]menu.MenuDTO, error)
+	InvalidateOutletMenus(ctx context.Context, outletID string) error
+}
+
+// required category repository methods to place menus into categories of their own outlet
+type iCategoryRepository interface {
+	GetOne(ctx context.Context, id uuid.UUID) (*menucategory.CategoryDTO, error)
+	GetAll(ctx context.Context, outletID uuid.UUID) ([]menucategory.CategoryDTO, error)
+}
+
+// required staff usecase methods to scope menu mutation into staff of its outlet
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
 }
 
 type Usecase struct {
-	conf       *config.Config
-	log        *logger.Log
-	menuDBRepo iRepository
-	worker     pb.WorkerClient
+	conf         *config.Config
+	log          *logger.Log
+	menuDBRepo   iRepository
+	categoryRepo iCategoryRepository
+	staffUC      iStaffUsecase
+	worker       pb.WorkerClient
 }
 
-func New(conf *config.Config, log *logger.Log, worker pb.WorkerClient, menuDBRepo iRepository) *Usecase {
+func New(conf *config.Config, log *logger.Log, worker pb.WorkerClient, menuDBRepo iRepository, categoryRepo iCategoryRepository, staffUC iStaffUsecase) *Usecase {
 	return &Usecase{
-		conf:       conf,
-		log:        log,
-		menuDBRepo: menuDBRepo,
-		worker:     worker,
+		conf:         conf,
+		log:          log,
+		menuDBRepo:   menuDBRepo,
+		categoryRepo: categoryRepo,
+		staffUC:      staffUC,
+		worker:       worker,
 	}
 }
 
-func (uc *Usecase) Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byte) (*menu.MenuDTO, error) {
+// menus are managed by outlet managers and kitchen staff
+var menuStaffRoles = []string{outletstaff.RoleManager, outletstaff.RoleKitchen}
+
+func (uc *Usecase) Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menu.MenuDTO, error) {
+	if err := uc.staffUC.Authorize(ctx, claims, uuid.MustParse(nm.OutletID), menuStaffRoles...); err != nil {
+		return nil, err
+	}
+
+	categoryID, err := uc.category(ctx, nm.CategoryID, nm.OutletID)
+	if err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 
 	id := uuid.New()
@@ -54,6 +86,7 @@ func (uc *Usecase) Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byt
 		IsAvailable: nm.IsAvailable,
 		ImageURL:    "pending",
 		OutletID:    nm.OutletID,
+		CategoryID:  categoryID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -74,6 +107,12 @@ func (uc *Usecase) Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byt
 
 		if err != nil {
 			uc.log.Error(err.Error())
+			return
+		}
+
+		// image url is written by worker, so cached menus still hold the pending one
+		if err := uc.menuDBRepo.InvalidateOutletMenus(workerCtx, m.OutletID); err != nil {
+			uc.log.Errorf("failed to invalidate menus of outlet %s, %v", m.OutletID, err)
 		}
 	}()
 
@@ -81,12 +120,12 @@ func (uc *Usecase) Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byt
 }
 
 func (uc *Usecase) GetAll(ctx context.Context, qp *menuweb.QueryParams) (*result.Result[menu.MenuDTO], error) {
-	total, err := uc.menuDBRepo.Count(ctx, qp.Filter.OutletId)
+	total, err := uc.menuDBRepo.Count(ctx, qp)
 	if err != nil {
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
-	if !qp.Page.CanPaginate(total) {
+	if total > 0 && !qp.Page.CanPaginate(total) {
 		e := errshttp.New(errshttp.InvalidArgument, "Page requested is out of range")
 		e.AddDetail(fmt.Sprintf("pagination: page number must be between 1 and %d", total))
 		return nil, e
@@ -100,7 +139,99 @@ func (uc *Usecase) GetAll(ctx context.Context, qp *menuweb.QueryParams) (*result
 	return result.New(m, total, qp.Page.Number, qp.Page.Size), nil
 }
 
-func (uc *Usecase) Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID, image *[]byte) (*menu.MenuDTO, error) {
+// GetGrouped returns every matching menu of an outlet grouped by their category, groups follow position of categories
+// and uncategorized menus come last. Empty categories are kept, so clients could show them as they are arranged
+func (uc *Usecase) GetGrouped(ctx context.Context, qp *menuweb.QueryParams) ([]menu.GroupDTO, error) {
+	categories, err := uc.categoryRepo.GetAll(ctx, uuid.MustParse(qp.Filter.OutletId))
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	menus, err := uc.menuDBRepo.GetArranged(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	groups := make([]menu.GroupDTO, 0, len(categories)+1)
+	index := make(map[uuid.UUID]int, len(categories))
+	for i := range categories {
+		// filtering by category only leaves its own group
+		if qp.Filter.CategoryId != "" && categories[i].ID.String() != qp.Filter.CategoryId {
+			continue
+		}
+
+		index[categories[i].ID] = len(groups)
+		groups = append(groups, menu.GroupDTO{Category: &categories[i], Menus: []menu.MenuDTO{}})
+	}
+
+	uncategorized := menu.GroupDTO{Menus: []menu.MenuDTO{}}
+	for _, m := range menus {
+		if m.CategoryID != nil {
+			if i, ok := index[*m.CategoryID]; ok {
+				groups[i].Menus = append(groups[i].Menus, m)
+				continue
+			}
+		}
+		uncategorized.Menus = append(uncategorized.Menus, m)
+	}
+
+	if len(uncategorized.Menus) > 0 {
+		groups = append(groups, uncategorized)
+	}
+
+	return groups, nil
+}
+
+// category makes sure given category belongs to outlet of the menu, empty category leaves the menu uncategorized
+func (uc *Usecase) category(ctx context.Context, categoryID, outletID string) (*uuid.UUID, error) {
+	if categoryID == "" {
+		return nil, nil
+	}
+
+	id := uuid.MustParse(categoryID)
+
+	c, err := uc.categoryRepo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Category not found")
+			e.AddDetail(fmt.Sprintf("data: category with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if c.OutletID.String() != outletID {
+		e := errshttp.New(errshttp.FailedPrecondition, "Category belongs to another outlet")
+		e.AddDetail("category_id: must be a category of the same outlet as the menu")
+		return nil, e
+	}
+
+	return &id, nil
+}
+
+func (uc *Usecase) Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menu.MenuDTO, error) {
+	existing, err := uc.menuDBRepo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("data: menu with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	// authorize against outlet which menu belongs to, rather than the one given by client
+	if err := uc.staffUC.Authorize(ctx, claims, uuid.MustParse(existing.OutletID), menuStaffRoles...); err != nil {
+		return nil, err
+	}
+
+	categoryID, err := uc.category(ctx, nm.CategoryID, existing.OutletID)
+	if err != nil {
+		return nil, err
+	}
+
 	if image != nil {
 		nm.ImageURL = "pending"
 	}
@@ -112,7 +243,9 @@ func (uc *Usecase) Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID
 		Price:       nm.Price,
 		IsAvailable: nm.IsAvailable,
 		ImageURL:    nm.ImageURL,
-		OutletID:    nm.OutletID,
+		OutletID:    existing.OutletID,
+		CategoryID:  categoryID,
+		CreatedAt:   existing.CreatedAt,
 		UpdatedAt:   time.Now(),
 	}
 
@@ -133,6 +266,12 @@ func (uc *Usecase) Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID
 
 			if err != nil {
 				uc.log.Error(err.Error())
+				return
+			}
+
+			// image url is written by worker, so cached menus still hold the pending one
+			if err := uc.menuDBRepo.InvalidateOutletMenus(workerCtx, m.OutletID); err != nil {
+				uc.log.Errorf("failed to invalidate menus of outlet %s, %v", m.OutletID, err)
 			}
 		}()
 	}
@@ -140,13 +279,21 @@ func (uc *Usecase) Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID
 	return m, nil
 }
 
-func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID) error {
+func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
 	m, err := uc.menuDBRepo.GetOne(ctx, id)
 
 	if err == sql.ErrNoRows {
 		return errshttp.New(errshttp.NotFound, "Menu topping not found")
 	}
 
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.staffUC.Authorize(ctx, claims, uuid.MustParse(m.OutletID), menuStaffRoles...); err != nil {
+		return err
+	}
+
 	if err := uc.menuDBRepo.Delete(ctx, id); err != nil {
 		return errshttp.New(errshttp.Internal, "Something went wrong")
 	}
@@ -6,9 +6,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/sdk/validate"
 	"github.com/goplateframework/internal/web/formfile"
 	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/internal/web/webcontext"
 	"github.com/goplateframework/pkg/logger"
 	"github.com/labstack/echo/v4"
 
@@ -17,10 +19,11 @@ import (
 
 // required usecase methods which this controller needs to operate the business logic
 type iUsecase interface {
-	Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byte) (*menu.MenuDTO, error)
+	Create(ctx context.Context, nm *menu.NewMenuDTO, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menu.MenuDTO, error)
 	GetAll(ctx context.Context, qp *QueryParams) (*result.Result[menu.MenuDTO], error)
-	Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID, image *[]byte) (*menu.MenuDTO, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	GetGrouped(ctx context.Context, qp *QueryParams) ([]menu.GroupDTO, error)
+	Update(ctx context.Context, nm *menu.NewMenuDTO, id uuid.UUID, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menu.MenuDTO, error)
+	Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
 }
 
 type controller struct {
@@ -66,7 +69,9 @@ func (con *controller) create(c echo.Context) error {
 		return e
 	}
 
-	m, err := con.menuUC.Create(c.Request().Context(), nm, &menuImage)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	m, err := con.menuUC.Create(c.Request().Context(), nm, &menuImage, claims)
 	if err != nil {
 		return err
 	}
@@ -83,6 +88,15 @@ func (con *controller) getAll(c echo.Context) error {
 		return e
 	}
 
+	if qp.GroupBy == "category" {
+		g, err := con.menuUC.GetGrouped(c.Request().Context(), qp)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, g)
+	}
+
 	m, err := con.menuUC.GetAll(c.Request().Context(), qp)
 	if err != nil {
 		return err
@@ -139,7 +153,9 @@ func (con *controller) update(c echo.Context) error {
 		menuImage = &mi
 	}
 
-	m, err := con.menuUC.Update(c.Request().Context(), nm, id, menuImage)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	m, err := con.menuUC.Update(c.Request().Context(), nm, id, menuImage, claims)
 	if err != nil {
 		return err
 	}
@@ -155,7 +171,9 @@ func (con *controller) delete(c echo.Context) error {
 		return e
 	}
 
-	err = con.menuUC.Delete(c.Request().Context(), id)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	err = con.menuUC.Delete(c.Request().Context(), id, claims)
 	if err != nil {
 		return err
 	}
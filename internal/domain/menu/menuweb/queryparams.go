@@ -10,20 +10,24 @@ import (
 
 // Supported query params for this menu web layer
 type UnparsedQueryParams struct {
-	page     string
-	size     string
-	orderBy  string
-	outletId string
-	name     string
+	page       string
+	size       string
+	orderBy    string
+	outletId   string
+	name       string
+	categoryId string
+	groupBy    string // category
 }
 
 func getQueryParams(c echo.Context) *UnparsedQueryParams {
 	return &UnparsedQueryParams{
-		page:     c.QueryParam("page"),
-		size:     c.QueryParam("size"),
-		orderBy:  c.QueryParam("order_by"),
-		outletId: c.QueryParam("outlet_id"),
-		name:     c.QueryParam("name"),
+		page:       c.QueryParam("page"),
+		size:       c.QueryParam("size"),
+		orderBy:    c.QueryParam("order_by"),
+		outletId:   c.QueryParam("outlet_id"),
+		name:       c.QueryParam("name"),
+		categoryId: c.QueryParam("category_id"),
+		groupBy:    c.QueryParam("group_by"),
 	}
 }
 
@@ -32,10 +36,14 @@ type QueryParams struct {
 	Page    *queryparams.Page
 	OrderBy *queryparams.OrderBy
 	Filter  struct {
-		OutletId string
-		LastId   string
-		Name     string
+		OutletId   string
+		LastId     string
+		Name       string
+		CategoryId string
 	}
+
+	// GroupBy is only category so far, grouped response holds every matching menu instead of a page of them
+	GroupBy string
 }
 
 func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
@@ -45,11 +53,12 @@ func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
 		return nil, err
 	}
 
-	if err := uqp.setOrderBy(qp); err != nil {
+	// filter goes first, since menus of a category are ordered by their position
+	if err := uqp.setFilter(qp); err != nil {
 		return nil, err
 	}
 
-	if err := uqp.setFilter(qp); err != nil {
+	if err := uqp.setOrderBy(qp); err != nil {
 		return nil, err
 	}
 
@@ -66,7 +75,7 @@ func (uqp *UnparsedQueryParams) setPage(qp *QueryParams) error {
 	return nil
 }
 
-var allowedOrderByFields = []string{"name", "price", "created_at"}
+var allowedOrderByFields = []string{"name", "price", "created_at", "position"}
 
 func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
 	defaultOrderBy := queryparams.NewOrderBy(
@@ -74,6 +83,10 @@ func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
 		queryparams.AscOrder,
 	)
 
+	if qp.Filter.CategoryId != "" {
+		defaultOrderBy = queryparams.NewOrderBy("position", queryparams.AscOrder)
+	}
+
 	orderBy, err := queryparams.ParseOrderBy(allowedOrderByFields, uqp.orderBy, defaultOrderBy)
 	if err != nil {
 		return err
@@ -98,5 +111,18 @@ func (uqp *UnparsedQueryParams) setFilter(qp *QueryParams) error {
 
 	qp.Filter.Name = uqp.name
 
+	if uqp.categoryId != "" {
+		categoryId, err := uuid.Parse(uqp.categoryId)
+		if err != nil {
+			return errors.New("filter: category_id is not valid")
+		}
+		qp.Filter.CategoryId = categoryId.String()
+	}
+
+	if uqp.groupBy != "" && uqp.groupBy != "category" {
+		return errors.New("group_by: must be 'category'")
+	}
+	qp.GroupBy = uqp.groupBy
+
 	return nil
 }
@@ -2,6 +2,7 @@ package menuweb
 
 import (
 	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
 	"github.com/goplateframework/pkg/logger"
 )
 
@@ -13,7 +14,7 @@ type Options struct {
 func Route(web *web.Web, opts *Options) {
 	con := newController(opts.MenuUC, opts.Log)
 
-	g := web.Echo.Group("/api/v1/menu", web.Mid.Authenticated)
+	g := web.Echo.Group("/api/v1/menu", web.Mid.Authenticated, web.Mid.Authorize(middlewares.ReadAnyWriteAdmin))
 	g.POST("", con.create)
 	g.GET("", con.getAll)
 	g.PUT("/:id", con.update)
@@ -0,0 +1,83 @@
+package menucategory
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// CategoryDTO is what we send to client
+type CategoryDTO struct {
+	ID        uuid.UUID `json:"id"`
+	OutletID  uuid.UUID `json:"outlet_id"`
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCategoryDTO is what client should send to create new category, it is placed after every other category of the outlet
+type NewCategoryDTO struct {
+	OutletID string `json:"outlet_id"`
+	Name     string `json:"name"`
+}
+
+func (d NewCategoryDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.OutletID, validation.Required, is.UUID),
+		validation.Field(&d.Name, validation.Required, validation.Length(1, 50)),
+	)
+}
+
+// UpdateCategoryDTO is what client should send to rename a category, position is changed through reorder instead
+type UpdateCategoryDTO struct {
+	Name string `json:"name"`
+}
+
+func (d UpdateCategoryDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Name, validation.Required, validation.Length(1, 50)),
+	)
+}
+
+// ReorderDTO is what client should send to reorder every category of an outlet, categories are positioned as they are listed
+type ReorderDTO struct {
+	OutletID    string   `json:"outlet_id"`
+	CategoryIDs []string `json:"category_ids"`
+}
+
+func (d ReorderDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.OutletID, validation.Required, is.UUID),
+		validation.Field(&d.CategoryIDs, validation.Required, validation.Length(1, 100), validation.Each(is.UUID), validation.By(unique)),
+	)
+}
+
+// ArrangeMenusDTO is what client should send to set menus of a category, menus are positioned as they are listed.
+// Menus which are in the category yet left out of the list are no longer categorized
+type ArrangeMenusDTO struct {
+	MenuIDs []string `json:"menu_ids"`
+}
+
+func (d ArrangeMenusDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuIDs, validation.Length(0, 200), validation.Each(is.UUID), validation.By(unique)),
+	)
+}
+
+func unique(value interface{}) error {
+	ids, _ := value.([]string)
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return errors.New("must not contain duplicates")
+		}
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
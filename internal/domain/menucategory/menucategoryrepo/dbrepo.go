@@ -0,0 +1,211 @@
+package menucategoryrepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menucategory"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrCategoriesMismatch is returned whenever reorder does not list every category of the outlet exactly once
+	ErrCategoriesMismatch = errors.New("given categories do not match categories of the outlet")
+
+	// ErrMenusMismatch is returned whenever menus which are arranged into a category do not belong to its outlet
+	ErrMenusMismatch = errors.New("given menus do not belong to outlet of the category")
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// Create places new category after every other category of its outlet, position of given category is filled accordingly
+func (dbrepo *repository) Create(ctx context.Context, c *menucategory.CategoryDTO) error {
+	q := `
+	INSERT INTO menu_categories
+		(id, outlet_id, name, position, created_at, updated_at)
+	VALUES
+		(:id, :outlet_id, :name,
+		(SELECT COALESCE(MAX(position) + 1, 0) FROM menu_categories WHERE outlet_id = :outlet_id),
+		:created_at, :updated_at)
+	RETURNING position`
+
+	rows, err := dbrepo.NamedQueryContext(ctx, q, intoModel(c))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&c.Position); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*menucategory.CategoryDTO, error) {
+	m := new(Model)
+
+	q := `SELECT * FROM menu_categories WHERE id = $1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	return m.intoDTO(), nil
+}
+
+// GetAll returns every category of an outlet by their position
+func (dbrepo *repository) GetAll(ctx context.Context, outletID uuid.UUID) ([]menucategory.CategoryDTO, error) {
+	q := `
+	SELECT * FROM menu_categories
+	WHERE outlet_id = $1
+	ORDER BY position, name`
+
+	models := []Model{}
+	if err := dbrepo.SelectContext(ctx, &models, q, outletID); err != nil {
+		return nil, err
+	}
+
+	categories := make([]menucategory.CategoryDTO, 0, len(models))
+	for i := range models {
+		categories = append(categories, *models[i].intoDTO())
+	}
+
+	return categories, nil
+}
+
+// NameExists tells whether an outlet already has a category of given name other than the excluded one, names are case insensitive
+func (dbrepo *repository) NameExists(ctx context.Context, outletID uuid.UUID, name string, excludeID uuid.UUID) (bool, error) {
+	var exists bool
+
+	q := `SELECT EXISTS (SELECT 1 FROM menu_categories WHERE outlet_id = $1 AND lower(name) = lower($2) AND id <> $3)`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, outletID, name, excludeID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (dbrepo *repository) Update(ctx context.Context, c *menucategory.CategoryDTO) error {
+	q := `
+	UPDATE
+		menu_categories
+	SET
+		name = :name,
+		updated_at = :updated_at
+	WHERE id = :id`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(c))
+	return err
+}
+
+// Delete removes a category, its menus are left uncategorized
+func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbrepo.ExecContext(ctx, `DELETE FROM menu_categories WHERE id = $1`, id)
+	return err
+}
+
+// Reorder positions every category of an outlet as given ids are ordered, ErrCategoriesMismatch is returned
+// whenever given ids are not exactly the categories of the outlet
+func (dbrepo *repository) Reorder(ctx context.Context, outletID uuid.UUID, ids []uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current []uuid.UUID
+	if err := tx.SelectContext(ctx, &current, `SELECT id FROM menu_categories WHERE outlet_id = $1 FOR UPDATE`, outletID); err != nil {
+		return err
+	}
+
+	if !sameSet(current, ids) {
+		return ErrCategoriesMismatch
+	}
+
+	q := `UPDATE menu_categories SET position = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	for i, id := range ids {
+		if _, err := tx.ExecContext(ctx, q, i, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ArrangeMenus makes given menus the only menus of a category, positioned as they are ordered. ErrMenusMismatch is returned
+// whenever any of them does not belong to the outlet of the category
+func (dbrepo *repository) ArrangeMenus(ctx context.Context, c *menucategory.CategoryDTO, menuIDs []uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(menuIDs) > 0 {
+		q, args, err := sqlx.In(`SELECT COUNT(DISTINCT id) FROM menus WHERE outlet_id = ? AND id IN (?)`, c.OutletID, menuIDs)
+		if err != nil {
+			return err
+		}
+
+		var found int
+		if err := tx.QueryRowxContext(ctx, tx.Rebind(q), args...).Scan(&found); err != nil {
+			return err
+		}
+
+		if found != len(distinct(menuIDs)) {
+			return ErrMenusMismatch
+		}
+	}
+
+	q := `UPDATE menus SET category_id = NULL, position = 0 WHERE category_id = $1`
+
+	if _, err := tx.ExecContext(ctx, q, c.ID); err != nil {
+		return err
+	}
+
+	q = `UPDATE menus SET category_id = $1, position = $2 WHERE id = $3`
+
+	for i, id := range menuIDs {
+		if _, err := tx.ExecContext(ctx, q, c.ID, i, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func sameSet(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := distinct(a)
+	for _, id := range b {
+		if _, ok := set[id]; !ok {
+			return false
+		}
+		delete(set, id)
+	}
+
+	return len(set) == 0
+}
+
+func distinct(ids []uuid.UUID) map[uuid.UUID]struct{} {
+	set := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
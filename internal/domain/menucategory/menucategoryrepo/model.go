@@ -0,0 +1,39 @@
+package menucategoryrepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menucategory"
+)
+
+type Model struct {
+	ID        uuid.UUID `db:"id"`
+	OutletID  uuid.UUID `db:"outlet_id"`
+	Name      string    `db:"name"`
+	Position  int       `db:"position"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func intoModel(c *menucategory.CategoryDTO) *Model {
+	return &Model{
+		ID:        c.ID,
+		OutletID:  c.OutletID,
+		Name:      c.Name,
+		Position:  c.Position,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func (m *Model) intoDTO() *menucategory.CategoryDTO {
+	return &menucategory.CategoryDTO{
+		ID:        m.ID,
+		OutletID:  m.OutletID,
+		Name:      m.Name,
+		Position:  m.Position,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
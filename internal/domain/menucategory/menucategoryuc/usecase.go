@@ -0,0 +1,235 @@
+package menucategoryuc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/menucategory"
+	"github.com/goplateframework/internal/domain/menucategory/menucategoryrepo"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/pkg/logger"
+)
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	Create(ctx context.Context, c *menucategory.CategoryDTO) error
+	GetOne(ctx context.Context, id uuid.UUID) (*menucategory.CategoryDTO, error)
+	GetAll(ctx context.Context, outletID uuid.UUID) ([]menucategory.CategoryDTO, error)
+	NameExists(ctx context.Context, outletID uuid.UUID, name string, excludeID uuid.UUID) (bool, error)
+	Update(ctx context.Context, c *menucategory.CategoryDTO) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Reorder(ctx context.Context, outletID uuid.UUID, ids []uuid.UUID) error
+	ArrangeMenus(ctx context.Context, c *menucategory.CategoryDTO, menuIDs []uuid.UUID) error
+}
+
+// category and position of menus are held by menus table, so cached menus of the outlet go stale whenever they change
+type iMenuRepository interface {
+	InvalidateOutletMenus(ctx context.Context, outletID string) error
+}
+
+// required staff usecase methods to scope category mutation into staff of its outlet
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
+}
+
+type Usecase struct {
+	conf     *config.Config
+	log      *logger.Log
+	repo     iRepository
+	menuRepo iMenuRepository
+	staffUC  iStaffUsecase
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, menuRepo iMenuRepository, staffUC iStaffUsecase) *Usecase {
+	return &Usecase{
+		conf:     conf,
+		log:      log,
+		repo:     repo,
+		menuRepo: menuRepo,
+		staffUC:  staffUC,
+	}
+}
+
+// categories are managed by the same staff who manage menus
+var categoryStaffRoles = []string{outletstaff.RoleManager, outletstaff.RoleKitchen}
+
+func (uc *Usecase) Create(ctx context.Context, nc *menucategory.NewCategoryDTO, claims *tokenutil.AccessTokenClaims) (*menucategory.CategoryDTO, error) {
+	outletID := uuid.MustParse(nc.OutletID)
+
+	if err := uc.staffUC.Authorize(ctx, claims, outletID, categoryStaffRoles...); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkName(ctx, outletID, nc.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	c := &menucategory.CategoryDTO{
+		ID:        uuid.New(),
+		OutletID:  outletID,
+		Name:      nc.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.repo.Create(ctx, c); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return c, nil
+}
+
+func (uc *Usecase) GetAll(ctx context.Context, outletID uuid.UUID) ([]menucategory.CategoryDTO, error) {
+	categories, err := uc.repo.GetAll(ctx, outletID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return categories, nil
+}
+
+func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID) (*menucategory.CategoryDTO, error) {
+	c, err := uc.repo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Category not found")
+			e.AddDetail(fmt.Sprintf("data: category with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return c, nil
+}
+
+func (uc *Usecase) Update(ctx context.Context, id uuid.UUID, nc *menucategory.UpdateCategoryDTO, claims *tokenutil.AccessTokenClaims) (*menucategory.CategoryDTO, error) {
+	c, err := uc.GetOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.staffUC.Authorize(ctx, claims, c.OutletID, categoryStaffRoles...); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkName(ctx, c.OutletID, nc.Name, c.ID); err != nil {
+		return nil, err
+	}
+
+	c.Name = nc.Name
+	c.UpdatedAt = time.Now()
+
+	if err := uc.repo.Update(ctx, c); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return c, nil
+}
+
+// Delete removes a category, its menus are kept yet no longer categorized
+func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	c, err := uc.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.staffUC.Authorize(ctx, claims, c.OutletID, categoryStaffRoles...); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	uc.invalidateMenus(ctx, c.OutletID)
+
+	return nil
+}
+
+// Reorder positions every category of an outlet as they are listed, then returns them by their new position
+func (uc *Usecase) Reorder(ctx context.Context, r *menucategory.ReorderDTO, claims *tokenutil.AccessTokenClaims) ([]menucategory.CategoryDTO, error) {
+	outletID := uuid.MustParse(r.OutletID)
+
+	if err := uc.staffUC.Authorize(ctx, claims, outletID, categoryStaffRoles...); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(r.CategoryIDs))
+	for _, id := range r.CategoryIDs {
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	if err := uc.repo.Reorder(ctx, outletID, ids); err != nil {
+		if err == menucategoryrepo.ErrCategoriesMismatch {
+			e := errshttp.New(errshttp.FailedPrecondition, "Categories do not match categories of the outlet")
+			e.AddDetail("category_ids: must list every category of the outlet exactly once")
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.GetAll(ctx, outletID)
+}
+
+// ArrangeMenus sets menus of a category along with their position
+func (uc *Usecase) ArrangeMenus(ctx context.Context, id uuid.UUID, am *menucategory.ArrangeMenusDTO, claims *tokenutil.AccessTokenClaims) error {
+	c, err := uc.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.staffUC.Authorize(ctx, claims, c.OutletID, categoryStaffRoles...); err != nil {
+		return err
+	}
+
+	menuIDs := make([]uuid.UUID, 0, len(am.MenuIDs))
+	for _, id := range am.MenuIDs {
+		menuIDs = append(menuIDs, uuid.MustParse(id))
+	}
+
+	if err := uc.repo.ArrangeMenus(ctx, c, menuIDs); err != nil {
+		if err == menucategoryrepo.ErrMenusMismatch {
+			e := errshttp.New(errshttp.FailedPrecondition, "Menus do not belong to outlet of the category")
+			e.AddDetail("menu_ids: every menu must exist on the same outlet as the category")
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	uc.invalidateMenus(ctx, c.OutletID)
+
+	return nil
+}
+
+func (uc *Usecase) checkName(ctx context.Context, outletID uuid.UUID, name string, excludeID uuid.UUID) error {
+	exists, err := uc.repo.NameExists(ctx, outletID, name, excludeID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if exists {
+		e := errshttp.New(errshttp.AlreadyExists, "Category already exists")
+		e.AddDetail(fmt.Sprintf("name: outlet already has category named %s", name))
+		return e
+	}
+
+	return nil
+}
+
+// invalidateMenus only logs failures, since menus have been written and cached ones expire on their own
+func (uc *Usecase) invalidateMenus(ctx context.Context, outletID uuid.UUID) {
+	if err := uc.menuRepo.InvalidateOutletMenus(ctx, outletID.String()); err != nil {
+		uc.log.Errorf("failed to invalidate menus of outlet %s, %v", outletID, err)
+	}
+}
@@ -0,0 +1,209 @@
+package menucategoryweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menucategory"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// required usecase methods which this controller needs to operate the business logic
+type iUsecase interface {
+	Create(ctx context.Context, nc *menucategory.NewCategoryDTO, claims *tokenutil.AccessTokenClaims) (*menucategory.CategoryDTO, error)
+	GetAll(ctx context.Context, outletID uuid.UUID) ([]menucategory.CategoryDTO, error)
+	GetOne(ctx context.Context, id uuid.UUID) (*menucategory.CategoryDTO, error)
+	Update(ctx context.Context, id uuid.UUID, nc *menucategory.UpdateCategoryDTO, claims *tokenutil.AccessTokenClaims) (*menucategory.CategoryDTO, error)
+	Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+	Reorder(ctx context.Context, r *menucategory.ReorderDTO, claims *tokenutil.AccessTokenClaims) ([]menucategory.CategoryDTO, error)
+	ArrangeMenus(ctx context.Context, id uuid.UUID, am *menucategory.ArrangeMenusDTO, claims *tokenutil.AccessTokenClaims) error
+}
+
+type controller struct {
+	categoryUC iUsecase
+	log        *logger.Log
+}
+
+func newController(categoryUC iUsecase, log *logger.Log) *controller {
+	return &controller{categoryUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	nc := new(menucategory.NewCategoryDTO)
+
+	if err := c.Bind(nc); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := nc.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	cat, err := con.categoryUC.Create(c.Request().Context(), nc, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, cat)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	outletID, err := uuid.Parse(c.QueryParam("outlet_id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
+		e.AddDetail("filter: outlet_id is not valid")
+		return e
+	}
+
+	categories, err := con.categoryUC.GetAll(c.Request().Context(), outletID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, categories)
+}
+
+func (con *controller) getOne(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Category id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	cat, err := con.categoryUC.GetOne(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cat)
+}
+
+func (con *controller) update(c echo.Context) error {
+	nc := new(menucategory.UpdateCategoryDTO)
+
+	if err := c.Bind(nc); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := nc.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Category id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	cat, err := con.categoryUC.Update(c.Request().Context(), id, nc, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, cat)
+}
+
+func (con *controller) delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Category id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.categoryUC.Delete(c.Request().Context(), id, claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) reorder(c echo.Context) error {
+	r := new(menucategory.ReorderDTO)
+
+	if err := c.Bind(r); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := r.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	categories, err := con.categoryUC.Reorder(c.Request().Context(), r, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, categories)
+}
+
+func (con *controller) arrangeMenus(c echo.Context) error {
+	am := new(menucategory.ArrangeMenusDTO)
+
+	if err := c.Bind(am); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := am.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Category id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.categoryUC.ArrangeMenus(c.Request().Context(), id, am, claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
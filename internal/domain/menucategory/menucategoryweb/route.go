@@ -0,0 +1,25 @@
+package menucategoryweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log        *logger.Log
+	CategoryUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.CategoryUC, opts.Log)
+
+	g := web.Echo.Group("/api/v1/menu-categories", web.Mid.Authenticated, web.Mid.Authorize(middlewares.ReadAnyWriteAdmin))
+	g.POST("", con.create)
+	g.GET("", con.getAll)
+	g.PUT("/order", con.reorder)
+	g.GET("/:id", con.getOne)
+	g.PUT("/:id", con.update)
+	g.DELETE("/:id", con.delete)
+	g.PUT("/:id/menus", con.arrangeMenus)
+}
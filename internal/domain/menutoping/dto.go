@@ -9,15 +9,17 @@ import (
 )
 
 type MenuTopingsDTO struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Price       float64   `json:"price"`
-	IsAvailable bool      `json:"is_available"`
-	ImageURL    string    `json:"image_url"`
-	Stock       int       `json:"stock"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	MenuID      uuid.UUID `json:"menu_id"`
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Price       float64    `json:"price"`
+	IsAvailable bool       `json:"is_available"`
+	ImageURL    string     `json:"image_url"`
+	Stock       int        `json:"stock"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	MenuID      uuid.UUID  `json:"menu_id"`
+	GroupID     *uuid.UUID `json:"group_id"` // nil whenever toping is a free extra outside of any group
+	Position    int        `json:"position"` // within its group
 }
 
 type NewMenuTopingsDTO struct {
@@ -34,7 +36,77 @@ func (nmt NewMenuTopingsDTO) Validate() error {
 		validation.Field(&nmt.Name, validation.Required, validation.Length(1, 50)),
 		validation.Field(&nmt.Price, validation.Required),
 		validation.Field(&nmt.IsAvailable, validation.Required),
-		validation.Field(&nmt.Stock, validation.Required),
+		validation.Field(&nmt.Stock, validation.Required, validation.Min(1)),
 		validation.Field(&nmt.MenuID, validation.Required, is.UUIDv4),
 	)
 }
+
+// UpdateMenuTopingsDTO is what client should send to change details of a toping, stock is changed through stock movements only
+type UpdateMenuTopingsDTO struct {
+	Name        string  `json:"name" form:"name"`
+	Price       float64 `json:"price" form:"price"`
+	IsAvailable bool    `json:"is_available" form:"is_available"` // could only be true while toping is in stock
+}
+
+func (umt UpdateMenuTopingsDTO) Validate() error {
+	return validation.ValidateStruct(&umt,
+		validation.Field(&umt.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&umt.Price, validation.Required),
+	)
+}
+
+// kinds of stock movement, see stock_movement_kind enum on migration
+const (
+	MovementReserve = "reserve"
+	MovementRelease = "release"
+	MovementAdjust  = "adjust"
+)
+
+// InitialStockReason is reason of the movement which records stock a toping is created with
+const InitialStockReason = "initial stock"
+
+// StockMovementDTO is an entry of stock ledger of a toping
+type StockMovementDTO struct {
+	ID           uuid.UUID  `json:"id"`
+	MenuTopingID uuid.UUID  `json:"menu_toping_id"`
+	Kind         string     `json:"kind"`
+	Delta        int        `json:"delta"`
+	StockAfter   int        `json:"stock_after"`
+	Reason       string     `json:"reason"`
+	AccountID    *uuid.UUID `json:"account_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// StockDTO is current stock of a toping along with its latest movements
+type StockDTO struct {
+	MenuTopingID uuid.UUID          `json:"menu_toping_id"`
+	Stock        int                `json:"stock"`
+	IsAvailable  bool               `json:"is_available"`
+	Movements    []StockMovementDTO `json:"movements"`
+}
+
+// StockQuantityDTO is what client should send to reserve or release stock of a toping
+type StockQuantityDTO struct {
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+func (d StockQuantityDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Quantity, validation.Required, validation.Min(1)),
+		validation.Field(&d.Reason, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+// StockAdjustmentDTO is what client should send to correct stock of a toping, such as restock or waste
+type StockAdjustmentDTO struct {
+	Delta  int    `json:"delta"`
+	Reason string `json:"reason"`
+}
+
+func (d StockAdjustmentDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Delta, validation.Required),
+		validation.Field(&d.Reason, validation.Required, validation.Length(1, 255)),
+	)
+}
@@ -16,15 +16,49 @@ func NewDB(db *sqlx.DB) *repository {
 	return &repository{db}
 }
 
-func (dbrepo *repository) Create(ctx context.Context, m *menutoping.MenuTopingsDTO) error {
+// Create stores a toping along with the movement which records its initial stock, so stock ledger of the toping
+// always adds up to its stock
+func (dbrepo *repository) Create(ctx context.Context, m *menutoping.MenuTopingsDTO, accountID *uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	q := `
 	INSERT INTO menu_topings
 		(id, name, price, is_available, image_url, stock, created_at, updated_at, menu_id)
 	VALUES
 		(:id, :name, :price, :is_available, :image_url, :stock, :created_at, :updated_at, :menu_id)`
 
-	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(m))
-	return err
+	if _, err := tx.NamedExecContext(ctx, q, intoModel(m)); err != nil {
+		return err
+	}
+
+	if m.Stock != 0 {
+		movement := &menutoping.StockMovementDTO{
+			ID:           uuid.New(),
+			MenuTopingID: m.ID,
+			Kind:         menutoping.MovementAdjust,
+			Delta:        m.Stock,
+			StockAfter:   m.Stock,
+			Reason:       menutoping.InitialStockReason,
+			AccountID:    accountID,
+			CreatedAt:    m.CreatedAt,
+		}
+
+		q = `
+		INSERT INTO menu_toping_stock_movements
+			(id, menu_toping_id, kind, delta, stock_after, reason, account_id, created_at)
+		VALUES
+			(:id, :menu_toping_id, :kind, :delta, :stock_after, :reason, :account_id, :created_at)`
+
+		if _, err := tx.NamedExecContext(ctx, q, intoStockMovementModel(movement)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (dbrepo *repository) GetAll(ctx context.Context) ([]*menutoping.MenuTopingsDTO, error) {
@@ -51,6 +85,33 @@ func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*menutoping
 	return mt.intoDTO(), nil
 }
 
+// GetMany returns topings of given ids, topings which do not exist are left out
+func (dbrepo *repository) GetMany(ctx context.Context, ids []uuid.UUID) ([]menutoping.MenuTopingsDTO, error) {
+	q, args, err := sqlx.In(`SELECT * FROM menu_topings WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbrepo.QueryxContext(ctx, dbrepo.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var topings []menutoping.MenuTopingsDTO
+	for rows.Next() {
+		mt := new(Model)
+		if err := rows.StructScan(mt); err != nil {
+			return nil, err
+		}
+		topings = append(topings, *mt.intoDTO())
+	}
+
+	return topings, nil
+}
+
+// Update changes details of a toping, stock is left as is since it only moves through stock movements.
+// Toping which is out of stock stays unavailable, even whenever stock runs out while it is being updated
 func (dbrepo *repository) Update(ctx context.Context, m *menutoping.MenuTopingsDTO) error {
 	q := `
 	UPDATE
@@ -58,9 +119,8 @@ func (dbrepo *repository) Update(ctx context.Context, m *menutoping.MenuTopingsD
 	SET
 		name = :name,
 		price = :price,
-		is_available = :is_available,
+		is_available = :is_available AND stock > 0,
 		image_url = :image_url,
-		stock = :stock,
 		updated_at = :updated_at
 	WHERE id = :id`
 
@@ -74,3 +134,16 @@ func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := dbrepo.ExecContext(ctx, q, id)
 	return err
 }
+
+// GetOutletID returns outlet which owns given menu, it is used to scope topping mutation into outlet staff
+func (dbrepo *repository) GetOutletID(ctx context.Context, menuID uuid.UUID) (uuid.UUID, error) {
+	var outletID uuid.UUID
+
+	q := `SELECT outlet_id FROM menus WHERE id = $1`
+
+	if err := dbrepo.GetContext(ctx, &outletID, q, menuID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return outletID, nil
+}
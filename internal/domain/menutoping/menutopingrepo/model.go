@@ -8,15 +8,17 @@ import (
 )
 
 type Model struct {
-	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Price       float64   `db:"price"`
-	IsAvailable bool      `db:"is_available"`
-	ImageURL    string    `db:"image_url"`
-	Stock       int       `db:"stock"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	MenuID      uuid.UUID `db:"menu_id"`
+	ID          uuid.UUID  `db:"id"`
+	Name        string     `db:"name"`
+	Price       float64    `db:"price"`
+	IsAvailable bool       `db:"is_available"`
+	ImageURL    string     `db:"image_url"`
+	Stock       int        `db:"stock"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	MenuID      uuid.UUID  `db:"menu_id"`
+	GroupID     *uuid.UUID `db:"group_id"`
+	Position    int        `db:"position"`
 }
 
 func intoModel(mt *menutoping.MenuTopingsDTO) *Model {
@@ -30,6 +32,8 @@ func intoModel(mt *menutoping.MenuTopingsDTO) *Model {
 		CreatedAt:   mt.CreatedAt,
 		UpdatedAt:   mt.UpdatedAt,
 		MenuID:      mt.MenuID,
+		GroupID:     mt.GroupID,
+		Position:    mt.Position,
 	}
 }
 
@@ -44,5 +48,44 @@ func (m *Model) intoDTO() *menutoping.MenuTopingsDTO {
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 		MenuID:      m.MenuID,
+		GroupID:     m.GroupID,
+		Position:    m.Position,
+	}
+}
+
+type StockMovementModel struct {
+	ID           uuid.UUID  `db:"id"`
+	MenuTopingID uuid.UUID  `db:"menu_toping_id"`
+	Kind         string     `db:"kind"`
+	Delta        int        `db:"delta"`
+	StockAfter   int        `db:"stock_after"`
+	Reason       string     `db:"reason"`
+	AccountID    *uuid.UUID `db:"account_id"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (m *StockMovementModel) intoDTO() *menutoping.StockMovementDTO {
+	return &menutoping.StockMovementDTO{
+		ID:           m.ID,
+		MenuTopingID: m.MenuTopingID,
+		Kind:         m.Kind,
+		Delta:        m.Delta,
+		StockAfter:   m.StockAfter,
+		Reason:       m.Reason,
+		AccountID:    m.AccountID,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func intoStockMovementModel(sm *menutoping.StockMovementDTO) *StockMovementModel {
+	return &StockMovementModel{
+		ID:           sm.ID,
+		MenuTopingID: sm.MenuTopingID,
+		Kind:         sm.Kind,
+		Delta:        sm.Delta,
+		StockAfter:   sm.StockAfter,
+		Reason:       sm.Reason,
+		AccountID:    sm.AccountID,
+		CreatedAt:    sm.CreatedAt,
 	}
 }
@@ -0,0 +1,124 @@
+package menutopingrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutoping"
+)
+
+// ErrInsufficientStock is returned whenever a movement would bring stock of a toping below zero
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// Reserve takes given quantity out of stock of a toping
+func (dbrepo *repository) Reserve(ctx context.Context, id uuid.UUID, quantity int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error) {
+	return dbrepo.moveStock(ctx, id, menutoping.MovementReserve, -quantity, reason, accountID)
+}
+
+// Release puts given quantity back into stock of a toping, such as whenever a reservation is cancelled
+func (dbrepo *repository) Release(ctx context.Context, id uuid.UUID, quantity int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error) {
+	return dbrepo.moveStock(ctx, id, menutoping.MovementRelease, quantity, reason, accountID)
+}
+
+// Adjust corrects stock of a toping by given delta, which is negative to take stock out
+func (dbrepo *repository) Adjust(ctx context.Context, id uuid.UUID, delta int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error) {
+	return dbrepo.moveStock(ctx, id, menutoping.MovementAdjust, delta, reason, accountID)
+}
+
+// moveStock applies delta on stock of a toping while its row is locked, so concurrent movements are serialized.
+// Toping becomes unavailable once its stock reaches zero, and every movement is written into stock ledger.
+// sql.ErrNoRows is returned whenever the toping does not exist
+func (dbrepo *repository) moveStock(ctx context.Context, id uuid.UUID, kind string, delta int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error) {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	mt := new(Model)
+
+	q := `SELECT * FROM menu_topings WHERE id = $1 FOR UPDATE`
+
+	if err := tx.QueryRowxContext(ctx, q, id).StructScan(mt); err != nil {
+		return nil, err
+	}
+
+	stock := mt.Stock + delta
+	if stock < 0 {
+		return nil, ErrInsufficientStock
+	}
+
+	mt.Stock = stock
+	mt.UpdatedAt = time.Now()
+	if stock == 0 {
+		mt.IsAvailable = false
+	}
+
+	q = `
+	UPDATE
+		menu_topings
+	SET
+		stock = :stock,
+		is_available = :is_available,
+		updated_at = :updated_at
+	WHERE id = :id`
+
+	if _, err := tx.NamedExecContext(ctx, q, mt); err != nil {
+		return nil, err
+	}
+
+	movement := &menutoping.StockMovementDTO{
+		ID:           uuid.New(),
+		MenuTopingID: id,
+		Kind:         kind,
+		Delta:        delta,
+		StockAfter:   stock,
+		Reason:       reason,
+		AccountID:    accountID,
+		CreatedAt:    mt.UpdatedAt,
+	}
+
+	q = `
+	INSERT INTO menu_toping_stock_movements
+		(id, menu_toping_id, kind, delta, stock_after, reason, account_id, created_at)
+	VALUES
+		(:id, :menu_toping_id, :kind, :delta, :stock_after, :reason, :account_id, :created_at)`
+
+	if _, err := tx.NamedExecContext(ctx, q, intoStockMovementModel(movement)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return mt.intoDTO(), nil
+}
+
+// GetStockMovements returns latest movements of a toping, newest first
+func (dbrepo *repository) GetStockMovements(ctx context.Context, id uuid.UUID, limit int) ([]menutoping.StockMovementDTO, error) {
+	q := `
+	SELECT * FROM menu_toping_stock_movements
+	WHERE menu_toping_id = $1
+	ORDER BY created_at DESC
+	LIMIT $2`
+
+	rows, err := dbrepo.QueryxContext(ctx, q, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var movements []menutoping.StockMovementDTO
+	for rows.Next() {
+		sm := new(StockMovementModel)
+		if err := rows.StructScan(sm); err != nil {
+			return nil, err
+		}
+		movements = append(movements, *sm.intoDTO())
+	}
+
+	return movements, nil
+}
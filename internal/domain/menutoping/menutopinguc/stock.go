@@ -0,0 +1,102 @@
+package menutopinguc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/menutoping/menutopingrepo"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+)
+
+// amount of latest movements which are shown along with stock of a toping
+const stockMovementsLimit = 50
+
+// GetStock returns current stock of a toping along with its latest movements
+func (uc *Usecase) GetStock(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*menutoping.StockDTO, error) {
+	mt, err := uc.authorizedToping(ctx, id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	movements, err := uc.menuTopingDBRepo.GetStockMovements(ctx, id, stockMovementsLimit)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if movements == nil {
+		movements = []menutoping.StockMovementDTO{}
+	}
+
+	return &menutoping.StockDTO{
+		MenuTopingID: mt.ID,
+		Stock:        mt.Stock,
+		IsAvailable:  mt.IsAvailable,
+		Movements:    movements,
+	}, nil
+}
+
+func (uc *Usecase) ReserveStock(ctx context.Context, id uuid.UUID, sq *menutoping.StockQuantityDTO, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error) {
+	if _, err := uc.authorizedToping(ctx, id, claims); err != nil {
+		return nil, err
+	}
+
+	mt, err := uc.menuTopingDBRepo.Reserve(ctx, id, sq.Quantity, sq.Reason, &claims.AccountID)
+	return mt, stockError(id, err)
+}
+
+func (uc *Usecase) ReleaseStock(ctx context.Context, id uuid.UUID, sq *menutoping.StockQuantityDTO, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error) {
+	if _, err := uc.authorizedToping(ctx, id, claims); err != nil {
+		return nil, err
+	}
+
+	mt, err := uc.menuTopingDBRepo.Release(ctx, id, sq.Quantity, sq.Reason, &claims.AccountID)
+	return mt, stockError(id, err)
+}
+
+func (uc *Usecase) AdjustStock(ctx context.Context, id uuid.UUID, sa *menutoping.StockAdjustmentDTO, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error) {
+	if _, err := uc.authorizedToping(ctx, id, claims); err != nil {
+		return nil, err
+	}
+
+	mt, err := uc.menuTopingDBRepo.Adjust(ctx, id, sa.Delta, sa.Reason, &claims.AccountID)
+	return mt, stockError(id, err)
+}
+
+// authorizedToping gets a toping whenever account on claims is staff of the outlet which owns it
+func (uc *Usecase) authorizedToping(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error) {
+	mt, err := uc.menuTopingDBRepo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errshttp.New(errshttp.NotFound, "Menu topping not found")
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.authorize(ctx, claims, mt.MenuID); err != nil {
+		return nil, err
+	}
+
+	return mt, nil
+}
+
+// stockError translates error of a stock movement into http error, nil stays nil
+func stockError(id uuid.UUID, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, menutopingrepo.ErrInsufficientStock):
+		e := errshttp.New(errshttp.FailedPrecondition, "Stock is insufficient")
+		e.AddDetail(fmt.Sprintf("quantity: stock of toping %s is not enough", id))
+		return e
+	case errors.Is(err, sql.ErrNoRows):
+		return errshttp.New(errshttp.NotFound, "Menu topping not found")
+	default:
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+}
@@ -9,37 +9,71 @@ import (
 	"github.com/google/uuid"
 	"github.com/goplateframework/config"
 	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/outletstaff"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/worker/pb"
 	"github.com/goplateframework/pkg/logger"
 )
 
 // required iRepository methods which this usecase needs to store or retrieve data
 type iRepository interface {
-	Create(ctx context.Context, m *menutoping.MenuTopingsDTO) error
+	Create(ctx context.Context, m *menutoping.MenuTopingsDTO, accountID *uuid.UUID) error
 	GetAll(ctx context.Context) ([]*menutoping.MenuTopingsDTO, error)
 	GetOne(ctx context.Context, id uuid.UUID) (*menutoping.MenuTopingsDTO, error)
 	Update(ctx context.Context, m *menutoping.MenuTopingsDTO) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	GetOutletID(ctx context.Context, menuID uuid.UUID) (uuid.UUID, error)
+	Reserve(ctx context.Context, id uuid.UUID, quantity int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error)
+	Release(ctx context.Context, id uuid.UUID, quantity int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error)
+	Adjust(ctx context.Context, id uuid.UUID, delta int, reason string, accountID *uuid.UUID) (*menutoping.MenuTopingsDTO, error)
+	GetStockMovements(ctx context.Context, id uuid.UUID, limit int) ([]menutoping.StockMovementDTO, error)
+}
+
+// required staff usecase methods to scope topping mutation into staff of its outlet
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
 }
 
 type Usecase struct {
 	conf             *config.Config
 	log              *logger.Log
 	menuTopingDBRepo iRepository
+	staffUC          iStaffUsecase
 	worker           pb.WorkerClient
 }
 
-func New(conf *config.Config, log *logger.Log, menuTopingDBRepo iRepository, worker pb.WorkerClient) *Usecase {
+func New(conf *config.Config, log *logger.Log, menuTopingDBRepo iRepository, worker pb.WorkerClient, staffUC iStaffUsecase) *Usecase {
 	return &Usecase{
 		conf:             conf,
 		log:              log,
 		menuTopingDBRepo: menuTopingDBRepo,
+		staffUC:          staffUC,
 		worker:           worker,
 	}
 }
 
-func (uc *Usecase) Create(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO, image *[]byte) (*menutoping.MenuTopingsDTO, error) {
+// authorize makes sure account on claims is staff of the outlet which owns given menu
+func (uc *Usecase) authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, menuID uuid.UUID) error {
+	outletID, err := uc.menuTopingDBRepo.GetOutletID(ctx, menuID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("menu_id: menu with id %s not found", menuID))
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.staffUC.Authorize(ctx, claims, outletID, outletstaff.RoleManager, outletstaff.RoleKitchen)
+}
+
+func (uc *Usecase) Create(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error) {
+	if err := uc.authorize(ctx, claims, nmt.MenuID); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
 	id := uuid.New()
 
@@ -55,7 +89,7 @@ func (uc *Usecase) Create(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO
 		MenuID:      nmt.MenuID,
 	}
 
-	if err := uc.menuTopingDBRepo.Create(ctx, mt); err != nil {
+	if err := uc.menuTopingDBRepo.Create(ctx, mt, &claims.AccountID); err != nil {
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
@@ -97,14 +131,35 @@ func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID) (*menutoping.MenuTo
 	return mt, nil
 }
 
-func (uc *Usecase) Update(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO, id uuid.UUID, image *[]byte) (*menutoping.MenuTopingsDTO, error) {
+func (uc *Usecase) Update(ctx context.Context, umt *menutoping.UpdateMenuTopingsDTO, id uuid.UUID, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error) {
+	existing, err := uc.menuTopingDBRepo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errshttp.New(errshttp.NotFound, "Menu topping not found")
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.authorize(ctx, claims, existing.MenuID); err != nil {
+		return nil, err
+	}
+
+	// toping becomes unavailable once it runs out of stock, it is only available again after restock
+	if umt.IsAvailable && existing.Stock == 0 {
+		e := errshttp.New(errshttp.FailedPrecondition, "Menu topping is out of stock")
+		e.AddDetail("is_available: could not be true while stock is 0, adjust stock first")
+		return nil, e
+	}
+
 	mt := &menutoping.MenuTopingsDTO{
 		ID:          id,
-		Name:        nmt.Name,
-		Price:       nmt.Price,
-		IsAvailable: nmt.IsAvailable,
-		Stock:       nmt.Stock,
+		Name:        umt.Name,
+		Price:       umt.Price,
+		IsAvailable: umt.IsAvailable,
+		Stock:       existing.Stock, // stock is changed through stock movements only
 		UpdatedAt:   time.Now(),
+		MenuID:      existing.MenuID,
 	}
 
 	if err := uc.menuTopingDBRepo.Update(ctx, mt); err != nil {
@@ -129,13 +184,21 @@ func (uc *Usecase) Update(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO
 	return mt, nil
 }
 
-func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID) error {
+func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
 	mt, err := uc.menuTopingDBRepo.GetOne(ctx, id)
 
 	if err == sql.ErrNoRows {
 		return errshttp.New(errshttp.NotFound, "Menu topping not found")
 	}
 
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.authorize(ctx, claims, mt.MenuID); err != nil {
+		return err
+	}
+
 	if err := uc.menuTopingDBRepo.Delete(ctx, id); err != nil {
 		return errshttp.New(errshttp.Internal, "Something went wrong")
 	}
@@ -7,18 +7,24 @@ import (
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/menutoping"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/sdk/validate"
 	"github.com/goplateframework/internal/web/formfile"
+	"github.com/goplateframework/internal/web/webcontext"
 	"github.com/goplateframework/pkg/logger"
 	"github.com/labstack/echo/v4"
 )
 
 type iUsecase interface {
-	Create(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO, image *[]byte) (*menutoping.MenuTopingsDTO, error)
+	Create(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error)
 	GetAll(ctx context.Context) ([]*menutoping.MenuTopingsDTO, error)
 	GetOne(ctx context.Context, id uuid.UUID) (*menutoping.MenuTopingsDTO, error)
-	Update(ctx context.Context, nmt *menutoping.NewMenuTopingsDTO, id uuid.UUID, image *[]byte) (*menutoping.MenuTopingsDTO, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Update(ctx context.Context, umt *menutoping.UpdateMenuTopingsDTO, id uuid.UUID, image *[]byte, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error)
+	Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+	GetStock(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*menutoping.StockDTO, error)
+	ReserveStock(ctx context.Context, id uuid.UUID, sq *menutoping.StockQuantityDTO, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error)
+	ReleaseStock(ctx context.Context, id uuid.UUID, sq *menutoping.StockQuantityDTO, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error)
+	AdjustStock(ctx context.Context, id uuid.UUID, sa *menutoping.StockAdjustmentDTO, claims *tokenutil.AccessTokenClaims) (*menutoping.MenuTopingsDTO, error)
 }
 
 type controller struct {
@@ -69,7 +75,9 @@ func (con *controller) create(c echo.Context) error {
 		return e
 	}
 
-	m, err := con.menuTopingUC.Create(c.Request().Context(), nmt, &menuTopingImage)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	m, err := con.menuTopingUC.Create(c.Request().Context(), nmt, &menuTopingImage, claims)
 	if err != nil {
 		return err
 	}
@@ -104,13 +112,13 @@ func (con *controller) getOne(c echo.Context) error {
 }
 
 func (con *controller) update(c echo.Context) error {
-	nmt := new(menutoping.NewMenuTopingsDTO)
+	umt := new(menutoping.UpdateMenuTopingsDTO)
 
-	if err := c.Bind(nmt); err != nil {
+	if err := c.Bind(umt); err != nil {
 		return errshttp.New(errshttp.InvalidArgument, "Given form-data is invalid")
 	}
 
-	if err := nmt.Validate(); err != nil {
+	if err := umt.Validate(); err != nil {
 		e := errshttp.New(errshttp.InvalidArgument, "Given form-data is out of validation rules")
 
 		validationErrs := validate.SplitErrors(err)
@@ -145,7 +153,9 @@ func (con *controller) update(c echo.Context) error {
 		menuTopingImage = &mti
 	}
 
-	m, err := con.menuTopingUC.Update(c.Request().Context(), nmt, id, menuTopingImage)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	m, err := con.menuTopingUC.Update(c.Request().Context(), umt, id, menuTopingImage, claims)
 	if err != nil {
 		return err
 	}
@@ -161,10 +171,135 @@ func (con *controller) delete(c echo.Context) error {
 		return e
 	}
 
-	err = con.menuTopingUC.Delete(c.Request().Context(), id)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	err = con.menuTopingUC.Delete(c.Request().Context(), id, claims)
 	if err != nil {
 		return err
 	}
 
 	return c.NoContent(http.StatusOK)
 }
+
+func (con *controller) getStock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Menu topings id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	s, err := con.menuTopingUC.GetStock(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, s)
+}
+
+func (con *controller) reserveStock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Menu topings id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	dto := new(menutoping.StockQuantityDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	mt, err := con.menuTopingUC.ReserveStock(c.Request().Context(), id, dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, mt)
+}
+
+func (con *controller) releaseStock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Menu topings id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	dto := new(menutoping.StockQuantityDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	mt, err := con.menuTopingUC.ReleaseStock(c.Request().Context(), id, dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, mt)
+}
+
+func (con *controller) adjustStock(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Menu topings id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	dto := new(menutoping.StockAdjustmentDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	mt, err := con.menuTopingUC.AdjustStock(c.Request().Context(), id, dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, mt)
+}
@@ -2,6 +2,7 @@ package menutopingweb
 
 import (
 	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
 	"github.com/goplateframework/pkg/logger"
 )
 
@@ -13,10 +14,15 @@ type Options struct {
 func Route(web *web.Web, opts *Options) {
 	con := newController(opts.MenuTopingUC, opts.Log)
 
-	g := web.Echo.Group("/api/v1/menu-topings", web.Mid.Authenticated)
+	g := web.Echo.Group("/api/v1/menu-topings", web.Mid.Authenticated, web.Mid.Authorize(middlewares.ReadAnyWriteAdmin))
 	g.POST("", con.create)
 	g.GET("", con.getAll)
 	g.GET("/:id", con.getOne)
 	g.PUT("/:id", con.update)
 	g.DELETE("/:id", con.delete)
+
+	g.GET("/:id/stock", con.getStock)
+	g.POST("/:id/stock/reserve", con.reserveStock)
+	g.POST("/:id/stock/release", con.releaseStock)
+	g.POST("/:id/stock/adjust", con.adjustStock)
 }
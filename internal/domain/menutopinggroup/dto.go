@@ -0,0 +1,206 @@
+package menutopinggroup
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutoping"
+)
+
+// GroupDTO is what we send to client, along with topings of the group by their position
+type GroupDTO struct {
+	ID         uuid.UUID                   `json:"id"`
+	MenuID     uuid.UUID                   `json:"menu_id"`
+	Name       string                      `json:"name"`
+	MinSelect  int                         `json:"min_select"`
+	MaxSelect  *int                        `json:"max_select"` // nil is unlimited
+	IsRequired bool                        `json:"is_required"`
+	Position   int                         `json:"position"`
+	Topings    []menutoping.MenuTopingsDTO `json:"topings"`
+	CreatedAt  time.Time                   `json:"created_at"`
+	UpdatedAt  time.Time                   `json:"updated_at"`
+}
+
+// NewGroupDTO is what client should send to create new group, it is placed after every other group of the menu.
+// Optional group could be skipped entirely, yet whenever it is chosen from, min_select still applies
+type NewGroupDTO struct {
+	MenuID     string `json:"menu_id"`
+	Name       string `json:"name"`
+	MinSelect  int    `json:"min_select"`
+	MaxSelect  *int   `json:"max_select"`
+	IsRequired bool   `json:"is_required"`
+}
+
+func (d NewGroupDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuID, validation.Required, is.UUID),
+		validation.Field(&d.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&d.MinSelect, validation.Min(0), validation.When(d.IsRequired, validation.Min(1).Error("must be at least 1 on required group"))),
+		validation.Field(&d.MaxSelect, validation.NilOrNotEmpty, validation.Min(1), validation.Min(d.MinSelect).Error("must not be less than min_select")),
+	)
+}
+
+// UpdateGroupDTO is what client should send to change rules of a group, position is changed through reorder instead
+type UpdateGroupDTO struct {
+	Name       string `json:"name"`
+	MinSelect  int    `json:"min_select"`
+	MaxSelect  *int   `json:"max_select"`
+	IsRequired bool   `json:"is_required"`
+}
+
+func (d UpdateGroupDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&d.MinSelect, validation.Min(0), validation.When(d.IsRequired, validation.Min(1).Error("must be at least 1 on required group"))),
+		validation.Field(&d.MaxSelect, validation.NilOrNotEmpty, validation.Min(1), validation.Min(d.MinSelect).Error("must not be less than min_select")),
+	)
+}
+
+// ReorderDTO is what client should send to reorder every group of a menu, groups are positioned as they are listed
+type ReorderDTO struct {
+	MenuID   string   `json:"menu_id"`
+	GroupIDs []string `json:"group_ids"`
+}
+
+func (d ReorderDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuID, validation.Required, is.UUID),
+		validation.Field(&d.GroupIDs, validation.Required, validation.Length(1, 50), validation.Each(is.UUID), validation.By(unique)),
+	)
+}
+
+// AssignTopingsDTO is what client should send to set topings of a group, topings are positioned as they are listed.
+// Topings which are in the group yet left out of the list become free extras
+type AssignTopingsDTO struct {
+	TopingIDs []string `json:"toping_ids"`
+}
+
+func (d AssignTopingsDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.TopingIDs, validation.Length(0, 100), validation.Each(is.UUID), validation.By(unique)),
+	)
+}
+
+// SelectionDTO is what client should send to check topings which a customer selects for a menu
+type SelectionDTO struct {
+	MenuID    string   `json:"menu_id"`
+	TopingIDs []string `json:"toping_ids"`
+}
+
+func (d SelectionDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuID, validation.Required, is.UUID),
+		validation.Field(&d.TopingIDs, validation.Length(0, 100), validation.Each(is.UUID)),
+	)
+}
+
+// SelectionResultDTO tells whether a selection satisfies every group of the menu, along with every rule it breaks
+type SelectionResultDTO struct {
+	MenuID     uuid.UUID      `json:"menu_id"`
+	Valid      bool           `json:"valid"`
+	Violations []ViolationDTO `json:"violations"`
+}
+
+type ViolationDTO struct {
+	GroupID  *uuid.UUID `json:"group_id"`
+	TopingID *uuid.UUID `json:"toping_id"`
+	Reason   string     `json:"reason"`
+}
+
+// Check validates selected topings against groups of a menu, topings are every toping of the menu including free extras
+func Check(menuID uuid.UUID, groups []GroupDTO, topings []menutoping.MenuTopingsDTO, selected []uuid.UUID) *SelectionResultDTO {
+	res := &SelectionResultDTO{MenuID: menuID, Violations: []ViolationDTO{}}
+
+	known := make(map[uuid.UUID]*menutoping.MenuTopingsDTO, len(topings))
+	for i := range topings {
+		known[topings[i].ID] = &topings[i]
+	}
+
+	chosen := make(map[uuid.UUID]int, len(groups))
+	seen := make(map[uuid.UUID]struct{}, len(selected))
+
+	for _, id := range selected {
+		topingID := id
+
+		if _, ok := seen[id]; ok {
+			res.Violations = append(res.Violations, ViolationDTO{TopingID: &topingID, Reason: "toping is selected more than once"})
+			continue
+		}
+		seen[id] = struct{}{}
+
+		t, ok := known[id]
+		if !ok {
+			res.Violations = append(res.Violations, ViolationDTO{TopingID: &topingID, Reason: "toping does not belong to the menu"})
+			continue
+		}
+
+		if !t.IsAvailable || t.Stock <= 0 {
+			res.Violations = append(res.Violations, ViolationDTO{GroupID: t.GroupID, TopingID: &topingID, Reason: "toping is unavailable"})
+		}
+
+		if t.GroupID != nil {
+			chosen[*t.GroupID]++
+		}
+	}
+
+	for i := range groups {
+		g := &groups[i]
+		groupID := g.ID
+
+		if err := g.check(chosen[g.ID]); err != nil {
+			res.Violations = append(res.Violations, ViolationDTO{GroupID: &groupID, Reason: err.Error()})
+		}
+	}
+
+	res.Valid = len(res.Violations) == 0
+	return res
+}
+
+// Details describes every violation as a detail of given field, so a caller could reject the selection with all of them
+func (r *SelectionResultDTO) Details(field string) []string {
+	details := make([]string, 0, len(r.Violations))
+	for _, v := range r.Violations {
+		if v.TopingID != nil {
+			details = append(details, fmt.Sprintf("%s: %s on menu %s (toping %s)", field, v.Reason, r.MenuID, *v.TopingID))
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s: %s on menu %s", field, v.Reason, r.MenuID))
+	}
+
+	return details
+}
+
+// check tells whether given number of topings chosen from the group satisfies its rules
+func (g *GroupDTO) check(n int) error {
+	if n == 0 && !g.IsRequired {
+		return nil
+	}
+
+	if n < g.MinSelect {
+		return fmt.Errorf("%s requires at least %d toping(s), %d selected", g.Name, g.MinSelect, n)
+	}
+
+	if g.MaxSelect != nil && n > *g.MaxSelect {
+		return fmt.Errorf("%s allows at most %d toping(s), %d selected", g.Name, *g.MaxSelect, n)
+	}
+
+	return nil
+}
+
+func unique(value interface{}) error {
+	ids, _ := value.([]string)
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return errors.New("must not contain duplicates")
+		}
+		seen[id] = struct{}{}
+	}
+
+	return nil
+}
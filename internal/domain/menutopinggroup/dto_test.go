@@ -0,0 +1,86 @@
+package menutopinggroup
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutoping"
+)
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestGroupCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   GroupDTO
+		n       int
+		wantErr bool
+	}{
+		{"optional group skipped", GroupDTO{Name: "Sauce", MinSelect: 2, MaxSelect: intPtr(3)}, 0, false},
+		{"optional group chosen below min", GroupDTO{Name: "Sauce", MinSelect: 2, MaxSelect: intPtr(3)}, 1, true},
+		{"required group with none selected", GroupDTO{Name: "Size", MinSelect: 1, MaxSelect: intPtr(1), IsRequired: true}, 0, true},
+		{"required group satisfied", GroupDTO{Name: "Size", MinSelect: 1, MaxSelect: intPtr(1), IsRequired: true}, 1, false},
+		{"max overflow", GroupDTO{Name: "Size", MinSelect: 1, MaxSelect: intPtr(1), IsRequired: true}, 2, true},
+		{"unlimited max", GroupDTO{Name: "Extra", MinSelect: 1}, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.group.check(tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("check(%d) error = %v, wantErr %v", tt.n, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	menuID := uuid.New()
+	sizeID, sauceID := uuid.New(), uuid.New()
+
+	small := menutoping.MenuTopingsDTO{ID: uuid.New(), MenuID: menuID, GroupID: &sizeID, IsAvailable: true, Stock: 10}
+	large := menutoping.MenuTopingsDTO{ID: uuid.New(), MenuID: menuID, GroupID: &sizeID, IsAvailable: true, Stock: 10}
+	chili := menutoping.MenuTopingsDTO{ID: uuid.New(), MenuID: menuID, GroupID: &sauceID, IsAvailable: true, Stock: 10}
+	soldOut := menutoping.MenuTopingsDTO{ID: uuid.New(), MenuID: menuID, GroupID: &sauceID, IsAvailable: true, Stock: 0}
+	egg := menutoping.MenuTopingsDTO{ID: uuid.New(), MenuID: menuID, IsAvailable: true, Stock: 10}
+
+	groups := []GroupDTO{
+		{ID: sizeID, MenuID: menuID, Name: "Size", MinSelect: 1, MaxSelect: intPtr(1), IsRequired: true},
+		{ID: sauceID, MenuID: menuID, Name: "Sauce", MinSelect: 1, MaxSelect: intPtr(2)},
+	}
+	topings := []menutoping.MenuTopingsDTO{small, large, chili, soldOut, egg}
+
+	tests := []struct {
+		name       string
+		selected   []uuid.UUID
+		violations int
+	}{
+		{"valid selection", []uuid.UUID{small.ID, chili.ID, egg.ID}, 0},
+		{"required group with none selected", []uuid.UUID{egg.ID}, 1},
+		{"max overflow", []uuid.UUID{small.ID, large.ID}, 1},
+		{"duplicate selection", []uuid.UUID{small.ID, egg.ID, egg.ID}, 1},
+		{"toping of another menu", []uuid.UUID{small.ID, uuid.New()}, 1},
+		{"toping out of stock", []uuid.UUID{small.ID, soldOut.ID}, 1},
+		{"nothing selected", nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Check(menuID, groups, topings, tt.selected)
+
+			if len(res.Violations) != tt.violations {
+				t.Fatalf("Check() violations = %+v, want %d of them", res.Violations, tt.violations)
+			}
+
+			if res.Valid != (tt.violations == 0) {
+				t.Errorf("Check() valid = %v with %d violations", res.Valid, len(res.Violations))
+			}
+
+			if details := res.Details("items"); len(details) != tt.violations {
+				t.Errorf("Details() = %v, want %d of them", details, tt.violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package menutopinggrouprepo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/menutopinggroup"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrGroupsMismatch is returned whenever reorder does not list every group of the menu exactly once
+	ErrGroupsMismatch = errors.New("given groups do not match groups of the menu")
+
+	// ErrTopingsMismatch is returned whenever topings which are assigned into a group do not belong to its menu
+	ErrTopingsMismatch = errors.New("given topings do not belong to menu of the group")
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// Create places new group after every other group of its menu, position of given group is filled accordingly
+func (dbrepo *repository) Create(ctx context.Context, g *menutopinggroup.GroupDTO) error {
+	q := `
+	INSERT INTO menu_toping_groups
+		(id, menu_id, name, min_select, max_select, is_required, position, created_at, updated_at)
+	VALUES
+		(:id, :menu_id, :name, :min_select, :max_select, :is_required,
+		(SELECT COALESCE(MAX(position) + 1, 0) FROM menu_toping_groups WHERE menu_id = :menu_id),
+		:created_at, :updated_at)
+	RETURNING position`
+
+	rows, err := dbrepo.NamedQueryContext(ctx, q, intoModel(g))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&g.Position); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*menutopinggroup.GroupDTO, error) {
+	m := new(Model)
+
+	q := `SELECT * FROM menu_toping_groups WHERE id = $1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	groups := []menutopinggroup.GroupDTO{*m.intoDTO()}
+	if err := dbrepo.attachTopings(ctx, m.MenuID, groups); err != nil {
+		return nil, err
+	}
+
+	return &groups[0], nil
+}
+
+// GetAll returns every group of a menu by their position, along with their topings
+func (dbrepo *repository) GetAll(ctx context.Context, menuID uuid.UUID) ([]menutopinggroup.GroupDTO, error) {
+	q := `
+	SELECT * FROM menu_toping_groups
+	WHERE menu_id = $1
+	ORDER BY position, name`
+
+	models := []Model{}
+	if err := dbrepo.SelectContext(ctx, &models, q, menuID); err != nil {
+		return nil, err
+	}
+
+	groups := make([]menutopinggroup.GroupDTO, 0, len(models))
+	for i := range models {
+		groups = append(groups, *models[i].intoDTO())
+	}
+
+	if err := dbrepo.attachTopings(ctx, menuID, groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetTopings returns every toping of a menu, including those which are not in any group
+func (dbrepo *repository) GetTopings(ctx context.Context, menuID uuid.UUID) ([]menutoping.MenuTopingsDTO, error) {
+	q := `
+	SELECT id, menu_id, group_id, position, name, price, is_available, COALESCE(image_url, '') AS image_url, stock, created_at, updated_at
+		FROM menu_topings
+	WHERE menu_id = $1
+	ORDER BY position, name`
+
+	models := []TopingModel{}
+	if err := dbrepo.SelectContext(ctx, &models, q, menuID); err != nil {
+		return nil, err
+	}
+
+	topings := make([]menutoping.MenuTopingsDTO, 0, len(models))
+	for i := range models {
+		topings = append(topings, *models[i].intoDTO())
+	}
+
+	return topings, nil
+}
+
+// attachTopings fills topings of given groups, all of them should belong to the same menu
+func (dbrepo *repository) attachTopings(ctx context.Context, menuID uuid.UUID, groups []menutopinggroup.GroupDTO) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID]int, len(groups))
+	for i := range groups {
+		index[groups[i].ID] = i
+	}
+
+	topings, err := dbrepo.GetTopings(ctx, menuID)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range topings {
+		if t.GroupID == nil {
+			continue
+		}
+
+		if i, ok := index[*t.GroupID]; ok {
+			groups[i].Topings = append(groups[i].Topings, t)
+		}
+	}
+
+	return nil
+}
+
+// NameExists tells whether a menu already has a group of given name other than the excluded one, names are case insensitive
+func (dbrepo *repository) NameExists(ctx context.Context, menuID uuid.UUID, name string, excludeID uuid.UUID) (bool, error) {
+	var exists bool
+
+	q := `SELECT EXISTS (SELECT 1 FROM menu_toping_groups WHERE menu_id = $1 AND lower(name) = lower($2) AND id <> $3)`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, menuID, name, excludeID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (dbrepo *repository) Update(ctx context.Context, g *menutopinggroup.GroupDTO) error {
+	q := `
+	UPDATE
+		menu_toping_groups
+	SET
+		name = :name,
+		min_select = :min_select,
+		max_select = :max_select,
+		is_required = :is_required,
+		updated_at = :updated_at
+	WHERE id = :id`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(g))
+	return err
+}
+
+// Delete removes a group, its topings become free extras
+func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := dbrepo.ExecContext(ctx, `DELETE FROM menu_toping_groups WHERE id = $1`, id)
+	return err
+}
+
+// Reorder positions every group of a menu as given ids are ordered, ErrGroupsMismatch is returned
+// whenever given ids are not exactly the groups of the menu
+func (dbrepo *repository) Reorder(ctx context.Context, menuID uuid.UUID, ids []uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current []uuid.UUID
+	if err := tx.SelectContext(ctx, &current, `SELECT id FROM menu_toping_groups WHERE menu_id = $1 FOR UPDATE`, menuID); err != nil {
+		return err
+	}
+
+	if !sameSet(current, ids) {
+		return ErrGroupsMismatch
+	}
+
+	q := `UPDATE menu_toping_groups SET position = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	for i, id := range ids {
+		if _, err := tx.ExecContext(ctx, q, i, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AssignTopings makes given topings the only topings of a group, positioned as they are ordered. A toping belongs to
+// one group at most, so topings which are in another group are moved. ErrTopingsMismatch is returned whenever any of them
+// does not belong to the menu of the group
+func (dbrepo *repository) AssignTopings(ctx context.Context, g *menutopinggroup.GroupDTO, topingIDs []uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if len(topingIDs) > 0 {
+		q, args, err := sqlx.In(`SELECT COUNT(DISTINCT id) FROM menu_topings WHERE menu_id = ? AND id IN (?)`, g.MenuID, topingIDs)
+		if err != nil {
+			return err
+		}
+
+		var found int
+		if err := tx.QueryRowxContext(ctx, tx.Rebind(q), args...).Scan(&found); err != nil {
+			return err
+		}
+
+		if found != len(distinct(topingIDs)) {
+			return ErrTopingsMismatch
+		}
+	}
+
+	q := `UPDATE menu_topings SET group_id = NULL, position = 0 WHERE group_id = $1`
+
+	if _, err := tx.ExecContext(ctx, q, g.ID); err != nil {
+		return err
+	}
+
+	q = `UPDATE menu_topings SET group_id = $1, position = $2 WHERE id = $3`
+
+	for i, id := range topingIDs {
+		if _, err := tx.ExecContext(ctx, q, g.ID, i, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func sameSet(a, b []uuid.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := distinct(a)
+	for _, id := range b {
+		if _, ok := set[id]; !ok {
+			return false
+		}
+		delete(set, id)
+	}
+
+	return len(set) == 0
+}
+
+func distinct(ids []uuid.UUID) map[uuid.UUID]struct{} {
+	set := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// GetOutletID returns outlet which owns given menu, it is used to scope group mutation into outlet staff
+func (dbrepo *repository) GetOutletID(ctx context.Context, menuID uuid.UUID) (uuid.UUID, error) {
+	var outletID uuid.UUID
+
+	q := `SELECT outlet_id FROM menus WHERE id = $1`
+
+	if err := dbrepo.GetContext(ctx, &outletID, q, menuID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return outletID, nil
+}
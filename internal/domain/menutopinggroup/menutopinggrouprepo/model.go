@@ -0,0 +1,80 @@
+package menutopinggrouprepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/menutopinggroup"
+)
+
+type Model struct {
+	ID         uuid.UUID `db:"id"`
+	MenuID     uuid.UUID `db:"menu_id"`
+	Name       string    `db:"name"`
+	MinSelect  int       `db:"min_select"`
+	MaxSelect  *int      `db:"max_select"`
+	IsRequired bool      `db:"is_required"`
+	Position   int       `db:"position"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+func intoModel(g *menutopinggroup.GroupDTO) *Model {
+	return &Model{
+		ID:         g.ID,
+		MenuID:     g.MenuID,
+		Name:       g.Name,
+		MinSelect:  g.MinSelect,
+		MaxSelect:  g.MaxSelect,
+		IsRequired: g.IsRequired,
+		Position:   g.Position,
+		CreatedAt:  g.CreatedAt,
+		UpdatedAt:  g.UpdatedAt,
+	}
+}
+
+func (m *Model) intoDTO() *menutopinggroup.GroupDTO {
+	return &menutopinggroup.GroupDTO{
+		ID:         m.ID,
+		MenuID:     m.MenuID,
+		Name:       m.Name,
+		MinSelect:  m.MinSelect,
+		MaxSelect:  m.MaxSelect,
+		IsRequired: m.IsRequired,
+		Position:   m.Position,
+		Topings:    []menutoping.MenuTopingsDTO{},
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+type TopingModel struct {
+	ID          uuid.UUID  `db:"id"`
+	MenuID      uuid.UUID  `db:"menu_id"`
+	GroupID     *uuid.UUID `db:"group_id"`
+	Position    int        `db:"position"`
+	Name        string     `db:"name"`
+	Price       float64    `db:"price"`
+	IsAvailable bool       `db:"is_available"`
+	ImageURL    string     `db:"image_url"`
+	Stock       int        `db:"stock"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+func (m *TopingModel) intoDTO() *menutoping.MenuTopingsDTO {
+	return &menutoping.MenuTopingsDTO{
+		ID:          m.ID,
+		Name:        m.Name,
+		Price:       m.Price,
+		IsAvailable: m.IsAvailable,
+		ImageURL:    m.ImageURL,
+		Stock:       m.Stock,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		MenuID:      m.MenuID,
+		GroupID:     m.GroupID,
+		Position:    m.Position,
+	}
+}
@@ -0,0 +1,278 @@
+package menutopinggroupuc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/menutopinggroup"
+	"github.com/goplateframework/internal/domain/menutopinggroup/menutopinggrouprepo"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/pkg/logger"
+)
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	Create(ctx context.Context, g *menutopinggroup.GroupDTO) error
+	GetOne(ctx context.Context, id uuid.UUID) (*menutopinggroup.GroupDTO, error)
+	GetAll(ctx context.Context, menuID uuid.UUID) ([]menutopinggroup.GroupDTO, error)
+	GetTopings(ctx context.Context, menuID uuid.UUID) ([]menutoping.MenuTopingsDTO, error)
+	NameExists(ctx context.Context, menuID uuid.UUID, name string, excludeID uuid.UUID) (bool, error)
+	Update(ctx context.Context, g *menutopinggroup.GroupDTO) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Reorder(ctx context.Context, menuID uuid.UUID, ids []uuid.UUID) error
+	AssignTopings(ctx context.Context, g *menutopinggroup.GroupDTO, topingIDs []uuid.UUID) error
+	GetOutletID(ctx context.Context, menuID uuid.UUID) (uuid.UUID, error)
+}
+
+// required staff usecase methods to scope group mutation into staff of the outlet which owns the menu
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
+}
+
+type Usecase struct {
+	conf    *config.Config
+	log     *logger.Log
+	repo    iRepository
+	staffUC iStaffUsecase
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, staffUC iStaffUsecase) *Usecase {
+	return &Usecase{
+		conf:    conf,
+		log:     log,
+		repo:    repo,
+		staffUC: staffUC,
+	}
+}
+
+// groups are managed by the same staff who manage menus and their topings
+var groupStaffRoles = []string{outletstaff.RoleManager, outletstaff.RoleKitchen}
+
+func (uc *Usecase) Create(ctx context.Context, ng *menutopinggroup.NewGroupDTO, claims *tokenutil.AccessTokenClaims) (*menutopinggroup.GroupDTO, error) {
+	menuID := uuid.MustParse(ng.MenuID)
+
+	if err := uc.authorize(ctx, claims, menuID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkName(ctx, menuID, ng.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	g := &menutopinggroup.GroupDTO{
+		ID:         uuid.New(),
+		MenuID:     menuID,
+		Name:       ng.Name,
+		MinSelect:  ng.MinSelect,
+		MaxSelect:  ng.MaxSelect,
+		IsRequired: ng.IsRequired,
+		Topings:    []menutoping.MenuTopingsDTO{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := uc.repo.Create(ctx, g); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return g, nil
+}
+
+func (uc *Usecase) GetAll(ctx context.Context, menuID uuid.UUID) ([]menutopinggroup.GroupDTO, error) {
+	groups, err := uc.repo.GetAll(ctx, menuID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return groups, nil
+}
+
+func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID) (*menutopinggroup.GroupDTO, error) {
+	g, err := uc.repo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Toping group not found")
+			e.AddDetail(fmt.Sprintf("data: toping group with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return g, nil
+}
+
+func (uc *Usecase) Update(ctx context.Context, id uuid.UUID, ug *menutopinggroup.UpdateGroupDTO, claims *tokenutil.AccessTokenClaims) (*menutopinggroup.GroupDTO, error) {
+	g, err := uc.GetOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorize(ctx, claims, g.MenuID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkName(ctx, g.MenuID, ug.Name, g.ID); err != nil {
+		return nil, err
+	}
+
+	g.Name = ug.Name
+	g.MinSelect = ug.MinSelect
+	g.MaxSelect = ug.MaxSelect
+	g.IsRequired = ug.IsRequired
+	g.UpdatedAt = time.Now()
+
+	if err := uc.repo.Update(ctx, g); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return g, nil
+}
+
+// Delete removes a group, its topings are kept as free extras of the menu
+func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	g, err := uc.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.authorize(ctx, claims, g.MenuID); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// Reorder positions every group of a menu as they are listed, then returns them by their new position
+func (uc *Usecase) Reorder(ctx context.Context, r *menutopinggroup.ReorderDTO, claims *tokenutil.AccessTokenClaims) ([]menutopinggroup.GroupDTO, error) {
+	menuID := uuid.MustParse(r.MenuID)
+
+	if err := uc.authorize(ctx, claims, menuID); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(r.GroupIDs))
+	for _, id := range r.GroupIDs {
+		ids = append(ids, uuid.MustParse(id))
+	}
+
+	if err := uc.repo.Reorder(ctx, menuID, ids); err != nil {
+		if err == menutopinggrouprepo.ErrGroupsMismatch {
+			e := errshttp.New(errshttp.FailedPrecondition, "Toping groups do not match groups of the menu")
+			e.AddDetail("group_ids: must list every toping group of the menu exactly once")
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.GetAll(ctx, menuID)
+}
+
+// AssignTopings sets topings of a group along with their position, then returns the group with its new topings
+func (uc *Usecase) AssignTopings(ctx context.Context, id uuid.UUID, at *menutopinggroup.AssignTopingsDTO, claims *tokenutil.AccessTokenClaims) (*menutopinggroup.GroupDTO, error) {
+	g, err := uc.GetOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.authorize(ctx, claims, g.MenuID); err != nil {
+		return nil, err
+	}
+
+	topingIDs := make([]uuid.UUID, 0, len(at.TopingIDs))
+	for _, id := range at.TopingIDs {
+		topingIDs = append(topingIDs, uuid.MustParse(id))
+	}
+
+	if err := uc.repo.AssignTopings(ctx, g, topingIDs); err != nil {
+		if err == menutopinggrouprepo.ErrTopingsMismatch {
+			e := errshttp.New(errshttp.FailedPrecondition, "Topings do not belong to menu of the group")
+			e.AddDetail("toping_ids: every toping must exist on the same menu as the group")
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.GetOne(ctx, id)
+}
+
+// Validate checks topings which a customer selects against every group of the menu. Broken rules are reported
+// on the result rather than as an error, so client is able to show all of them at once
+func (uc *Usecase) Validate(ctx context.Context, s *menutopinggroup.SelectionDTO) (*menutopinggroup.SelectionResultDTO, error) {
+	menuID := uuid.MustParse(s.MenuID)
+
+	if _, err := uc.outletOf(ctx, menuID); err != nil {
+		return nil, err
+	}
+
+	groups, err := uc.repo.GetAll(ctx, menuID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	topings, err := uc.repo.GetTopings(ctx, menuID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	selected := make([]uuid.UUID, 0, len(s.TopingIDs))
+	for _, id := range s.TopingIDs {
+		selected = append(selected, uuid.MustParse(id))
+	}
+
+	return menutopinggroup.Check(menuID, groups, topings, selected), nil
+}
+
+func (uc *Usecase) authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, menuID uuid.UUID) error {
+	outletID, err := uc.outletOf(ctx, menuID)
+	if err != nil {
+		return err
+	}
+
+	return uc.staffUC.Authorize(ctx, claims, outletID, groupStaffRoles...)
+}
+
+func (uc *Usecase) outletOf(ctx context.Context, menuID uuid.UUID) (uuid.UUID, error) {
+	outletID, err := uc.repo.GetOutletID(ctx, menuID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("data: menu with id %s not found", menuID))
+			return uuid.Nil, e
+		}
+
+		return uuid.Nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return outletID, nil
+}
+
+func (uc *Usecase) checkName(ctx context.Context, menuID uuid.UUID, name string, excludeID uuid.UUID) error {
+	exists, err := uc.repo.NameExists(ctx, menuID, name, excludeID)
+	if err != nil {
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if exists {
+		e := errshttp.New(errshttp.AlreadyExists, "Toping group already exists")
+		e.AddDetail(fmt.Sprintf("name: menu already has toping group named %s", name))
+		return e
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package menutopinggroupweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/menutopinggroup"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// required usecase methods which this controller needs to operate the business logic
+type iUsecase interface {
+	Create(ctx context.Context, ng *menutopinggroup.NewGroupDTO, claims *tokenutil.AccessTokenClaims) (*menutopinggroup.GroupDTO, error)
+	GetAll(ctx context.Context, menuID uuid.UUID) ([]menutopinggroup.GroupDTO, error)
+	GetOne(ctx context.Context, id uuid.UUID) (*menutopinggroup.GroupDTO, error)
+	Update(ctx context.Context, id uuid.UUID, ng *menutopinggroup.UpdateGroupDTO, claims *tokenutil.AccessTokenClaims) (*menutopinggroup.GroupDTO, error)
+	Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+	Reorder(ctx context.Context, r *menutopinggroup.ReorderDTO, claims *tokenutil.AccessTokenClaims) ([]menutopinggroup.GroupDTO, error)
+	AssignTopings(ctx context.Context, id uuid.UUID, at *menutopinggroup.AssignTopingsDTO, claims *tokenutil.AccessTokenClaims) (*menutopinggroup.GroupDTO, error)
+	Validate(ctx context.Context, s *menutopinggroup.SelectionDTO) (*menutopinggroup.SelectionResultDTO, error)
+}
+
+type controller struct {
+	groupUC iUsecase
+	log     *logger.Log
+}
+
+func newController(groupUC iUsecase, log *logger.Log) *controller {
+	return &controller{groupUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	ng := new(menutopinggroup.NewGroupDTO)
+
+	if err := c.Bind(ng); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := ng.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	g, err := con.groupUC.Create(c.Request().Context(), ng, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, g)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	menuID, err := uuid.Parse(c.QueryParam("menu_id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
+		e.AddDetail("filter: menu_id is not valid")
+		return e
+	}
+
+	groups, err := con.groupUC.GetAll(c.Request().Context(), menuID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, groups)
+}
+
+func (con *controller) getOne(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Toping group id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	g, err := con.groupUC.GetOne(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, g)
+}
+
+func (con *controller) update(c echo.Context) error {
+	ng := new(menutopinggroup.UpdateGroupDTO)
+
+	if err := c.Bind(ng); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := ng.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Toping group id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	g, err := con.groupUC.Update(c.Request().Context(), id, ng, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, g)
+}
+
+func (con *controller) delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Toping group id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.groupUC.Delete(c.Request().Context(), id, claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) reorder(c echo.Context) error {
+	r := new(menutopinggroup.ReorderDTO)
+
+	if err := c.Bind(r); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := r.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	groups, err := con.groupUC.Reorder(c.Request().Context(), r, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, groups)
+}
+
+func (con *controller) assignTopings(c echo.Context) error {
+	at := new(menutopinggroup.AssignTopingsDTO)
+
+	if err := c.Bind(at); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := at.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Toping group id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	g, err := con.groupUC.AssignTopings(c.Request().Context(), id, at, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, g)
+}
+
+func (con *controller) validate(c echo.Context) error {
+	sel := new(menutopinggroup.SelectionDTO)
+
+	if err := c.Bind(sel); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := sel.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	res, err := con.groupUC.Validate(c.Request().Context(), sel)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,28 @@
+package menutopinggroupweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log     *logger.Log
+	GroupUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.GroupUC, opts.Log)
+
+	// checking a selection only reads groups, so every account is able to do it while ordering
+	web.Echo.POST("/api/v1/menu-toping-groups/validate", con.validate, web.Mid.Authenticated)
+
+	g := web.Echo.Group("/api/v1/menu-toping-groups", web.Mid.Authenticated, web.Mid.Authorize(middlewares.ReadAnyWriteAdmin))
+	g.POST("", con.create)
+	g.GET("", con.getAll)
+	g.PUT("/order", con.reorder)
+	g.GET("/:id", con.getOne)
+	g.PUT("/:id", con.update)
+	g.DELETE("/:id", con.delete)
+	g.PUT("/:id/topings", con.assignTopings)
+}
@@ -0,0 +1,139 @@
+package order
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// statuses which are available on orders table, see order_status enum on migration
+const (
+	StatusPending   = "pending"
+	StatusAccepted  = "accepted"
+	StatusPreparing = "preparing"
+	StatusReady     = "ready"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+var Statuses = []string{StatusPending, StatusAccepted, StatusPreparing, StatusReady, StatusCompleted, StatusCancelled}
+
+// transitions maps a status into statuses which an order could move into from it,
+// completed and cancelled orders are final
+var transitions = map[string][]string{
+	StatusPending:   {StatusAccepted, StatusCancelled},
+	StatusAccepted:  {StatusPreparing, StatusCancelled},
+	StatusPreparing: {StatusReady},
+	StatusReady:     {StatusCompleted},
+}
+
+// CanTransition tells whether an order is allowed to move from a status into another
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// OrderDTO is what we send to client
+type OrderDTO struct {
+	ID        uuid.UUID      `json:"id"`
+	AccountID uuid.UUID      `json:"account_id"`
+	OutletID  uuid.UUID      `json:"outlet_id"`
+	Status    string         `json:"status"`
+	Discount  float64        `json:"discount"` // taken off by the promotion which has been redeemed on the order
+	Total     float64        `json:"total"`    // what is charged, after discount
+	Note      string         `json:"note"`
+	Items     []OrderItemDTO `json:"items,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// OrderItemDTO is a line of an order, name and price are snapshot of the menu at the time of ordering
+type OrderItemDTO struct {
+	ID       uuid.UUID            `json:"id"`
+	OrderID  uuid.UUID            `json:"-"`
+	MenuID   *uuid.UUID           `json:"menu_id"` // nil once the menu is deleted
+	Name     string               `json:"name"`
+	Price    float64              `json:"price"`
+	Quantity int                  `json:"quantity"`
+	Total    float64              `json:"total"`
+	Topings  []OrderItemTopingDTO `json:"topings"`
+}
+
+type OrderItemTopingDTO struct {
+	ID           uuid.UUID  `json:"id"`
+	OrderItemID  uuid.UUID  `json:"-"`
+	MenuTopingID *uuid.UUID `json:"menu_toping_id"` // nil once the toping is deleted
+	Name         string     `json:"name"`
+	Price        float64    `json:"price"`
+}
+
+// MenuPrice is current price of a menu which is about to be ordered
+type MenuPrice struct {
+	ID          uuid.UUID
+	OutletID    uuid.UUID
+	Name        string
+	Price       float64
+	IsAvailable bool
+}
+
+// TopingPrice is current price of a menu toping which is about to be ordered
+type TopingPrice struct {
+	ID          uuid.UUID
+	MenuID      uuid.UUID
+	Name        string
+	Price       float64
+	IsAvailable bool
+}
+
+// NewOrderDTO is what client should send to place an order, prices are never taken from client.
+// The best running promotion is redeemed on the order, a promotion with code is only considered once its code is given
+type NewOrderDTO struct {
+	OutletID      string            `json:"outlet_id"`
+	Note          string            `json:"note"`
+	PromotionCode string            `json:"promotion_code"`
+	Items         []NewOrderItemDTO `json:"items"`
+}
+
+func (d NewOrderDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.OutletID, validation.Required, is.UUID),
+		validation.Field(&d.Note, validation.Length(0, 255)),
+		validation.Field(&d.PromotionCode, validation.Length(3, 30)),
+		validation.Field(&d.Items, validation.Required, validation.Length(1, 50)),
+	)
+}
+
+type NewOrderItemDTO struct {
+	MenuID    string   `json:"menu_id"`
+	Quantity  int      `json:"quantity"`
+	TopingIDs []string `json:"toping_ids"`
+}
+
+func (d NewOrderItemDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuID, validation.Required, is.UUID),
+		validation.Field(&d.Quantity, validation.Required, validation.Min(1), validation.Max(99)),
+		validation.Field(&d.TopingIDs, validation.Length(0, 10), validation.Each(is.UUID)),
+	)
+}
+
+// UpdateStatusDTO is what client should send to move an order into another status
+type UpdateStatusDTO struct {
+	Status string `json:"status"`
+}
+
+func (d UpdateStatusDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Status, validation.Required, validation.In(
+			StatusAccepted, StatusPreparing, StatusReady, StatusCompleted, StatusCancelled,
+		)),
+	)
+}
+
+// RoundPrice rounds an amount into cents, so float arithmetic does not leak into stored prices
+func RoundPrice(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
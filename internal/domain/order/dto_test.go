@@ -0,0 +1,33 @@
+package order
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusPending, StatusAccepted, true},
+		{StatusPending, StatusCancelled, true},
+		{StatusPending, StatusPreparing, false},
+		{StatusPending, StatusPending, false},
+		{StatusAccepted, StatusPreparing, true},
+		{StatusAccepted, StatusCancelled, true},
+		{StatusAccepted, StatusReady, false},
+		{StatusPreparing, StatusReady, true},
+		{StatusPreparing, StatusCancelled, false},
+		{StatusReady, StatusCompleted, true},
+		{StatusReady, StatusPending, false},
+		{StatusCompleted, StatusCancelled, false},
+		{StatusCancelled, StatusPending, false},
+		{"unknown", StatusAccepted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
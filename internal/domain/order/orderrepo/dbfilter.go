@@ -0,0 +1,33 @@
+package orderrepo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goplateframework/internal/domain/order/orderweb"
+)
+
+func (dbrepo *repository) buildFilter(args map[string]any, qp *orderweb.QueryParams) string {
+	var filters []string
+
+	if qp.Filter.AccountID != nil {
+		args["account_id"] = *qp.Filter.AccountID
+		filters = append(filters, " account_id = :account_id")
+	}
+
+	if qp.Filter.OutletID != nil {
+		args["outlet_id"] = *qp.Filter.OutletID
+		filters = append(filters, " outlet_id = :outlet_id")
+	}
+
+	if qp.Filter.Status != "" {
+		args["status"] = qp.Filter.Status
+		filters = append(filters, " status = :status")
+	}
+
+	if len(filters) > 0 {
+		return fmt.Sprintf(" WHERE %s", strings.Join(filters, " AND "))
+	}
+
+	return ""
+}
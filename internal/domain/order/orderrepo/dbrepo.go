@@ -0,0 +1,301 @@
+package orderrepo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/domain/order/orderweb"
+	"github.com/goplateframework/internal/domain/promotion"
+	"github.com/goplateframework/internal/domain/promotion/promotionrepo"
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// GetMenuPrices returns current price of given menus, menus which do not exist are left out
+func (dbrepo *repository) GetMenuPrices(ctx context.Context, ids []uuid.UUID) ([]order.MenuPrice, error) {
+	q, args, err := sqlx.In(`
+	SELECT id, outlet_id, name, price, is_available
+	FROM menus
+	WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbrepo.QueryxContext(ctx, dbrepo.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var menus []order.MenuPrice
+	for rows.Next() {
+		m := new(MenuPriceModel)
+		if err := rows.StructScan(m); err != nil {
+			return nil, err
+		}
+		menus = append(menus, *m.intoDTO())
+	}
+
+	return menus, nil
+}
+
+// GetTopingPrices returns current price of given menu topings, topings which do not exist are left out
+func (dbrepo *repository) GetTopingPrices(ctx context.Context, ids []uuid.UUID) ([]order.TopingPrice, error) {
+	q, args, err := sqlx.In(`
+	SELECT id, menu_id, name, price, is_available
+	FROM menu_topings
+	WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbrepo.QueryxContext(ctx, dbrepo.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var topings []order.TopingPrice
+	for rows.Next() {
+		t := new(TopingPriceModel)
+		if err := rows.StructScan(t); err != nil {
+			return nil, err
+		}
+		topings = append(topings, *t.intoDTO())
+	}
+
+	return topings, nil
+}
+
+// Create stores an order along with its lines and their topings at once, redemption of its promotion is recorded
+// along with it whenever given. See promotionrepo.Redeem for errors of a promotion which could no longer be redeemed
+func (dbrepo *repository) Create(ctx context.Context, o *order.OrderDTO, r *promotion.RedemptionDTO) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `
+	INSERT INTO orders
+		(id, account_id, outlet_id, status, discount, total, note, created_at, updated_at)
+	VALUES
+		(:id, :account_id, :outlet_id, :status, :discount, :total, :note, :created_at, :updated_at)`
+
+	if _, err := tx.NamedExecContext(ctx, q, intoModel(o)); err != nil {
+		return err
+	}
+
+	for i := range o.Items {
+		item := &o.Items[i]
+
+		q := `
+		INSERT INTO order_items
+			(id, order_id, menu_id, name, price, quantity, total)
+		VALUES
+			(:id, :order_id, :menu_id, :name, :price, :quantity, :total)`
+
+		if _, err := tx.NamedExecContext(ctx, q, intoItemModel(item)); err != nil {
+			return err
+		}
+
+		for j := range item.Topings {
+			q := `
+			INSERT INTO order_item_topings
+				(id, order_item_id, menu_toping_id, name, price)
+			VALUES
+				(:id, :order_item_id, :menu_toping_id, :name, :price)`
+
+			if _, err := tx.NamedExecContext(ctx, q, intoItemTopingModel(&item.Topings[j])); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r != nil {
+		if err := promotionrepo.Redeem(ctx, tx, r); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetOne returns an order along with its lines and their topings
+func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*order.OrderDTO, error) {
+	m := new(Model)
+
+	q := `
+	SELECT * FROM orders
+	WHERE id = $1
+	LIMIT 1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	o := m.intoDTO()
+
+	items, err := dbrepo.getItems(ctx, o.ID)
+	if err != nil {
+		return nil, err
+	}
+	o.Items = items
+
+	return o, nil
+}
+
+func (dbrepo *repository) getItems(ctx context.Context, orderID uuid.UUID) ([]order.OrderItemDTO, error) {
+	q := `
+	SELECT * FROM order_items
+	WHERE order_id = $1
+	ORDER BY name ASC`
+
+	rows, err := dbrepo.QueryxContext(ctx, q, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []order.OrderItemDTO
+	index := make(map[uuid.UUID]int)
+	for rows.Next() {
+		i := new(ItemModel)
+		if err := rows.StructScan(i); err != nil {
+			return nil, err
+		}
+		index[i.ID] = len(items)
+		items = append(items, *i.intoDTO())
+	}
+
+	q = `
+	SELECT t.* FROM order_item_topings t
+	INNER JOIN order_items i
+		ON t.order_item_id = i.id
+	WHERE i.order_id = $1
+	ORDER BY t.name ASC`
+
+	topingRows, err := dbrepo.QueryxContext(ctx, q, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer topingRows.Close()
+
+	for topingRows.Next() {
+		t := new(ItemTopingModel)
+		if err := topingRows.StructScan(t); err != nil {
+			return nil, err
+		}
+
+		if i, ok := index[t.OrderItemID]; ok {
+			items[i].Topings = append(items[i].Topings, *t.intoDTO())
+		}
+	}
+
+	return items, nil
+}
+
+// Count returns total of orders which match filters of given query params
+func (dbrepo *repository) Count(ctx context.Context, qp *orderweb.QueryParams) (int, error) {
+	args := map[string]any{}
+	q := "SELECT COUNT(*) AS total FROM orders" + dbrepo.buildFilter(args, qp)
+
+	stmt, err := dbrepo.PrepareNamedContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var count struct {
+		Total int `db:"total"`
+	}
+
+	if err := stmt.GetContext(ctx, &count, args); err != nil {
+		return 0, err
+	}
+
+	return count.Total, nil
+}
+
+// GetAll lists orders without their lines
+func (dbrepo *repository) GetAll(ctx context.Context, qp *orderweb.QueryParams) ([]order.OrderDTO, error) {
+	args := map[string]any{
+		"size":   qp.Page.Size,
+		"offset": qp.Page.Offset,
+	}
+
+	var qb strings.Builder
+	qb.WriteString(`
+		SELECT * FROM orders
+	`)
+
+	qb.WriteString(dbrepo.buildFilter(args, qp))
+	qb.WriteString(fmt.Sprintf(" ORDER BY %s %s", qp.OrderBy.Field, qp.OrderBy.Direction))
+	qb.WriteString(" OFFSET :offset LIMIT :size")
+
+	rows, err := dbrepo.NamedQueryContext(ctx, qb.String(), args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []order.OrderDTO
+	for rows.Next() {
+		o := new(Model)
+		if err := rows.StructScan(o); err != nil {
+			return nil, err
+		}
+		orders = append(orders, *o.intoDTO())
+	}
+
+	return orders, nil
+}
+
+// UpdateStatus moves an order from a status into another, sql.ErrNoRows is returned whenever
+// the order is no longer on the expected status, since another request has moved it first
+func (dbrepo *repository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `
+	UPDATE orders
+	SET status = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1 AND status = $2`
+
+	res, err := tx.ExecContext(ctx, q, id, from, to)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	// promotion which has been redeemed on a cancelled order is given back, so cancelling never uses it up
+	if to == order.StatusCancelled {
+		if err := promotionrepo.Release(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,147 @@
+package orderrepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/order"
+)
+
+type Model struct {
+	ID        uuid.UUID `db:"id"`
+	AccountID uuid.UUID `db:"account_id"`
+	OutletID  uuid.UUID `db:"outlet_id"`
+	Status    string    `db:"status"`
+	Discount  float64   `db:"discount"`
+	Total     float64   `db:"total"`
+	Note      string    `db:"note"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (m *Model) intoDTO() *order.OrderDTO {
+	return &order.OrderDTO{
+		ID:        m.ID,
+		AccountID: m.AccountID,
+		OutletID:  m.OutletID,
+		Status:    m.Status,
+		Discount:  m.Discount,
+		Total:     m.Total,
+		Note:      m.Note,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func intoModel(o *order.OrderDTO) *Model {
+	return &Model{
+		ID:        o.ID,
+		AccountID: o.AccountID,
+		OutletID:  o.OutletID,
+		Status:    o.Status,
+		Discount:  o.Discount,
+		Total:     o.Total,
+		Note:      o.Note,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+type ItemModel struct {
+	ID       uuid.UUID  `db:"id"`
+	OrderID  uuid.UUID  `db:"order_id"`
+	MenuID   *uuid.UUID `db:"menu_id"`
+	Name     string     `db:"name"`
+	Price    float64    `db:"price"`
+	Quantity int        `db:"quantity"`
+	Total    float64    `db:"total"`
+}
+
+func (m *ItemModel) intoDTO() *order.OrderItemDTO {
+	return &order.OrderItemDTO{
+		ID:       m.ID,
+		OrderID:  m.OrderID,
+		MenuID:   m.MenuID,
+		Name:     m.Name,
+		Price:    m.Price,
+		Quantity: m.Quantity,
+		Total:    m.Total,
+		Topings:  []order.OrderItemTopingDTO{},
+	}
+}
+
+func intoItemModel(i *order.OrderItemDTO) *ItemModel {
+	return &ItemModel{
+		ID:       i.ID,
+		OrderID:  i.OrderID,
+		MenuID:   i.MenuID,
+		Name:     i.Name,
+		Price:    i.Price,
+		Quantity: i.Quantity,
+		Total:    i.Total,
+	}
+}
+
+type ItemTopingModel struct {
+	ID           uuid.UUID  `db:"id"`
+	OrderItemID  uuid.UUID  `db:"order_item_id"`
+	MenuTopingID *uuid.UUID `db:"menu_toping_id"`
+	Name         string     `db:"name"`
+	Price        float64    `db:"price"`
+}
+
+func (m *ItemTopingModel) intoDTO() *order.OrderItemTopingDTO {
+	return &order.OrderItemTopingDTO{
+		ID:           m.ID,
+		OrderItemID:  m.OrderItemID,
+		MenuTopingID: m.MenuTopingID,
+		Name:         m.Name,
+		Price:        m.Price,
+	}
+}
+
+func intoItemTopingModel(t *order.OrderItemTopingDTO) *ItemTopingModel {
+	return &ItemTopingModel{
+		ID:           t.ID,
+		OrderItemID:  t.OrderItemID,
+		MenuTopingID: t.MenuTopingID,
+		Name:         t.Name,
+		Price:        t.Price,
+	}
+}
+
+type MenuPriceModel struct {
+	ID          uuid.UUID `db:"id"`
+	OutletID    uuid.UUID `db:"outlet_id"`
+	Name        string    `db:"name"`
+	Price       float64   `db:"price"`
+	IsAvailable bool      `db:"is_available"`
+}
+
+func (m *MenuPriceModel) intoDTO() *order.MenuPrice {
+	return &order.MenuPrice{
+		ID:          m.ID,
+		OutletID:    m.OutletID,
+		Name:        m.Name,
+		Price:       m.Price,
+		IsAvailable: m.IsAvailable,
+	}
+}
+
+type TopingPriceModel struct {
+	ID          uuid.UUID `db:"id"`
+	MenuID      uuid.UUID `db:"menu_id"`
+	Name        string    `db:"name"`
+	Price       float64   `db:"price"`
+	IsAvailable bool      `db:"is_available"`
+}
+
+func (m *TopingPriceModel) intoDTO() *order.TopingPrice {
+	return &order.TopingPrice{
+		ID:          m.ID,
+		MenuID:      m.MenuID,
+		Name:        m.Name,
+		Price:       m.Price,
+		IsAvailable: m.IsAvailable,
+	}
+}
@@ -0,0 +1,400 @@
+package orderuc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/menutoping"
+	"github.com/goplateframework/internal/domain/menutopinggroup"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/domain/order/orderweb"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/domain/promotion"
+	"github.com/goplateframework/internal/domain/promotion/promotionrepo"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/pkg/logger"
+)
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	GetMenuPrices(ctx context.Context, ids []uuid.UUID) ([]order.MenuPrice, error)
+	GetTopingPrices(ctx context.Context, ids []uuid.UUID) ([]order.TopingPrice, error)
+	Create(ctx context.Context, o *order.OrderDTO, r *promotion.RedemptionDTO) error
+	GetOne(ctx context.Context, id uuid.UUID) (*order.OrderDTO, error)
+	Count(ctx context.Context, qp *orderweb.QueryParams) (int, error)
+	GetAll(ctx context.Context, qp *orderweb.QueryParams) ([]order.OrderDTO, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error
+}
+
+// required staff usecase methods to scope order handling into staff of its outlet
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
+}
+
+// required toping group repository methods to check selected topings against rules of their groups
+type iGroupRepository interface {
+	GetAll(ctx context.Context, menuID uuid.UUID) ([]menutopinggroup.GroupDTO, error)
+	GetTopings(ctx context.Context, menuID uuid.UUID) ([]menutoping.MenuTopingsDTO, error)
+}
+
+// required promotion usecase methods to discount an order by the best promotion which applies on it
+type iPromotionUsecase interface {
+	Apply(ctx context.Context, quote *promotion.QuoteDTO, code string, accountID uuid.UUID) (*promotion.PromotionDTO, error)
+}
+
+// staff roles which are allowed to move an order into a status
+var statusStaffRoles = map[string][]string{
+	order.StatusAccepted:  {outletstaff.RoleManager, outletstaff.RoleCashier},
+	order.StatusPreparing: {outletstaff.RoleManager, outletstaff.RoleKitchen},
+	order.StatusReady:     {outletstaff.RoleManager, outletstaff.RoleKitchen},
+	order.StatusCompleted: {outletstaff.RoleManager, outletstaff.RoleCashier},
+	order.StatusCancelled: {outletstaff.RoleManager, outletstaff.RoleCashier},
+}
+
+type Usecase struct {
+	conf        *config.Config
+	log         *logger.Log
+	repo        iRepository
+	groupRepo   iGroupRepository
+	staffUC     iStaffUsecase
+	promotionUC iPromotionUsecase
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, groupRepo iGroupRepository, staffUC iStaffUsecase, promotionUC iPromotionUsecase) *Usecase {
+	return &Usecase{
+		conf:        conf,
+		log:         log,
+		repo:        repo,
+		groupRepo:   groupRepo,
+		staffUC:     staffUC,
+		promotionUC: promotionUC,
+	}
+}
+
+// Create places an order of the account which owns given claims. Prices are calculated from current
+// price of menus and topings, then snapshot into order lines. The best promotion is redeemed along with the order,
+// so its discount is taken off what is charged
+func (uc *Usecase) Create(ctx context.Context, no *order.NewOrderDTO, claims *tokenutil.AccessTokenClaims) (*order.OrderDTO, error) {
+	outletID := uuid.MustParse(no.OutletID)
+
+	menus, topings, err := uc.prices(ctx, no.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	o := &order.OrderDTO{
+		ID:        uuid.New(),
+		AccountID: claims.AccountID,
+		OutletID:  outletID,
+		Status:    order.StatusPending,
+		Note:      no.Note,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	for _, ni := range no.Items {
+		menuID := uuid.MustParse(ni.MenuID)
+
+		m, ok := menus[menuID]
+		if !ok {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("items: menu %s not found", menuID))
+			return nil, e
+		}
+
+		if m.OutletID != outletID {
+			e := errshttp.New(errshttp.InvalidArgument, "Menu does not belong to the outlet")
+			e.AddDetail(fmt.Sprintf("items: menu %s does not belong to outlet %s", menuID, outletID))
+			return nil, e
+		}
+
+		if !m.IsAvailable {
+			e := errshttp.New(errshttp.FailedPrecondition, "Menu is not available")
+			e.AddDetail(fmt.Sprintf("items: menu %s is not available", menuID))
+			return nil, e
+		}
+
+		item := order.OrderItemDTO{
+			ID:       uuid.New(),
+			OrderID:  o.ID,
+			MenuID:   &m.ID,
+			Name:     m.Name,
+			Price:    m.Price,
+			Quantity: ni.Quantity,
+			Topings:  []order.OrderItemTopingDTO{},
+		}
+
+		unitPrice := m.Price
+		selected := make(map[uuid.UUID]bool, len(ni.TopingIDs))
+		chosen := make([]uuid.UUID, 0, len(ni.TopingIDs))
+
+		for _, rawID := range ni.TopingIDs {
+			topingID := uuid.MustParse(rawID)
+
+			t, ok := topings[topingID]
+			if !ok || t.MenuID != menuID {
+				e := errshttp.New(errshttp.NotFound, "Menu toping not found")
+				e.AddDetail(fmt.Sprintf("items: toping %s not found on menu %s", topingID, menuID))
+				return nil, e
+			}
+
+			if !t.IsAvailable {
+				e := errshttp.New(errshttp.FailedPrecondition, "Menu toping is not available")
+				e.AddDetail(fmt.Sprintf("items: toping %s is not available", topingID))
+				return nil, e
+			}
+
+			if selected[topingID] {
+				e := errshttp.New(errshttp.InvalidArgument, "Menu toping is selected more than once")
+				e.AddDetail(fmt.Sprintf("items: toping %s is selected more than once on menu %s", topingID, menuID))
+				return nil, e
+			}
+			selected[topingID] = true
+			chosen = append(chosen, topingID)
+
+			item.Topings = append(item.Topings, order.OrderItemTopingDTO{
+				ID:           uuid.New(),
+				OrderItemID:  item.ID,
+				MenuTopingID: &t.ID,
+				Name:         t.Name,
+				Price:        t.Price,
+			})
+			unitPrice += t.Price
+		}
+
+		if err := uc.checkGroups(ctx, menuID, chosen); err != nil {
+			return nil, err
+		}
+
+		item.Total = order.RoundPrice(unitPrice * float64(item.Quantity))
+		o.Total = order.RoundPrice(o.Total + item.Total)
+		o.Items = append(o.Items, item)
+	}
+
+	r, err := uc.discount(ctx, o, no.PromotionCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, o, r); err != nil {
+		switch err {
+		case promotionrepo.ErrNotRedeemable, sql.ErrNoRows:
+			e := errshttp.New(errshttp.Aborted, "Promotion is no longer running")
+			e.AddDetail("promotion_code: promotion has ended in the meantime, place the order again")
+			return nil, e
+		case promotionrepo.ErrUsageLimitReached:
+			e := errshttp.New(errshttp.Aborted, "Promotion has been used up")
+			e.AddDetail("promotion_code: usage limit has been reached in the meantime, place the order again")
+			return nil, e
+		default:
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+	}
+
+	return o, nil
+}
+
+// checkGroups makes sure topings selected for a menu satisfy every toping group of the menu
+func (uc *Usecase) checkGroups(ctx context.Context, menuID uuid.UUID, selected []uuid.UUID) error {
+	groups, err := uc.groupRepo.GetAll(ctx, menuID)
+	if err != nil {
+		uc.log.Errorf("failed to get toping groups of menu %s: %v", menuID, err)
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	topings, err := uc.groupRepo.GetTopings(ctx, menuID)
+	if err != nil {
+		uc.log.Errorf("failed to get topings of menu %s: %v", menuID, err)
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	res := menutopinggroup.Check(menuID, groups, topings, selected)
+	if res.Valid {
+		return nil
+	}
+
+	e := errshttp.New(errshttp.InvalidArgument, "Selected topings do not satisfy toping groups of the menu")
+	for _, d := range res.Details("items") {
+		e.AddDetail(d)
+	}
+	return e
+}
+
+// discount takes the best promotion off total of an order, it returns redemption which should be recorded
+// along with the order, nil whenever no promotion applies
+func (uc *Usecase) discount(ctx context.Context, o *order.OrderDTO, code string) (*promotion.RedemptionDTO, error) {
+	quote := &promotion.QuoteDTO{
+		OutletID: o.OutletID,
+		Items:    make([]promotion.QuoteItemDTO, 0, len(o.Items)),
+		Subtotal: o.Total,
+	}
+
+	for _, i := range o.Items {
+		quote.Items = append(quote.Items, promotion.QuoteItemDTO{
+			MenuID:   *i.MenuID,
+			Name:     i.Name,
+			Price:    i.Price,
+			Quantity: i.Quantity,
+			Total:    i.Total,
+		})
+	}
+
+	best, err := uc.promotionUC.Apply(ctx, quote, code, o.AccountID)
+	if err != nil || best == nil {
+		return nil, err
+	}
+
+	o.Discount = quote.Discount
+	o.Total = quote.Total
+
+	return &promotion.RedemptionDTO{
+		ID:          uuid.New(),
+		PromotionID: best.ID,
+		AccountID:   o.AccountID,
+		OrderID:     o.ID,
+		Subtotal:    quote.Subtotal,
+		Discount:    quote.Discount,
+		CreatedAt:   o.CreatedAt,
+	}, nil
+}
+
+// prices loads current price of every menu and toping of given items, keyed by their id
+func (uc *Usecase) prices(ctx context.Context, items []order.NewOrderItemDTO) (map[uuid.UUID]order.MenuPrice, map[uuid.UUID]order.TopingPrice, error) {
+	var menuIDs, topingIDs []uuid.UUID
+	for _, i := range items {
+		menuIDs = append(menuIDs, uuid.MustParse(i.MenuID))
+		for _, t := range i.TopingIDs {
+			topingIDs = append(topingIDs, uuid.MustParse(t))
+		}
+	}
+
+	menuPrices, err := uc.repo.GetMenuPrices(ctx, menuIDs)
+	if err != nil {
+		return nil, nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	menus := make(map[uuid.UUID]order.MenuPrice, len(menuPrices))
+	for _, m := range menuPrices {
+		menus[m.ID] = m
+	}
+
+	topings := make(map[uuid.UUID]order.TopingPrice)
+	if len(topingIDs) == 0 {
+		return menus, topings, nil
+	}
+
+	topingPrices, err := uc.repo.GetTopingPrices(ctx, topingIDs)
+	if err != nil {
+		return nil, nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	for _, t := range topingPrices {
+		topings[t.ID] = t
+	}
+
+	return menus, topings, nil
+}
+
+// GetAll lists own orders, or orders of an outlet whenever it is requested by staff of the outlet
+func (uc *Usecase) GetAll(ctx context.Context, qp *orderweb.QueryParams, claims *tokenutil.AccessTokenClaims) (*result.Result[order.OrderDTO], error) {
+	if qp.Filter.OutletID != nil {
+		if err := uc.staffUC.Authorize(ctx, claims, *qp.Filter.OutletID); err != nil {
+			return nil, err
+		}
+	} else {
+		qp.Filter.AccountID = &claims.AccountID
+	}
+
+	total, err := uc.repo.Count(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if total > 0 && !qp.Page.CanPaginate(total) {
+		e := errshttp.New(errshttp.InvalidArgument, "Page requested is out of range")
+		e.AddDetail(fmt.Sprintf("pagination: page number must be between 1 and %d", total))
+		return nil, e
+	}
+
+	o, err := uc.repo.GetAll(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return result.New(o, total, qp.Page.Number, qp.Page.Size), nil
+}
+
+// GetOne returns an order to its owner, or to staff of its outlet
+func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*order.OrderDTO, error) {
+	o, err := uc.getOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.AccountID != claims.AccountID {
+		if err := uc.staffUC.Authorize(ctx, claims, o.OutletID); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// UpdateStatus moves an order into given status, whenever the transition is valid. Staff of the outlet moves
+// orders according to their role, while the owner is only able to cancel its order which is still pending
+func (uc *Usecase) UpdateStatus(ctx context.Context, id uuid.UUID, status string, claims *tokenutil.AccessTokenClaims) (*order.OrderDTO, error) {
+	o, err := uc.getOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !order.CanTransition(o.Status, status) {
+		e := errshttp.New(errshttp.FailedPrecondition, "Order could not move into given status")
+		e.AddDetail(fmt.Sprintf("status: order which is %s could not be %s", o.Status, status))
+		return nil, e
+	}
+
+	ownerCancels := o.AccountID == claims.AccountID && o.Status == order.StatusPending && status == order.StatusCancelled
+	if !ownerCancels {
+		if err := uc.staffUC.Authorize(ctx, claims, o.OutletID, statusStaffRoles[status]...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.UpdateStatus(ctx, o.ID, o.Status, status); err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.Aborted, "Order has been updated by another request")
+			e.AddDetail("status: order status has changed, fetch the order and try again")
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	o.Status = status
+	o.UpdatedAt = time.Now()
+
+	return o, nil
+}
+
+func (uc *Usecase) getOne(ctx context.Context, id uuid.UUID) (*order.OrderDTO, error) {
+	o, err := uc.repo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Order not found")
+			e.AddDetail(fmt.Sprintf("data: order with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return o, nil
+}
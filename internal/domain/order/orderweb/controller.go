@@ -0,0 +1,132 @@
+package orderweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type iUsecase interface {
+	Create(ctx context.Context, no *order.NewOrderDTO, claims *tokenutil.AccessTokenClaims) (*order.OrderDTO, error)
+	GetAll(ctx context.Context, qp *QueryParams, claims *tokenutil.AccessTokenClaims) (*result.Result[order.OrderDTO], error)
+	GetOne(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*order.OrderDTO, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, claims *tokenutil.AccessTokenClaims) (*order.OrderDTO, error)
+}
+
+type controller struct {
+	orderUC iUsecase
+	log     *logger.Log
+}
+
+func newController(orderUC iUsecase, log *logger.Log) *controller {
+	return &controller{orderUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	dto := new(order.NewOrderDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	o, err := con.orderUC.Create(c.Request().Context(), dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, o)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	qp, err := getQueryParams(c).Parse()
+
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
+		e.AddDetail(err.Error())
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	o, err := con.orderUC.GetAll(c.Request().Context(), qp, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, o)
+}
+
+func (con *controller) getOne(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Order id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	o, err := con.orderUC.GetOne(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, o)
+}
+
+func (con *controller) updateStatus(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Order id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	dto := new(order.UpdateStatusDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	o, err := con.orderUC.UpdateStatus(c.Request().Context(), id, dto.Status, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, o)
+}
@@ -0,0 +1,104 @@
+package orderweb
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/web/queryparams"
+	"github.com/labstack/echo/v4"
+)
+
+// Supported query params for this order web layer
+type UnparsedQueryParams struct {
+	page     string
+	size     string
+	orderBy  string
+	status   string
+	outletID string // orders of an outlet, only for its staff. Own orders are listed without it
+}
+
+func getQueryParams(c echo.Context) *UnparsedQueryParams {
+	return &UnparsedQueryParams{
+		page:     c.QueryParam("page"),
+		size:     c.QueryParam("size"),
+		orderBy:  c.QueryParam("order_by"),
+		status:   c.QueryParam("status"),
+		outletID: c.QueryParam("outlet_id"),
+	}
+}
+
+// Populated query params to send to repository
+type QueryParams struct {
+	Page    *queryparams.Page
+	OrderBy *queryparams.OrderBy
+	Filter  struct {
+		Status    string
+		OutletID  *uuid.UUID
+		AccountID *uuid.UUID // set by usecase, never by client
+	}
+}
+
+func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
+	qp := new(QueryParams)
+
+	if err := uqp.setPage(qp); err != nil {
+		return nil, err
+	}
+
+	if err := uqp.setOrderBy(qp); err != nil {
+		return nil, err
+	}
+
+	if err := uqp.setFilter(qp); err != nil {
+		return nil, err
+	}
+
+	return qp, nil
+}
+
+func (uqp *UnparsedQueryParams) setPage(qp *QueryParams) error {
+	page, err := queryparams.ParsePage(uqp.page, uqp.size)
+	if err != nil {
+		return err
+	}
+
+	qp.Page = page
+	return nil
+}
+
+var allowedOrderByFields = []string{"total", "status", "created_at"}
+
+func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
+	defaultOrderBy := queryparams.NewOrderBy(
+		"created_at",
+		queryparams.DescOrder,
+	)
+
+	orderBy, err := queryparams.ParseOrderBy(allowedOrderByFields, uqp.orderBy, defaultOrderBy)
+	if err != nil {
+		return err
+	}
+
+	qp.OrderBy = orderBy
+	return nil
+}
+
+func (uqp *UnparsedQueryParams) setFilter(qp *QueryParams) error {
+	if uqp.status != "" && !slices.Contains(order.Statuses, uqp.status) {
+		return errors.New("status: unknown order status")
+	}
+
+	if uqp.outletID != "" {
+		id, err := uuid.Parse(uqp.outletID)
+		if err != nil {
+			return errors.New("outlet_id: should be valid UUID")
+		}
+		qp.Filter.OutletID = &id
+	}
+
+	qp.Filter.Status = uqp.status
+
+	return nil
+}
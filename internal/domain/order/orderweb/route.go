@@ -0,0 +1,22 @@
+package orderweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log     *logger.Log
+	OrderUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.OrderUC, opts.Log)
+
+	// every account is able to order, access to each order is scoped on usecase
+	g := web.Echo.Group("/api/v1/order", web.Mid.Authenticated)
+	g.POST("", con.create)
+	g.GET("", con.getAll)
+	g.GET("/:id", con.getOne)
+	g.PUT("/:id/status", con.updateStatus)
+}
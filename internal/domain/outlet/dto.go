@@ -1,6 +1,9 @@
 package outlet
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -9,31 +12,147 @@ import (
 	"github.com/goplateframework/internal/sdk/validate"
 )
 
+// DefaultTimezone is used by outlets which have not set their own timezone
+const DefaultTimezone = "Asia/Jakarta"
+
 type OutletDTO struct {
-	ID          uuid.UUID           `json:"id"`
-	Name        string              `json:"name"`
-	Phone       string              `json:"phone"`
-	OpeningTime string              `json:"opening_time"`
-	ClosingTime string              `json:"closing_time"`
-	CreatedAt   time.Time           `json:"created_at"`
-	UpdatedAt   time.Time           `json:"updated_at"`
-	Address     *address.AddressDTO `json:"address"`
+	ID        uuid.UUID           `json:"id"`
+	Name      string              `json:"name"`
+	Phone     string              `json:"phone"`
+	Timezone  string              `json:"timezone"`
+	IsOpenNow bool                `json:"is_open_now"` // computed on every read, never stored
+	Schedule  []ScheduleDTO       `json:"schedule"`
+	Closures  []ClosureDTO        `json:"closures"`    // upcoming ones only
+	Distance  *float64            `json:"distance_km"` // only set on nearby search, from the point being searched
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Address   *address.AddressDTO `json:"address"`
+}
+
+// IsOpenAt tells whether outlet is open at given time, according to its schedule on local time of the outlet.
+// Outlet is closed all day on its closure dates
+func (o *OutletDTO) IsOpenAt(t time.Time) bool {
+	loc, err := time.LoadLocation(o.Timezone)
+	if err != nil {
+		loc, _ = time.LoadLocation(DefaultTimezone)
+	}
+
+	local := t.In(loc)
+
+	date := local.Format(dateLayout)
+	for _, c := range o.Closures {
+		if c.Date == date {
+			return false
+		}
+	}
+
+	clock := local.Format(clockLayout)
+	for _, s := range o.Schedule {
+		if s.Weekday == int(local.Weekday()) && s.OpensAt <= clock && clock < s.ClosesAt {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	clockLayout = "15:04"
+	dateLayout  = "2006-01-02"
+)
+
+// clock accepts 00:00 up to 23:59, closing clock accepts 24:00 as well to close at midnight
+var (
+	openingClock = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+	closingClock = regexp.MustCompile(`^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$`)
+)
+
+// ScheduleDTO is an interval of opening hours on a weekday, weekday 0 is sunday.
+// Clocks are HH:MM on local time of the outlet, closes_at is exclusive
+type ScheduleDTO struct {
+	Weekday  int    `json:"weekday"`
+	OpensAt  string `json:"opens_at"`
+	ClosesAt string `json:"closes_at"`
+}
+
+func (s ScheduleDTO) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Weekday, validation.Min(0), validation.Max(6)),
+		validation.Field(&s.OpensAt, validation.Required, validation.Match(openingClock).Error("must be a clock of HH:MM")),
+		validation.Field(&s.ClosesAt, validation.Required, validation.Match(closingClock).Error("must be a clock of HH:MM"),
+			validation.By(func(interface{}) error {
+				// clocks of HH:MM are ordered the same way as strings
+				if s.ClosesAt <= s.OpensAt {
+					return errors.New("must be after opens_at")
+				}
+				return nil
+			})),
+	)
+}
+
+// ClosureDTO is a date on which outlet is closed all day, such as a holiday
+type ClosureDTO struct {
+	ID     uuid.UUID `json:"id"`
+	Date   string    `json:"date"` // YYYY-MM-DD on local time of the outlet
+	Reason string    `json:"reason"`
 }
 
 type NewOutletDTO struct {
-	Name        string              `json:"name"`
-	Phone       string              `json:"phone"`
-	OpeningTime string              `json:"opening_time"`
-	ClosingTime string              `json:"closing_time"`
-	Address     *address.AddressDTO `json:"address"`
+	Name     string              `json:"name"`
+	Phone    string              `json:"phone"`
+	Timezone string              `json:"timezone"` // IANA name, DefaultTimezone is used whenever it is empty
+	Address  *address.AddressDTO `json:"address"`
 }
 
 func (o NewOutletDTO) Validate() error {
 	return validation.ValidateStruct(&o,
 		validation.Field(&o.Name, validation.Required, validation.Length(1, 50)),
 		validation.Field(&o.Phone, validation.Required, validate.Phone),
-		validation.Field(&o.OpeningTime, validation.Required, validate.Timestamp),
-		validation.Field(&o.ClosingTime, validation.Required, validate.Timestamp),
-		validation.Field(&o.Address),
+		validation.Field(&o.Timezone, validation.Length(0, 64), validate.Timezone),
+		validation.Field(&o.Address, validation.By(func(interface{}) error {
+			if o.Address == nil {
+				return nil
+			}
+			return address.ValidateCoordinates(o.Address.Latitude, o.Address.Longitude)
+		})),
+	)
+}
+
+// UpdateScheduleDTO is what client should send to replace weekly schedule of an outlet,
+// outlet without any interval is always closed
+type UpdateScheduleDTO struct {
+	Intervals []ScheduleDTO `json:"intervals"`
+}
+
+func (d UpdateScheduleDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Intervals, validation.Length(0, 70), validation.By(func(interface{}) error {
+			return overlap(d.Intervals)
+		})),
+	)
+}
+
+// overlap makes sure intervals on the same weekday do not overlap each other
+func overlap(intervals []ScheduleDTO) error {
+	for i, a := range intervals {
+		for _, b := range intervals[i+1:] {
+			if a.Weekday == b.Weekday && a.OpensAt < b.ClosesAt && b.OpensAt < a.ClosesAt {
+				return fmt.Errorf("intervals on weekday %d overlap each other", a.Weekday)
+			}
+		}
+	}
+	return nil
+}
+
+// NewClosureDTO is what client should send to close an outlet all day on a date
+type NewClosureDTO struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+func (d NewClosureDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Date, validation.Required, validation.Date(dateLayout).Error("must be a date of YYYY-MM-DD")),
+		validation.Field(&d.Reason, validation.Length(0, 255)),
 	)
 }
@@ -0,0 +1,64 @@
+package outlet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOutletIsOpenAt(t *testing.T) {
+	jakarta, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+
+	o := &OutletDTO{
+		Timezone: DefaultTimezone,
+		Schedule: []ScheduleDTO{
+			{Weekday: 1, OpensAt: "10:00", ClosesAt: "24:00"}, // monday, until midnight
+			{Weekday: 2, OpensAt: "08:00", ClosesAt: "12:00"},
+			{Weekday: 2, OpensAt: "17:00", ClosesAt: "21:00"},
+		},
+		Closures: []ClosureDTO{
+			{Date: "2024-08-13", Reason: "holiday"}, // tuesday
+		},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before opening", time.Date(2024, 8, 5, 9, 59, 0, 0, jakarta), false},
+		{"at opening", time.Date(2024, 8, 5, 10, 0, 0, 0, jakarta), true},
+		{"last minute before 24:00 close", time.Date(2024, 8, 5, 23, 59, 59, 0, jakarta), true},
+		{"midnight after 24:00 close", time.Date(2024, 8, 6, 0, 0, 0, 0, jakarta), false},
+		{"at closing", time.Date(2024, 8, 6, 12, 0, 0, 0, jakarta), false},
+		{"between intervals", time.Date(2024, 8, 6, 15, 0, 0, 0, jakarta), false},
+		{"second interval", time.Date(2024, 8, 6, 17, 30, 0, 0, jakarta), true},
+		{"weekday without schedule", time.Date(2024, 8, 7, 11, 0, 0, 0, jakarta), false},
+		{"closure date", time.Date(2024, 8, 13, 9, 0, 0, 0, jakarta), false},
+		{"day before closure date", time.Date(2024, 8, 12, 11, 0, 0, 0, jakarta), true},
+		{"utc time on local monday night", time.Date(2024, 8, 5, 16, 30, 0, 0, time.UTC), true},
+		{"utc time past local midnight", time.Date(2024, 8, 5, 17, 30, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := o.IsOpenAt(tt.at); got != tt.want {
+				t.Errorf("IsOpenAt(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutletIsOpenAtFallsBackToDefaultTimezone(t *testing.T) {
+	o := &OutletDTO{
+		Timezone: "Nowhere/Invalid",
+		Schedule: []ScheduleDTO{{Weekday: 1, OpensAt: "10:00", ClosesAt: "11:00"}},
+	}
+
+	// 03:30 utc is 10:30 on default timezone
+	if !o.IsOpenAt(time.Date(2024, 8, 5, 3, 30, 0, 0, time.UTC)) {
+		t.Errorf("IsOpenAt() = false, want true on default timezone")
+	}
+}
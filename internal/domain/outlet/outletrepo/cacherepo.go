@@ -0,0 +1,156 @@
+package outletrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/address/addressrepo"
+	"github.com/goplateframework/internal/domain/outlet"
+	"github.com/goplateframework/internal/domain/outlet/outletweb"
+	"github.com/goplateframework/pkg/cacheaside"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	outletExpires  = 10 * time.Minute
+	outletsExpires = 5 * time.Minute
+
+	// every cached outlets page is tagged by it, since any write could shift pages
+	outletsTag = "outlets"
+)
+
+// cachedRepository serves outlet reads through cache-aside, writes invalidate every entry they affect
+type cachedRepository struct {
+	*repository
+	client  *redis.Client
+	log     *logger.Log
+	outlet  *cacheaside.Cache[outlet.OutletDTO]
+	outlets *cacheaside.Cache[[]outlet.OutletDTO]
+}
+
+func NewCachedDB(db *sqlx.DB, client *redis.Client, log *logger.Log) *cachedRepository {
+	return &cachedRepository{
+		repository: NewDB(db),
+		client:     client,
+		log:        log,
+		outlet:     cacheaside.New[outlet.OutletDTO](client, "outlet", outletExpires),
+		outlets:    cacheaside.New[[]outlet.OutletDTO](client, "outlets", outletsExpires),
+	}
+}
+
+func (repo *cachedRepository) GetOne(ctx context.Context, id uuid.UUID) (*outlet.OutletDTO, error) {
+	o, err := repo.outlet.Fetch(ctx, id.String(), func(ctx context.Context) (outlet.OutletDTO, error) {
+		o, err := repo.repository.GetOne(ctx, id)
+		if err != nil {
+			return outlet.OutletDTO{}, err
+		}
+		return *o, nil
+	}, func(o outlet.OutletDTO) []string {
+		return outletTags(&o)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+func (repo *cachedRepository) GetAll(ctx context.Context, qp *outletweb.QueryParams) ([]outlet.OutletDTO, error) {
+	// operating status filter depends on current time, while nearby search hardly repeats the same point,
+	// so both of them skip cache
+	if qp.Filter.Operate != "" || qp.Filter.Near != nil {
+		return repo.repository.GetAll(ctx, qp)
+	}
+
+	key := fmt.Sprintf("%d:%d:%s:%s:%s",
+		qp.Page.Number, qp.Page.Size, qp.OrderBy.Field, qp.OrderBy.Direction, qp.Filter.Name)
+
+	return repo.outlets.Fetch(ctx, key, func(ctx context.Context) ([]outlet.OutletDTO, error) {
+		return repo.repository.GetAll(ctx, qp)
+	}, func(outlets []outlet.OutletDTO) []string {
+		tags := []string{outletsTag}
+		for i := range outlets {
+			tags = append(tags, outletTags(&outlets[i])...)
+		}
+		return tags
+	})
+}
+
+func (repo *cachedRepository) Create(ctx context.Context, o *outlet.OutletDTO, managerID uuid.UUID) error {
+	if err := repo.repository.Create(ctx, o, managerID); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, outletsTag)
+	return nil
+}
+
+func (repo *cachedRepository) Update(ctx context.Context, o *outlet.OutletDTO) error {
+	if err := repo.repository.Update(ctx, o); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, outletsTag, OutletTag(o.ID))
+	return nil
+}
+
+func (repo *cachedRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := repo.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, outletsTag, OutletTag(id))
+	return nil
+}
+
+func (repo *cachedRepository) ReplaceSchedule(ctx context.Context, outletID uuid.UUID, intervals []outlet.ScheduleDTO) error {
+	if err := repo.repository.ReplaceSchedule(ctx, outletID, intervals); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, outletsTag, OutletTag(outletID))
+	return nil
+}
+
+func (repo *cachedRepository) CreateClosure(ctx context.Context, outletID uuid.UUID, c *outlet.ClosureDTO) error {
+	if err := repo.repository.CreateClosure(ctx, outletID, c); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, outletsTag, OutletTag(outletID))
+	return nil
+}
+
+func (repo *cachedRepository) DeleteClosure(ctx context.Context, outletID, closureID uuid.UUID) error {
+	if err := repo.repository.DeleteClosure(ctx, outletID, closureID); err != nil {
+		return err
+	}
+
+	repo.invalidate(ctx, outletsTag, OutletTag(outletID))
+	return nil
+}
+
+// invalidate only logs failures, since the write has already been committed and cached entries expire on their own
+func (repo *cachedRepository) invalidate(ctx context.Context, tags ...string) {
+	if err := cacheaside.InvalidateTags(ctx, repo.client, tags...); err != nil {
+		repo.log.Errorf("failed to invalidate cache tags %v, %v", tags, err)
+	}
+}
+
+// OutletTag tags every cached entry which shows the outlet, it is invalidated whenever the outlet changes
+func OutletTag(id uuid.UUID) string {
+	return fmt.Sprintf("outlet:%s", id.String())
+}
+
+func outletTags(o *outlet.OutletDTO) []string {
+	tags := []string{OutletTag(o.ID)}
+	if o.Address != nil {
+		tags = append(tags, addressrepo.AddressTag(o.Address.ID))
+	}
+	return tags
+}
@@ -2,6 +2,7 @@ package outletrepo
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/goplateframework/internal/domain/outlet/outletweb"
@@ -12,7 +13,7 @@ func (dbrepo *repository) buildFilter(args map[string]any, qp *outletweb.QueryPa
 
 	if qp.Filter.Name != "" {
 		args["name"] = "%" + qp.Filter.Name + "%"
-		filters = append(filters, " name ILIKE :name")
+		filters = append(filters, " o.name ILIKE :name")
 	}
 
 	if qp.Filter.Operate != "" {
@@ -20,15 +21,70 @@ func (dbrepo *repository) buildFilter(args map[string]any, qp *outletweb.QueryPa
 		// it can be done directly by appending query string
 
 		if qp.Filter.Operate == "open" {
-			filters = append(filters, " current_timestamp AT TIME ZONE 'Asia/Jakarta' > opening_time")
+			filters = append(filters, " "+openNow)
 		} else if qp.Filter.Operate == "close" {
-			filters = append(filters, " current_timestamp AT TIME ZONE 'Asia/Jakarta' < opening_time")
+			filters = append(filters, " NOT "+openNow)
 		}
 	}
 
+	if near := qp.Filter.Near; near != nil {
+		args["lat"] = near.Lat
+		args["lng"] = near.Lng
+		args["radius"] = near.RadiusKm
+
+		// bounding box is cheap to check and able to use index of coordinates, exact distance is only computed within it
+		latDelta := near.RadiusKm / kmPerDegree
+		args["min_lat"] = near.Lat - latDelta
+		args["max_lat"] = near.Lat + latDelta
+		filters = append(filters, " a.latitude BETWEEN :min_lat AND :max_lat")
+
+		// longitude box is left out whenever it wraps around the antimeridian or the poles, distance alone is enough there
+		cos := math.Cos(near.Lat * math.Pi / 180)
+		if near.Lat+latDelta < 90 && near.Lat-latDelta > -90 && cos > 0 {
+			lngDelta := near.RadiusKm / (kmPerDegree * cos)
+			if near.Lng-lngDelta > -180 && near.Lng+lngDelta < 180 {
+				args["min_lng"] = near.Lng - lngDelta
+				args["max_lng"] = near.Lng + lngDelta
+				filters = append(filters, " a.longitude BETWEEN :min_lng AND :max_lng")
+			}
+		}
+
+		filters = append(filters, " "+distance+" <= :radius")
+	}
+
 	if len(filters) > 0 {
 		return fmt.Sprintf(" WHERE %s", strings.Join(filters, " AND "))
 	}
 
 	return ""
 }
+
+// openNow tells whether an outlet is open at current time on its own timezone, the same way as outlet.OutletDTO.IsOpenAt does.
+// Outlet is open whenever an interval of the current weekday covers current clock, unless current date is one of its closures.
+// Casts are spelled out, since named queries of sqlx take :: as an escaped colon
+const openNow = `(
+	EXISTS (
+		SELECT 1 FROM outlet_schedules s
+		WHERE s.outlet_id = o.id
+			AND s.weekday = EXTRACT(DOW FROM current_timestamp AT TIME ZONE o.timezone)
+			AND CAST(current_timestamp AT TIME ZONE o.timezone AS time) >= s.opens_at
+			AND CAST(current_timestamp AT TIME ZONE o.timezone AS time) < s.closes_at
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM outlet_closures c
+		WHERE c.outlet_id = o.id
+			AND c.date = CAST(current_timestamp AT TIME ZONE o.timezone AS date)
+	)
+)`
+
+// kmPerDegree is length of a degree of latitude, it is the longest one of longitude as well
+const kmPerDegree = 111.32
+
+// distance is great circle distance in km between address and the point being searched, based on haversine formula.
+// least guards asin against rounding which slightly exceeds 1
+const distance = `(
+	2 * 6371 * asin(least(1, sqrt(
+		power(sin(radians(a.latitude - :lat) / 2), 2)
+		+ cos(radians(:lat)) * cos(radians(a.latitude)) * power(sin(radians(a.longitude - :lng) / 2), 2)
+	)))
+)`
@@ -2,12 +2,14 @@ package outletrepo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/goplateframework/internal/domain/outlet"
 	"github.com/goplateframework/internal/domain/outlet/outletweb"
+	"github.com/goplateframework/internal/domain/outletstaff"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -23,7 +25,7 @@ func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*outlet.Out
 	oa := new(ModelWithAddress)
 
 	q := `
-	SELECT o.*, a.street, a.city, a.province, a.postal_code 
+	SELECT o.*, a.street, a.city, a.province, a.postal_code, a.latitude, a.longitude
 		FROM outlets o
 	INNER JOIN addresses a 
 		ON o.address_id = a.id
@@ -34,18 +36,46 @@ func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*outlet.Out
 		return nil, err
 	}
 
-	return oa.intoDTO(), nil
+	outlets := []outlet.OutletDTO{*oa.intoDTO()}
+	if err := dbrepo.attachHours(ctx, outlets); err != nil {
+		return nil, err
+	}
+
+	return &outlets[0], nil
 }
 
-func (dbrepo *repository) Create(ctx context.Context, o *outlet.OutletDTO) error {
+// Create stores an outlet along with the account which manages it in one transaction, so an outlet is never left
+// without its manager. Outlet is created without manager whenever managerID is uuid.Nil
+func (dbrepo *repository) Create(ctx context.Context, o *outlet.OutletDTO, managerID uuid.UUID) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	q := `
 	INSERT INTO outlets
-		(name, phone, opening_time, closing_time, address_id, created_at, updated_at)
+		(id, name, phone, timezone, address_id, created_at, updated_at)
 	VALUES
-		(:name, :phone, :opening_time, :closing_time, :address_id, :created_at, :updated_at)`
+		(:id, :name, :phone, :timezone, :address_id, :created_at, :updated_at)`
 
-	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(o))
-	return err
+	if _, err := tx.NamedExecContext(ctx, q, intoModel(o)); err != nil {
+		return err
+	}
+
+	if managerID != uuid.Nil {
+		q = `
+		INSERT INTO outlet_staff
+			(outlet_id, account_id, role, created_at, updated_at)
+		VALUES
+			($1, $2, $3, $4, $4)`
+
+		if _, err := tx.ExecContext(ctx, q, o.ID, managerID, outletstaff.RoleManager, o.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (dbrepo *repository) Update(ctx context.Context, o *outlet.OutletDTO) error {
@@ -55,8 +85,7 @@ func (dbrepo *repository) Update(ctx context.Context, o *outlet.OutletDTO) error
 	SET
 		name = :name,
 		phone = :phone,
-		opening_time = :opening_time,
-		closing_time = :closing_time,
+		timezone = :timezone,
 		updated_at = :updated_at
 	WHERE id = :id`
 
@@ -64,16 +93,22 @@ func (dbrepo *repository) Update(ctx context.Context, o *outlet.OutletDTO) error
 	return err
 }
 
-func (dbrepo *repository) Count(ctx context.Context) (int, error) {
-	q := `
-	SELECT COUNT(*) AS total FROM outlets`
+// Count returns total of outlets which match filters of given query params
+func (dbrepo *repository) Count(ctx context.Context, qp *outletweb.QueryParams) (int, error) {
+	args := map[string]any{}
+	q := "SELECT COUNT(*) AS total FROM outlets o INNER JOIN addresses a ON o.address_id = a.id" + dbrepo.buildFilter(args, qp)
+
+	stmt, err := dbrepo.PrepareNamedContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
 
 	var count struct {
 		Total int `db:"total"`
 	}
 
-	err := dbrepo.GetContext(ctx, &count, q)
-	if err != nil {
+	if err := stmt.GetContext(ctx, &count, args); err != nil {
 		return 0, err
 	}
 
@@ -89,7 +124,14 @@ func (dbrepo *repository) GetAll(ctx context.Context, qp *outletweb.QueryParams)
 	var qb strings.Builder
 	qb.WriteString(`
 		SELECT 
-			o.*, a.street, a.city, a.province, a.postal_code
+			o.*, a.street, a.city, a.province, a.postal_code, a.latitude, a.longitude
+	`)
+
+	if qp.Filter.Near != nil {
+		qb.WriteString(", " + distance + " AS distance")
+	}
+
+	qb.WriteString(`
 		FROM 
 			outlets o
 		INNER JOIN addresses a
@@ -97,7 +139,13 @@ func (dbrepo *repository) GetAll(ctx context.Context, qp *outletweb.QueryParams)
 	`)
 
 	qb.WriteString(dbrepo.buildFilter(args, qp))
-	qb.WriteString(fmt.Sprintf(" ORDER BY o.%s %s", qp.OrderBy.Field, qp.OrderBy.Direction))
+
+	// distance is not a column of outlets, it is computed on select
+	if qp.OrderBy.Field == "distance" {
+		qb.WriteString(fmt.Sprintf(" ORDER BY distance %s, o.id", qp.OrderBy.Direction))
+	} else {
+		qb.WriteString(fmt.Sprintf(" ORDER BY o.%s %s", qp.OrderBy.Field, qp.OrderBy.Direction))
+	}
 	qb.WriteString(" OFFSET :offset LIMIT :size")
 
 	rows, err := dbrepo.NamedQueryContext(ctx, qb.String(), args)
@@ -115,6 +163,10 @@ func (dbrepo *repository) GetAll(ctx context.Context, qp *outletweb.QueryParams)
 		outlets = append(outlets, *o.intoDTO())
 	}
 
+	if err := dbrepo.attachHours(ctx, outlets); err != nil {
+		return nil, err
+	}
+
 	return outlets, nil
 }
 
@@ -126,3 +178,130 @@ func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
 	_, err := dbrepo.ExecContext(ctx, q, id)
 	return err
 }
+
+// attachHours fills schedule and upcoming closures of given outlets, closures which have passed are left out
+func (dbrepo *repository) attachHours(ctx context.Context, outlets []outlet.OutletDTO) error {
+	if len(outlets) == 0 {
+		return nil
+	}
+
+	index := make(map[uuid.UUID]int, len(outlets))
+	ids := make([]uuid.UUID, 0, len(outlets))
+	for i := range outlets {
+		index[outlets[i].ID] = i
+		ids = append(ids, outlets[i].ID)
+	}
+
+	q, args, err := sqlx.In(`
+	SELECT outlet_id, weekday, to_char(opens_at, 'HH24:MI') AS opens_at, to_char(closes_at, 'HH24:MI') AS closes_at
+		FROM outlet_schedules
+	WHERE outlet_id IN (?)
+	ORDER BY weekday, opens_at`, ids)
+	if err != nil {
+		return err
+	}
+
+	schedules := []ScheduleModel{}
+	if err := dbrepo.SelectContext(ctx, &schedules, dbrepo.Rebind(q), args...); err != nil {
+		return err
+	}
+
+	for i := range schedules {
+		o := &outlets[index[schedules[i].OutletID]]
+		o.Schedule = append(o.Schedule, *schedules[i].intoDTO())
+	}
+
+	// a day before current date is kept, since it may still be current date on timezone of the outlet
+	q, args, err = sqlx.In(`
+	SELECT id, outlet_id, to_char(date, 'YYYY-MM-DD') AS date, reason
+		FROM outlet_closures
+	WHERE outlet_id IN (?) AND date >= CURRENT_DATE - 1
+	ORDER BY date`, ids)
+	if err != nil {
+		return err
+	}
+
+	closures := []ClosureModel{}
+	if err := dbrepo.SelectContext(ctx, &closures, dbrepo.Rebind(q), args...); err != nil {
+		return err
+	}
+
+	for i := range closures {
+		o := &outlets[index[closures[i].OutletID]]
+		o.Closures = append(o.Closures, *closures[i].intoDTO())
+	}
+
+	return nil
+}
+
+// ReplaceSchedule swaps every interval of an outlet with given ones at once
+func (dbrepo *repository) ReplaceSchedule(ctx context.Context, outletID uuid.UUID, intervals []outlet.ScheduleDTO) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM outlet_schedules WHERE outlet_id = $1`, outletID); err != nil {
+		return err
+	}
+
+	q := `
+	INSERT INTO outlet_schedules
+		(outlet_id, weekday, opens_at, closes_at)
+	VALUES
+		($1, $2, $3, $4)`
+
+	for _, s := range intervals {
+		if _, err := tx.ExecContext(ctx, q, outletID, s.Weekday, s.OpensAt, s.ClosesAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CreateClosure closes an outlet all day on a date, sql.ErrNoRows is returned whenever the date has been closed already
+func (dbrepo *repository) CreateClosure(ctx context.Context, outletID uuid.UUID, c *outlet.ClosureDTO) error {
+	q := `
+	INSERT INTO outlet_closures
+		(id, outlet_id, date, reason)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT (outlet_id, date) DO NOTHING`
+
+	res, err := dbrepo.ExecContext(ctx, q, c.ID, outletID, c.Date, c.Reason)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteClosure reopens an outlet on the date of a closure, sql.ErrNoRows is returned whenever the outlet has no such closure
+func (dbrepo *repository) DeleteClosure(ctx context.Context, outletID, closureID uuid.UUID) error {
+	res, err := dbrepo.ExecContext(ctx, `DELETE FROM outlet_closures WHERE id = $1 AND outlet_id = $2`, closureID, outletID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
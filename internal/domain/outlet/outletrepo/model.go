@@ -9,58 +9,95 @@ import (
 )
 
 type Model struct {
-	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Phone       string    `db:"phone"`
-	OpeningTime string    `db:"opening_time"`
-	ClosingTime string    `db:"closing_time"`
-	AddressID   uuid.UUID `db:"address_id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	Phone     string    `db:"phone"`
+	Timezone  string    `db:"timezone"`
+	AddressID uuid.UUID `db:"address_id"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
 }
 
 func intoModel(o *outlet.OutletDTO) *Model {
 	return &Model{
-		ID:          o.ID,
-		Name:        o.Name,
-		Phone:       o.Phone,
-		OpeningTime: o.OpeningTime,
-		ClosingTime: o.ClosingTime,
-		AddressID:   o.Address.ID,
-		UpdatedAt:   o.UpdatedAt,
-		CreatedAt:   o.CreatedAt,
+		ID:        o.ID,
+		Name:      o.Name,
+		Phone:     o.Phone,
+		Timezone:  o.Timezone,
+		AddressID: o.Address.ID,
+		UpdatedAt: o.UpdatedAt,
+		CreatedAt: o.CreatedAt,
 	}
 }
 
 type ModelWithAddress struct {
-	ID          uuid.UUID `db:"id"`
-	Name        string    `db:"name"`
-	Phone       string    `db:"phone"`
-	OpeningTime string    `db:"opening_time"`
-	ClosingTime string    `db:"closing_time"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	AddressID   uuid.UUID `db:"address_id"`
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	Phone     string    `db:"phone"`
+	Timezone  string    `db:"timezone"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	AddressID uuid.UUID `db:"address_id"`
 
-	Street     string `db:"street"`
-	City       string `db:"city"`
-	Province   string `db:"province"`
-	PostalCode string `db:"postal_code"`
+	Street     string   `db:"street"`
+	City       string   `db:"city"`
+	Province   string   `db:"province"`
+	PostalCode string   `db:"postal_code"`
+	Latitude   *float64 `db:"latitude"`
+	Longitude  *float64 `db:"longitude"`
+
+	Distance *float64 `db:"distance"`
 }
 
 func (ma *ModelWithAddress) intoDTO() *outlet.OutletDTO {
 	return &outlet.OutletDTO{
-		ID:          ma.ID,
-		Name:        ma.Name,
-		Phone:       ma.Phone,
-		OpeningTime: ma.OpeningTime,
-		ClosingTime: ma.ClosingTime,
+		ID:        ma.ID,
+		Name:      ma.Name,
+		Phone:     ma.Phone,
+		Timezone:  ma.Timezone,
+		Schedule:  []outlet.ScheduleDTO{},
+		Closures:  []outlet.ClosureDTO{},
+		CreatedAt: ma.CreatedAt,
+		UpdatedAt: ma.UpdatedAt,
+		Distance:  ma.Distance,
 		Address: &address.AddressDTO{
 			ID:         ma.AddressID,
 			Street:     ma.Street,
 			City:       ma.City,
 			Province:   ma.Province,
 			PostalCode: ma.PostalCode,
+			Latitude:   ma.Latitude,
+			Longitude:  ma.Longitude,
 		},
 	}
 }
+
+type ScheduleModel struct {
+	OutletID uuid.UUID `db:"outlet_id"`
+	Weekday  int       `db:"weekday"`
+	OpensAt  string    `db:"opens_at"`
+	ClosesAt string    `db:"closes_at"`
+}
+
+func (m *ScheduleModel) intoDTO() *outlet.ScheduleDTO {
+	return &outlet.ScheduleDTO{
+		Weekday:  m.Weekday,
+		OpensAt:  m.OpensAt,
+		ClosesAt: m.ClosesAt,
+	}
+}
+
+type ClosureModel struct {
+	ID       uuid.UUID `db:"id"`
+	OutletID uuid.UUID `db:"outlet_id"`
+	Date     string    `db:"date"`
+	Reason   string    `db:"reason"`
+}
+
+func (m *ClosureModel) intoDTO() *outlet.ClosureDTO {
+	return &outlet.ClosureDTO{
+		ID:     m.ID,
+		Date:   m.Date,
+		Reason: m.Reason,
+	}
+}
@@ -8,10 +8,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/account"
 	"github.com/goplateframework/internal/domain/address"
 	"github.com/goplateframework/internal/domain/outlet"
 	"github.com/goplateframework/internal/domain/outlet/outletweb"
+	"github.com/goplateframework/internal/domain/outletstaff"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/web/result"
 	"github.com/goplateframework/pkg/logger"
 )
@@ -19,41 +22,58 @@ import (
 type iRepository interface {
 	GetAll(ctx context.Context, qp *outletweb.QueryParams) ([]outlet.OutletDTO, error)
 	GetOne(ctx context.Context, id uuid.UUID) (*outlet.OutletDTO, error)
-	Create(ctx context.Context, a *outlet.OutletDTO) error
+	Create(ctx context.Context, o *outlet.OutletDTO, managerID uuid.UUID) error
 	Update(ctx context.Context, o *outlet.OutletDTO) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	Count(ctx context.Context) (int, error)
+	Count(ctx context.Context, qp *outletweb.QueryParams) (int, error)
+	ReplaceSchedule(ctx context.Context, outletID uuid.UUID, intervals []outlet.ScheduleDTO) error
+	CreateClosure(ctx context.Context, outletID uuid.UUID, c *outlet.ClosureDTO) error
+	DeleteClosure(ctx context.Context, outletID, closureID uuid.UUID) error
+}
+
+// required staff usecase methods to scope outlet mutation into its assigned staff
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
 }
 
 type Usecase struct {
-	conf *config.Config
-	log  *logger.Log
-	repo iRepository
+	conf    *config.Config
+	log     *logger.Log
+	repo    iRepository
+	staffUC iStaffUsecase
 }
 
-func New(conf *config.Config, log *logger.Log, repo iRepository) *Usecase {
+func New(conf *config.Config, log *logger.Log, repo iRepository, staffUC iStaffUsecase) *Usecase {
 	return &Usecase{
-		conf: conf,
-		log:  log,
-		repo: repo,
+		conf:    conf,
+		log:     log,
+		repo:    repo,
+		staffUC: staffUC,
 	}
 }
 
-func (uc *Usecase) Create(ctx context.Context, no *outlet.NewOutletDTO) (*outlet.OutletDTO, error) {
+func (uc *Usecase) Create(ctx context.Context, no *outlet.NewOutletDTO, claims *tokenutil.AccessTokenClaims) (*outlet.OutletDTO, error) {
 	now := time.Now()
 
 	o := &outlet.OutletDTO{
-		ID:          uuid.New(),
-		Name:        no.Name,
-		Phone:       no.Phone,
-		OpeningTime: no.OpeningTime,
-		ClosingTime: no.ClosingTime,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-		Address:     no.Address,
+		ID:        uuid.New(),
+		Name:      no.Name,
+		Phone:     no.Phone,
+		Timezone:  timezone(no.Timezone),
+		Schedule:  []outlet.ScheduleDTO{},
+		Closures:  []outlet.ClosureDTO{},
+		CreatedAt: now,
+		UpdatedAt: now,
+		Address:   no.Address,
+	}
+
+	// superadmin manages every outlet, other creators are in charge of the outlet they just created
+	managerID := uuid.Nil
+	if claims.Role != account.RoleSuperadmin {
+		managerID = claims.AccountID
 	}
 
-	if err := uc.repo.Create(ctx, o); err != nil {
+	if err := uc.repo.Create(ctx, o, managerID); err != nil {
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
@@ -61,12 +81,12 @@ func (uc *Usecase) Create(ctx context.Context, no *outlet.NewOutletDTO) (*outlet
 }
 
 func (uc *Usecase) GetAll(ctx context.Context, qp *outletweb.QueryParams) (*result.Result[outlet.OutletDTO], error) {
-	total, err := uc.repo.Count(ctx)
+	total, err := uc.repo.Count(ctx, qp)
 	if err != nil {
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
-	if !qp.Page.CanPaginate(total) {
+	if total > 0 && !qp.Page.CanPaginate(total) {
 		e := errshttp.New(errshttp.InvalidArgument, "Page requested is out of range")
 		e.AddDetail(fmt.Sprintf("pagination: page number must be between 1 and %d", total))
 		return nil, e
@@ -77,6 +97,11 @@ func (uc *Usecase) GetAll(ctx context.Context, qp *outletweb.QueryParams) (*resu
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
+	now := time.Now()
+	for i := range o {
+		o[i].IsOpenNow = o[i].IsOpenAt(now)
+	}
+
 	return result.New(o, total, qp.Page.Number, qp.Page.Size), nil
 }
 
@@ -93,18 +118,23 @@ func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID) (*outlet.OutletDTO,
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
+	o.IsOpenNow = o.IsOpenAt(time.Now())
+
 	return o, nil
 }
 
-func (uc *Usecase) Update(ctx context.Context, no *outlet.NewOutletDTO, id uuid.UUID) (*outlet.OutletDTO, error) {
+func (uc *Usecase) Update(ctx context.Context, no *outlet.NewOutletDTO, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*outlet.OutletDTO, error) {
+	if err := uc.staffUC.Authorize(ctx, claims, id, outletstaff.RoleManager); err != nil {
+		return nil, err
+	}
+
 	o := &outlet.OutletDTO{
-		ID:          id,
-		Name:        no.Name,
-		Phone:       no.Phone,
-		OpeningTime: no.OpeningTime,
-		ClosingTime: no.ClosingTime,
-		UpdatedAt:   time.Now(),
-		Address:     &address.AddressDTO{},
+		ID:        id,
+		Name:      no.Name,
+		Phone:     no.Phone,
+		Timezone:  timezone(no.Timezone),
+		UpdatedAt: time.Now(),
+		Address:   &address.AddressDTO{},
 	}
 
 	if err := uc.repo.Update(ctx, o); err != nil {
@@ -116,13 +146,91 @@ func (uc *Usecase) Update(ctx context.Context, no *outlet.NewOutletDTO, id uuid.
 		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
+	oa.IsOpenNow = oa.IsOpenAt(time.Now())
+
 	return oa, nil
 }
 
-func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID) error {
+func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	if err := uc.staffUC.Authorize(ctx, claims, id, outletstaff.RoleManager); err != nil {
+		return err
+	}
+
 	if err := uc.repo.Delete(ctx, id); err != nil {
 		return errshttp.New(errshttp.Internal, "Something went wrong")
 	}
 
 	return nil
 }
+
+// UpdateSchedule replaces weekly schedule of an outlet, then returns the outlet along with its new schedule
+func (uc *Usecase) UpdateSchedule(ctx context.Context, id uuid.UUID, us *outlet.UpdateScheduleDTO, claims *tokenutil.AccessTokenClaims) (*outlet.OutletDTO, error) {
+	if err := uc.staffUC.Authorize(ctx, claims, id, outletstaff.RoleManager); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.GetOne(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.ReplaceSchedule(ctx, id, us.Intervals); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return uc.GetOne(ctx, id)
+}
+
+// AddClosure closes an outlet all day on a date, such as a holiday
+func (uc *Usecase) AddClosure(ctx context.Context, id uuid.UUID, nc *outlet.NewClosureDTO, claims *tokenutil.AccessTokenClaims) (*outlet.ClosureDTO, error) {
+	if err := uc.staffUC.Authorize(ctx, claims, id, outletstaff.RoleManager); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.GetOne(ctx, id); err != nil {
+		return nil, err
+	}
+
+	c := &outlet.ClosureDTO{
+		ID:     uuid.New(),
+		Date:   nc.Date,
+		Reason: nc.Reason,
+	}
+
+	if err := uc.repo.CreateClosure(ctx, id, c); err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.AlreadyExists, "Outlet is already closed on given date")
+			e.AddDetail(fmt.Sprintf("date: outlet is closed on %s already", nc.Date))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return c, nil
+}
+
+func (uc *Usecase) RemoveClosure(ctx context.Context, id, closureID uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	if err := uc.staffUC.Authorize(ctx, claims, id, outletstaff.RoleManager); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteClosure(ctx, id, closureID); err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Closure not found")
+			e.AddDetail(fmt.Sprintf("data: closure with id %s not found", closureID))
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// timezone falls back into default one whenever outlet is not given its own timezone
+func timezone(tz string) string {
+	if tz == "" {
+		return outlet.DefaultTimezone
+	}
+	return tz
+}
@@ -8,8 +8,10 @@ import (
 	"github.com/goplateframework/internal/domain/address"
 	"github.com/goplateframework/internal/domain/outlet"
 	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
 	"github.com/goplateframework/internal/sdk/validate"
 	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/internal/web/webcontext"
 	"github.com/goplateframework/pkg/logger"
 	"github.com/labstack/echo/v4"
 )
@@ -23,9 +25,12 @@ type iAddressUsecase interface {
 type iOutletUsecase interface {
 	GetAll(ctx context.Context, qp *QueryParams) (*result.Result[outlet.OutletDTO], error)
 	GetOne(ctx context.Context, id uuid.UUID) (*outlet.OutletDTO, error)
-	Create(ctx context.Context, no *outlet.NewOutletDTO) (*outlet.OutletDTO, error)
-	Update(ctx context.Context, no *outlet.NewOutletDTO, id uuid.UUID) (*outlet.OutletDTO, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Create(ctx context.Context, no *outlet.NewOutletDTO, claims *tokenutil.AccessTokenClaims) (*outlet.OutletDTO, error)
+	Update(ctx context.Context, no *outlet.NewOutletDTO, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*outlet.OutletDTO, error)
+	Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+	UpdateSchedule(ctx context.Context, id uuid.UUID, us *outlet.UpdateScheduleDTO, claims *tokenutil.AccessTokenClaims) (*outlet.OutletDTO, error)
+	AddClosure(ctx context.Context, id uuid.UUID, nc *outlet.NewClosureDTO, claims *tokenutil.AccessTokenClaims) (*outlet.ClosureDTO, error)
+	RemoveClosure(ctx context.Context, id, closureID uuid.UUID, claims *tokenutil.AccessTokenClaims) error
 }
 
 type controller struct {
@@ -61,14 +66,18 @@ func (con *controller) create(c echo.Context) error {
 		City:       no.Address.City,
 		Province:   no.Address.Province,
 		PostalCode: no.Address.PostalCode,
+		Latitude:   no.Address.Latitude,
+		Longitude:  no.Address.Longitude,
 	})
 
 	if err != nil {
 		return err
 	}
 
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
 	no.Address = a
-	o, err := con.outletUC.Create(c.Request().Context(), no)
+	o, err := con.outletUC.Create(c.Request().Context(), no, claims)
 	if err != nil {
 		if err := con.addressUC.Delete(c.Request().Context(), a.ID); err != nil {
 			return err
@@ -80,7 +89,7 @@ func (con *controller) create(c echo.Context) error {
 }
 
 func (con *controller) getAll(c echo.Context) error {
-	qp, err := getQueryParams(c).Parse()
+	qp, err := GetQueryParams(c).Parse()
 
 	if err != nil {
 		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
@@ -139,7 +148,9 @@ func (con *controller) update(c echo.Context) error {
 		return e
 	}
 
-	o, err := con.outletUC.Update(c.Request().Context(), no, id)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	o, err := con.outletUC.Update(c.Request().Context(), no, id, claims)
 	if err != nil {
 		return err
 	}
@@ -155,10 +166,106 @@ func (con *controller) delete(c echo.Context) error {
 		return e
 	}
 
-	err = con.outletUC.Delete(c.Request().Context(), id)
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	err = con.outletUC.Delete(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (con *controller) updateSchedule(c echo.Context) error {
+	us := new(outlet.UpdateScheduleDTO)
+
+	if err := c.Bind(us); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := us.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	o, err := con.outletUC.UpdateSchedule(c.Request().Context(), id, us, claims)
 	if err != nil {
 		return err
 	}
 
+	return c.JSON(http.StatusOK, o)
+}
+
+func (con *controller) addClosure(c echo.Context) error {
+	nc := new(outlet.NewClosureDTO)
+
+	if err := c.Bind(nc); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := nc.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	cl, err := con.outletUC.AddClosure(c.Request().Context(), id, nc, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, cl)
+}
+
+func (con *controller) removeClosure(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	closureID, err := uuid.Parse(c.Param("closure_id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Closure id is invalid, should be valid UUID")
+		e.AddDetail("closure_id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.outletUC.RemoveClosure(c.Request().Context(), id, closureID, claims); err != nil {
+		return err
+	}
+
 	return c.NoContent(http.StatusNoContent)
 }
@@ -2,7 +2,9 @@ package outletweb
 
 import (
 	"errors"
+	"fmt"
 	"slices"
+	"strconv"
 
 	"github.com/goplateframework/internal/web/queryparams"
 	"github.com/labstack/echo/v4"
@@ -10,20 +12,26 @@ import (
 
 // Supported query params for this outlet web layer
 type UnparsedQueryParams struct {
-	page    string
-	size    string
-	orderBy string
-	name    string
-	operate string // open | close
+	page     string
+	size     string
+	orderBy  string
+	name     string
+	operate  string // open | close
+	lat      string
+	lng      string
+	radiusKm string
 }
 
-func getQueryParams(c echo.Context) *UnparsedQueryParams {
+func GetQueryParams(c echo.Context) *UnparsedQueryParams {
 	return &UnparsedQueryParams{
-		page:    c.QueryParam("page"),
-		size:    c.QueryParam("size"),
-		orderBy: c.QueryParam("order_by"),
-		name:    c.QueryParam("name"),
-		operate: c.QueryParam("operate"),
+		page:     c.QueryParam("page"),
+		size:     c.QueryParam("size"),
+		orderBy:  c.QueryParam("order_by"),
+		name:     c.QueryParam("name"),
+		operate:  c.QueryParam("operate"),
+		lat:      c.QueryParam("lat"),
+		lng:      c.QueryParam("lng"),
+		radiusKm: c.QueryParam("radius_km"),
 	}
 }
 
@@ -34,9 +42,22 @@ type QueryParams struct {
 	Filter  struct {
 		Name    string
 		Operate string
+		Near    *Near // nil unless outlets near a point are searched
 	}
 }
 
+// Near is a point to search outlets around, outlets whose address has no coordinates are never found
+type Near struct {
+	Lat      float64
+	Lng      float64
+	RadiusKm float64
+}
+
+const (
+	DefaultRadiusKm = 5.0
+	MaxRadiusKm     = 50.0
+)
+
 func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
 	qp := new(QueryParams)
 
@@ -44,11 +65,12 @@ func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
 		return nil, err
 	}
 
-	if err := uqp.setOrderBy(qp); err != nil {
+	// filter goes first, since ordering by distance depends on nearby search
+	if err := uqp.setFilter(qp); err != nil {
 		return nil, err
 	}
 
-	if err := uqp.setFilter(qp); err != nil {
+	if err := uqp.setOrderBy(qp); err != nil {
 		return nil, err
 	}
 
@@ -65,7 +87,7 @@ func (uqp *UnparsedQueryParams) setPage(qp *QueryParams) error {
 	return nil
 }
 
-var allowedOrderByFields = []string{"name", "opening_time", "created_at"}
+var allowedOrderByFields = []string{"name", "created_at", "distance"}
 
 func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
 	defaultOrderBy := queryparams.NewOrderBy(
@@ -73,11 +95,20 @@ func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
 		queryparams.DescOrder,
 	)
 
+	// nearby search is ordered from the nearest one, unless client asks otherwise
+	if qp.Filter.Near != nil {
+		defaultOrderBy = queryparams.NewOrderBy("distance", queryparams.AscOrder)
+	}
+
 	orderBy, err := queryparams.ParseOrderBy(allowedOrderByFields, uqp.orderBy, defaultOrderBy)
 	if err != nil {
 		return err
 	}
 
+	if orderBy.Field == "distance" && qp.Filter.Near == nil {
+		return errors.New("sorting: distance requires lat and lng")
+	}
+
 	qp.OrderBy = orderBy
 	return nil
 }
@@ -92,5 +123,46 @@ func (uqp *UnparsedQueryParams) setFilter(qp *QueryParams) error {
 	qp.Filter.Operate = uqp.operate
 	qp.Filter.Name = uqp.name
 
+	near, err := uqp.parseNear()
+	if err != nil {
+		return err
+	}
+	qp.Filter.Near = near
+
 	return nil
 }
+
+func (uqp *UnparsedQueryParams) parseNear() (*Near, error) {
+	if uqp.lat == "" && uqp.lng == "" {
+		if uqp.radiusKm != "" {
+			return nil, errors.New("radius_km: requires lat and lng")
+		}
+		return nil, nil
+	}
+
+	if uqp.lat == "" || uqp.lng == "" {
+		return nil, errors.New("lat and lng must be given together")
+	}
+
+	lat, err := strconv.ParseFloat(uqp.lat, 64)
+	if err != nil || !(lat >= -90 && lat <= 90) {
+		return nil, errors.New("lat: must be a number between -90 and 90")
+	}
+
+	lng, err := strconv.ParseFloat(uqp.lng, 64)
+	if err != nil || !(lng >= -180 && lng <= 180) {
+		return nil, errors.New("lng: must be a number between -180 and 180")
+	}
+
+	near := &Near{Lat: lat, Lng: lng, RadiusKm: DefaultRadiusKm}
+
+	if uqp.radiusKm != "" {
+		r, err := strconv.ParseFloat(uqp.radiusKm, 64)
+		if err != nil || !(r > 0 && r <= MaxRadiusKm) {
+			return nil, fmt.Errorf("radius_km: must be a number greater than 0 up to %g", MaxRadiusKm)
+		}
+		near.RadiusKm = r
+	}
+
+	return near, nil
+}
@@ -2,6 +2,7 @@ package outletweb
 
 import (
 	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
 	"github.com/goplateframework/pkg/logger"
 )
 
@@ -14,10 +15,13 @@ type Options struct {
 func Route(web *web.Web, opts *Options) {
 	con := newController(opts.AddressUC, opts.OutletUC, opts.Log)
 
-	g := web.Echo.Group("/api/v1/outlet", web.Mid.Authenticated)
+	g := web.Echo.Group("/api/v1/outlet", web.Mid.Authenticated, web.Mid.Authorize(middlewares.ReadAnyWriteAdmin))
 	g.GET("", con.getAll)
 	g.GET("/:id", con.getOne)
 	g.POST("", con.create)
 	g.PUT("/:id", con.update)
 	g.DELETE("/:id", con.delete)
+	g.PUT("/:id/schedule", con.updateSchedule)
+	g.POST("/:id/closures", con.addClosure)
+	g.DELETE("/:id/closures/:closure_id", con.removeClosure)
 }
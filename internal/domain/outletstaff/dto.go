@@ -0,0 +1,41 @@
+package outletstaff
+
+import (
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// roles which are available on outlet_staff table, see staff_role enum on migration
+const (
+	RoleManager = "manager"
+	RoleCashier = "cashier"
+	RoleKitchen = "kitchen"
+)
+
+// StaffDTO is what we send to client
+type StaffDTO struct {
+	OutletID  uuid.UUID `json:"outlet_id"`
+	AccountID uuid.UUID `json:"account_id"`
+	Role      string    `json:"role"`
+	Firstname string    `json:"firstname"`
+	Lastname  string    `json:"lastname"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewStaffDTO is what client should send to assign an account into an outlet
+type NewStaffDTO struct {
+	AccountID string `json:"account_id"`
+	Role      string `json:"role"`
+}
+
+func (ns NewStaffDTO) Validate() error {
+	return validation.ValidateStruct(&ns,
+		validation.Field(&ns.AccountID, validation.Required, is.UUID),
+		validation.Field(&ns.Role, validation.Required, validation.In(RoleManager, RoleCashier, RoleKitchen)),
+	)
+}
@@ -0,0 +1,96 @@
+package outletstaffrepo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// Upsert assigns an account into an outlet, or changes its role whenever the account is already assigned
+func (dbrepo *repository) Upsert(ctx context.Context, s *outletstaff.StaffDTO) error {
+	q := `
+	INSERT INTO outlet_staff
+		(outlet_id, account_id, role, created_at, updated_at)
+	VALUES
+		(:outlet_id, :account_id, :role, :created_at, :updated_at)
+	ON CONFLICT (outlet_id, account_id) DO UPDATE
+	SET
+		role = EXCLUDED.role,
+		updated_at = EXCLUDED.updated_at`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(s))
+	return err
+}
+
+func (dbrepo *repository) GetAll(ctx context.Context, outletID uuid.UUID) ([]outletstaff.StaffDTO, error) {
+	q := `
+	SELECT 
+		s.*, a.firstname, a.lastname, a.email
+	FROM 
+		outlet_staff s
+	INNER JOIN accounts a
+		ON s.account_id = a.id
+	WHERE s.outlet_id = $1
+	ORDER BY s.created_at ASC`
+
+	rows, err := dbrepo.QueryxContext(ctx, q, outletID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var staff []outletstaff.StaffDTO
+	for rows.Next() {
+		s := new(ModelWithAccount)
+		if err := rows.StructScan(s); err != nil {
+			return nil, err
+		}
+		staff = append(staff, *s.intoDTO())
+	}
+
+	return staff, nil
+}
+
+// GetRole returns role of an account within an outlet, sql.ErrNoRows is returned when account is not assigned
+func (dbrepo *repository) GetRole(ctx context.Context, outletID, accountID uuid.UUID) (string, error) {
+	var role string
+
+	q := `
+	SELECT role FROM outlet_staff
+	WHERE outlet_id = $1 AND account_id = $2
+	LIMIT 1`
+
+	if err := dbrepo.GetContext(ctx, &role, q, outletID, accountID); err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (dbrepo *repository) Delete(ctx context.Context, outletID, accountID uuid.UUID) error {
+	q := `
+	DELETE FROM outlet_staff
+	WHERE outlet_id = $1 AND account_id = $2`
+
+	res, err := dbrepo.ExecContext(ctx, q, outletID, accountID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
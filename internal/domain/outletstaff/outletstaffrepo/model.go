@@ -0,0 +1,47 @@
+package outletstaffrepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/outletstaff"
+)
+
+type Model struct {
+	OutletID  uuid.UUID `db:"outlet_id"`
+	AccountID uuid.UUID `db:"account_id"`
+	Role      string    `db:"role"` // ENUM: manager, cashier, kitchen
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func intoModel(s *outletstaff.StaffDTO) *Model {
+	return &Model{
+		OutletID:  s.OutletID,
+		AccountID: s.AccountID,
+		Role:      s.Role,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+type ModelWithAccount struct {
+	Model
+
+	Firstname string `db:"firstname"`
+	Lastname  string `db:"lastname"`
+	Email     string `db:"email"`
+}
+
+func (ma *ModelWithAccount) intoDTO() *outletstaff.StaffDTO {
+	return &outletstaff.StaffDTO{
+		OutletID:  ma.OutletID,
+		AccountID: ma.AccountID,
+		Role:      ma.Role,
+		Firstname: ma.Firstname,
+		Lastname:  ma.Lastname,
+		Email:     ma.Email,
+		CreatedAt: ma.CreatedAt,
+		UpdatedAt: ma.UpdatedAt,
+	}
+}
@@ -0,0 +1,154 @@
+package outletstaffuc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/pkg/logger"
+)
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	Upsert(ctx context.Context, s *outletstaff.StaffDTO) error
+	GetAll(ctx context.Context, outletID uuid.UUID) ([]outletstaff.StaffDTO, error)
+	GetRole(ctx context.Context, outletID, accountID uuid.UUID) (string, error)
+	Delete(ctx context.Context, outletID, accountID uuid.UUID) error
+}
+
+type iAccountDBRepo interface {
+	GetOne(ctx context.Context, id uuid.UUID) (*account.AccountDTO, error)
+}
+
+type Usecase struct {
+	conf          *config.Config
+	log           *logger.Log
+	repo          iRepository
+	accountDBRepo iAccountDBRepo
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, accountDBRepo iAccountDBRepo) *Usecase {
+	return &Usecase{
+		conf:          conf,
+		log:           log,
+		repo:          repo,
+		accountDBRepo: accountDBRepo,
+	}
+}
+
+// Authorize makes sure account on given claims is assigned into the outlet, superadmin always passes.
+// Whenever staff roles are given, account should hold one of them within the outlet
+func (uc *Usecase) Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error {
+	if claims.Role == account.RoleSuperadmin {
+		return nil
+	}
+
+	role, err := uc.repo.GetRole(ctx, outletID, claims.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.PermissionDenied, "Could not give access to this outlet")
+			e.AddDetail(fmt.Sprintf("outlet_id: account is not assigned into outlet %s", outletID))
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if len(staffRoles) > 0 && !slices.Contains(staffRoles, role) {
+		e := errshttp.New(errshttp.PermissionDenied, "Could not give access to this outlet")
+		e.AddDetail(fmt.Sprintf("role: %s is not allowed to perform this action", role))
+		return e
+	}
+
+	return nil
+}
+
+// Assign puts an account into an outlet, it is used internally as well whenever an admin creates an outlet
+func (uc *Usecase) Assign(ctx context.Context, outletID, accountID uuid.UUID, role string) (*outletstaff.StaffDTO, error) {
+	a, err := uc.accountDBRepo.GetOne(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Account not found")
+			e.AddDetail(fmt.Sprintf("account_id: account with id %s not found", accountID))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	now := time.Now()
+	s := &outletstaff.StaffDTO{
+		OutletID:  outletID,
+		AccountID: a.ID,
+		Role:      role,
+		Firstname: a.Firstname,
+		Lastname:  a.Lastname,
+		Email:     a.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := uc.repo.Upsert(ctx, s); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return s, nil
+}
+
+func (uc *Usecase) Create(ctx context.Context, ns *outletstaff.NewStaffDTO, outletID uuid.UUID, claims *tokenutil.AccessTokenClaims) (*outletstaff.StaffDTO, error) {
+	if err := uc.Authorize(ctx, claims, outletID, outletstaff.RoleManager); err != nil {
+		return nil, err
+	}
+
+	accountID, err := uuid.Parse(ns.AccountID)
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("account_id: invalid")
+		return nil, e
+	}
+
+	return uc.Assign(ctx, outletID, accountID, ns.Role)
+}
+
+func (uc *Usecase) GetAll(ctx context.Context, outletID uuid.UUID, claims *tokenutil.AccessTokenClaims) ([]outletstaff.StaffDTO, error) {
+	if err := uc.Authorize(ctx, claims, outletID); err != nil {
+		return nil, err
+	}
+
+	s, err := uc.repo.GetAll(ctx, outletID)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if s == nil {
+		s = []outletstaff.StaffDTO{}
+	}
+
+	return s, nil
+}
+
+func (uc *Usecase) Delete(ctx context.Context, outletID, accountID uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	if err := uc.Authorize(ctx, claims, outletID, outletstaff.RoleManager); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, outletID, accountID); err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Staff not found")
+			e.AddDetail(fmt.Sprintf("account_id: account %s is not assigned into this outlet", accountID))
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package outletstaffweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// required usecase methods which this controller needs to operate the business logic
+type iUsecase interface {
+	Create(ctx context.Context, ns *outletstaff.NewStaffDTO, outletID uuid.UUID, claims *tokenutil.AccessTokenClaims) (*outletstaff.StaffDTO, error)
+	GetAll(ctx context.Context, outletID uuid.UUID, claims *tokenutil.AccessTokenClaims) ([]outletstaff.StaffDTO, error)
+	Delete(ctx context.Context, outletID, accountID uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+}
+
+type controller struct {
+	staffUC iUsecase
+	log     *logger.Log
+}
+
+func newController(staffUC iUsecase, log *logger.Log) *controller {
+	return &controller{staffUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	ns := new(outletstaff.NewStaffDTO)
+
+	if err := c.Bind(ns); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := ns.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	outletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	s, err := con.staffUC.Create(c.Request().Context(), ns, outletID, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, s)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	outletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	s, err := con.staffUC.GetAll(c.Request().Context(), outletID, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, s)
+}
+
+func (con *controller) delete(c echo.Context) error {
+	outletID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Outlet id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	accountID, err := uuid.Parse(c.Param("account_id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Account id is invalid, should be valid UUID")
+		e.AddDetail("account_id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.staffUC.Delete(c.Request().Context(), outletID, accountID, claims); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
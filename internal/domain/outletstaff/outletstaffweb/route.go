@@ -0,0 +1,21 @@
+package outletstaffweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/internal/web/middlewares"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log     *logger.Log
+	StaffUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.StaffUC, opts.Log)
+
+	g := web.Echo.Group("/api/v1/outlet/:id/staff", web.Mid.Authenticated, web.Mid.RequireRole(middlewares.AdminRoles...))
+	g.POST("", con.create)
+	g.GET("", con.getAll)
+	g.DELETE("/:account_id", con.delete)
+}
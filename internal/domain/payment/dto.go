@@ -0,0 +1,99 @@
+package payment
+
+import (
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+)
+
+// statuses which are available on payments table, see payment_status enum on migration
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusRefunded  = "refunded"
+)
+
+// transitions maps a status into statuses which a payment could move into from it,
+// a failed payment goes back to pending once it is retried and refunded payments are final
+var transitions = map[string][]string{
+	StatusPending:   {StatusSucceeded, StatusFailed},
+	StatusFailed:    {StatusPending},
+	StatusSucceeded: {StatusRefunded},
+}
+
+// CanTransition tells whether a payment is allowed to move from a status into another
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// SourceStatuses returns every status which a payment could move into given status from
+func SourceStatuses(to string) []string {
+	var from []string
+	for s, next := range transitions {
+		if slices.Contains(next, to) {
+			from = append(from, s)
+		}
+	}
+	return from
+}
+
+// PaymentDTO is what we send to client
+type PaymentDTO struct {
+	ID            uuid.UUID    `json:"id"`
+	OrderID       uuid.UUID    `json:"order_id"`
+	AccountID     uuid.UUID    `json:"account_id"`
+	OutletID      uuid.UUID    `json:"outlet_id"`
+	Amount        float64      `json:"amount"`
+	Currency      string       `json:"currency"`
+	Status        string       `json:"status"`
+	Provider      string       `json:"provider"`
+	ProviderRef   *string      `json:"provider_ref"` // nil while charge of the latest attempt is not recorded yet
+	Attempts      int          `json:"attempts"`
+	FailureReason string       `json:"failure_reason"`
+	AttemptList   []AttemptDTO `json:"attempt_list,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// AttemptDTO is a charge which has been created on provider for a payment
+type AttemptDTO struct {
+	ID            uuid.UUID `json:"id"`
+	PaymentID     uuid.UUID `json:"-"`
+	ProviderRef   string    `json:"provider_ref"`
+	Status        string    `json:"status"`
+	FailureReason string    `json:"failure_reason"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewPaymentDTO is what client should send to pay an order, amount is never taken from client
+type NewPaymentDTO struct {
+	OrderID string `json:"order_id"`
+}
+
+func (d NewPaymentDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.OrderID, validation.Required, is.UUID),
+	)
+}
+
+// WebhookEventDTO is what provider sends once a charge has moved into another status
+type WebhookEventDTO struct {
+	ID            string `json:"id"`
+	Reference     string `json:"reference"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason"`
+}
+
+func (d WebhookEventDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.ID, validation.Required, validation.Length(1, 100)),
+		validation.Field(&d.Reference, validation.Required, validation.Length(1, 100)),
+		validation.Field(&d.Status, validation.Required, validation.In(StatusSucceeded, StatusFailed, StatusRefunded)),
+		validation.Field(&d.FailureReason, validation.Length(0, 255)),
+	)
+}
@@ -0,0 +1,242 @@
+package paymentrepo
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/payment"
+	"github.com/jmoiron/sqlx"
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+// GetOne returns a payment along with its attempts
+func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*payment.PaymentDTO, error) {
+	m := new(Model)
+
+	q := `
+	SELECT * FROM payments
+	WHERE id = $1
+	LIMIT 1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	p := m.intoDTO()
+
+	q = `
+	SELECT * FROM payment_attempts
+	WHERE payment_id = $1
+	ORDER BY created_at ASC`
+
+	rows, err := dbrepo.QueryxContext(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a := new(AttemptModel)
+		if err := rows.StructScan(a); err != nil {
+			return nil, err
+		}
+		p.AttemptList = append(p.AttemptList, *a.intoDTO())
+	}
+
+	return p, nil
+}
+
+// GetByOrder returns payment of an order without its attempts
+func (dbrepo *repository) GetByOrder(ctx context.Context, orderID uuid.UUID) (*payment.PaymentDTO, error) {
+	m := new(Model)
+
+	q := `
+	SELECT * FROM payments
+	WHERE order_id = $1
+	LIMIT 1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, orderID).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	return m.intoDTO(), nil
+}
+
+// Begin claims a new attempt of a payment before its charge is created on provider, so two requests
+// never charge the same order at once. First attempt stores the payment, later attempts retry a failed one.
+// Reference of the previous charge is cleared, so a pending payment without reference is one whose charge is not recorded yet.
+// sql.ErrNoRows is returned whenever another request has claimed the attempt first
+func (dbrepo *repository) Begin(ctx context.Context, p *payment.PaymentDTO) error {
+	q := `
+	UPDATE
+		payments
+	SET
+		provider_ref = NULL,
+		status = :status,
+		attempts = :attempts,
+		failure_reason = :failure_reason,
+		updated_at = :updated_at
+	WHERE id = :id AND status = 'failed' AND attempts = :attempts - 1`
+
+	if p.Attempts == 1 {
+		q = `
+		INSERT INTO payments
+			(id, order_id, account_id, outlet_id, amount, currency, status, provider, attempts, failure_reason, created_at, updated_at)
+		VALUES
+			(:id, :order_id, :account_id, :outlet_id, :amount, :currency, :status, :provider, :attempts, :failure_reason, :created_at, :updated_at)
+		ON CONFLICT (order_id) DO NOTHING`
+	}
+
+	res, err := dbrepo.NamedExecContext(ctx, q, intoModel(p))
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RecordAttempt stores charge which has been created for the claimed attempt, the payment follows status of the charge.
+// Recording the same charge again changes nothing, since a resumed attempt gets the same charge back from provider
+func (dbrepo *repository) RecordAttempt(ctx context.Context, a *payment.AttemptDTO) error {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	q := `
+	INSERT INTO payment_attempts
+		(id, payment_id, provider_ref, status, failure_reason, created_at, updated_at)
+	VALUES
+		(:id, :payment_id, :provider_ref, :status, :failure_reason, :created_at, :updated_at)
+	ON CONFLICT (provider_ref) DO NOTHING`
+
+	if _, err := tx.NamedExecContext(ctx, q, intoAttemptModel(a)); err != nil {
+		return err
+	}
+
+	q = `
+	UPDATE
+		payments
+	SET
+		provider_ref = :provider_ref,
+		status = :status,
+		failure_reason = :failure_reason,
+		updated_at = :updated_at
+	WHERE id = :payment_id AND provider_ref IS NULL`
+
+	if _, err := tx.NamedExecContext(ctx, q, intoAttemptModel(a)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateStatus moves charge of given provider reference into a status, whenever it is currently on one of from statuses.
+// It returns false whenever the charge is on another status, and sql.ErrNoRows whenever the reference is unknown
+func (dbrepo *repository) UpdateStatus(ctx context.Context, ref string, from []string, to, reason string) (bool, error) {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	moved, err := transition(ctx, tx, ref, from, to, reason)
+	if err != nil {
+		return false, err
+	}
+
+	return moved, tx.Commit()
+}
+
+// ApplyEvent records a webhook event and moves its charge along with it. It returns false without touching
+// anything whenever the event has been applied before, so redelivered events are idempotent.
+// Nothing is recorded whenever the reference is unknown, so provider could deliver the event again later
+func (dbrepo *repository) ApplyEvent(ctx context.Context, provider string, ev *payment.WebhookEventDTO, from []string) (bool, error) {
+	tx, err := dbrepo.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := `
+	INSERT INTO payment_webhook_events
+		(id, provider, provider_ref, status)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT (provider, id) DO NOTHING`
+
+	res, err := tx.ExecContext(ctx, q, ev.ID, provider, ev.Reference, ev.Status)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if n == 0 {
+		return false, nil
+	}
+
+	moved, err := transition(ctx, tx, ev.Reference, from, ev.Status, ev.FailureReason)
+	if err != nil {
+		return false, err
+	}
+
+	return moved, tx.Commit()
+}
+
+// transition moves an attempt while its row is locked, payment follows only whenever the attempt is its latest one
+func transition(ctx context.Context, tx *sqlx.Tx, ref string, from []string, to, reason string) (bool, error) {
+	var status string
+
+	q := `SELECT status FROM payment_attempts WHERE provider_ref = $1 FOR UPDATE`
+
+	if err := tx.QueryRowxContext(ctx, q, ref).Scan(&status); err != nil {
+		return false, err
+	}
+
+	if !slices.Contains(from, status) {
+		return false, nil
+	}
+
+	q = `
+	UPDATE payment_attempts
+	SET status = $2, failure_reason = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE provider_ref = $1`
+
+	if _, err := tx.ExecContext(ctx, q, ref, to, reason); err != nil {
+		return false, err
+	}
+
+	q = `
+	UPDATE payments
+	SET status = $2, failure_reason = $3, updated_at = CURRENT_TIMESTAMP
+	WHERE provider_ref = $1`
+
+	if _, err := tx.ExecContext(ctx, q, ref, to, reason); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,94 @@
+package paymentrepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/payment"
+)
+
+type Model struct {
+	ID            uuid.UUID `db:"id"`
+	OrderID       uuid.UUID `db:"order_id"`
+	AccountID     uuid.UUID `db:"account_id"`
+	OutletID      uuid.UUID `db:"outlet_id"`
+	Amount        float64   `db:"amount"`
+	Currency      string    `db:"currency"`
+	Status        string    `db:"status"`
+	Provider      string    `db:"provider"`
+	ProviderRef   *string   `db:"provider_ref"`
+	Attempts      int       `db:"attempts"`
+	FailureReason string    `db:"failure_reason"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (m *Model) intoDTO() *payment.PaymentDTO {
+	return &payment.PaymentDTO{
+		ID:            m.ID,
+		OrderID:       m.OrderID,
+		AccountID:     m.AccountID,
+		OutletID:      m.OutletID,
+		Amount:        m.Amount,
+		Currency:      m.Currency,
+		Status:        m.Status,
+		Provider:      m.Provider,
+		ProviderRef:   m.ProviderRef,
+		Attempts:      m.Attempts,
+		FailureReason: m.FailureReason,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func intoModel(p *payment.PaymentDTO) *Model {
+	return &Model{
+		ID:            p.ID,
+		OrderID:       p.OrderID,
+		AccountID:     p.AccountID,
+		OutletID:      p.OutletID,
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Status:        p.Status,
+		Provider:      p.Provider,
+		ProviderRef:   p.ProviderRef,
+		Attempts:      p.Attempts,
+		FailureReason: p.FailureReason,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+type AttemptModel struct {
+	ID            uuid.UUID `db:"id"`
+	PaymentID     uuid.UUID `db:"payment_id"`
+	ProviderRef   string    `db:"provider_ref"`
+	Status        string    `db:"status"`
+	FailureReason string    `db:"failure_reason"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (m *AttemptModel) intoDTO() *payment.AttemptDTO {
+	return &payment.AttemptDTO{
+		ID:            m.ID,
+		PaymentID:     m.PaymentID,
+		ProviderRef:   m.ProviderRef,
+		Status:        m.Status,
+		FailureReason: m.FailureReason,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func intoAttemptModel(a *payment.AttemptDTO) *AttemptModel {
+	return &AttemptModel{
+		ID:            a.ID,
+		PaymentID:     a.PaymentID,
+		ProviderRef:   a.ProviderRef,
+		Status:        a.Status,
+		FailureReason: a.FailureReason,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
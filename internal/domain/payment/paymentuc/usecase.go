@@ -0,0 +1,375 @@
+package paymentuc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/domain/payment"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/goplateframework/pkg/paymentprovider"
+)
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	GetOne(ctx context.Context, id uuid.UUID) (*payment.PaymentDTO, error)
+	GetByOrder(ctx context.Context, orderID uuid.UUID) (*payment.PaymentDTO, error)
+	Begin(ctx context.Context, p *payment.PaymentDTO) error
+	RecordAttempt(ctx context.Context, a *payment.AttemptDTO) error
+	UpdateStatus(ctx context.Context, ref string, from []string, to, reason string) (bool, error)
+	ApplyEvent(ctx context.Context, provider string, ev *payment.WebhookEventDTO, from []string) (bool, error)
+}
+
+// required order repository methods to price a payment from its order
+type iOrderRepository interface {
+	GetOne(ctx context.Context, id uuid.UUID) (*order.OrderDTO, error)
+}
+
+// required staff usecase methods to scope payment handling into staff of its outlet
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
+}
+
+type Usecase struct {
+	conf      *config.Config
+	log       *logger.Log
+	repo      iRepository
+	orderRepo iOrderRepository
+	provider  paymentprovider.Provider
+	staffUC   iStaffUsecase
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, orderRepo iOrderRepository, provider paymentprovider.Provider, staffUC iStaffUsecase) *Usecase {
+	return &Usecase{
+		conf:      conf,
+		log:       log,
+		repo:      repo,
+		orderRepo: orderRepo,
+		provider:  provider,
+		staffUC:   staffUC,
+	}
+}
+
+// Create charges total of an order on provider, or retries its payment whenever the previous attempt has failed.
+// Amount is always taken from the order, whose prices come from menus on ordering.
+// Attempt whose charge may have been created is never failed locally, it stays pending and is resumed
+// through the same idempotency key, so provider returns the same charge instead of charging twice
+func (uc *Usecase) Create(ctx context.Context, np *payment.NewPaymentDTO, claims *tokenutil.AccessTokenClaims) (*payment.PaymentDTO, error) {
+	orderID := uuid.MustParse(np.OrderID)
+
+	o, err := uc.orderRepo.GetOne(ctx, orderID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Order not found")
+			e.AddDetail(fmt.Sprintf("order_id: order with id %s not found", orderID))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if o.AccountID != claims.AccountID {
+		e := errshttp.New(errshttp.PermissionDenied, "Could not pay order of another account")
+		e.AddDetail("order_id: order belongs to another account")
+		return nil, e
+	}
+
+	if o.Status == order.StatusCancelled {
+		e := errshttp.New(errshttp.FailedPrecondition, "Order has been cancelled")
+		e.AddDetail("order_id: cancelled order could not be paid")
+		return nil, e
+	}
+
+	p, resumed, err := uc.nextAttempt(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if !resumed {
+		if err := uc.repo.Begin(ctx, p); err != nil {
+			if err == sql.ErrNoRows {
+				e := errshttp.New(errshttp.Aborted, "Payment has been started by another request")
+				e.AddDetail("order_id: payment of the order is being processed, fetch the payment and try again")
+				return nil, e
+			}
+
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+	}
+
+	charge, err := uc.provider.CreateCharge(ctx, &paymentprovider.ChargeRequest{
+		IdempotencyKey: fmt.Sprintf("%s:%d", p.ID, p.Attempts),
+		Amount:         p.Amount,
+		Currency:       p.Currency,
+		Description:    fmt.Sprintf("order %s", o.ID),
+	})
+	if err != nil {
+		// provider may have created the charge anyway, so the attempt stays pending until it is resumed
+		uc.log.Errorf("failed to create charge of payment %s: %v", p.ID, err)
+		e := errshttp.New(errshttp.Unavailable, "Payment provider is unavailable")
+		e.AddDetail("order_id: payment is kept pending, try again to resume it")
+		return nil, e
+	}
+
+	now := time.Now()
+	a := &payment.AttemptDTO{
+		ID:            uuid.New(),
+		PaymentID:     p.ID,
+		ProviderRef:   charge.Reference,
+		Status:        charge.Status,
+		FailureReason: charge.FailureReason,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := uc.repo.RecordAttempt(ctx, a); err != nil {
+		uc.log.Errorf("failed to record charge %s of payment %s: %v", charge.Reference, p.ID, err)
+		e := errshttp.New(errshttp.Internal, "Something went wrong")
+		e.AddDetail("order_id: payment is kept pending, try again to resume it")
+		return nil, e
+	}
+
+	return uc.getOne(ctx, p.ID)
+}
+
+// nextAttempt prepares payment of an order for its next attempt, a new payment is prepared whenever the order has none.
+// A pending payment whose charge is not recorded is resumed as is, it is reported as resumed so it is not claimed again.
+// Charge of the previous attempt is reconciled with provider first, a new attempt is opened only once it has failed there
+func (uc *Usecase) nextAttempt(ctx context.Context, o *order.OrderDTO) (*payment.PaymentDTO, bool, error) {
+	now := time.Now()
+
+	p, err := uc.repo.GetByOrder(ctx, o.ID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return nil, false, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		return &payment.PaymentDTO{
+			ID:        uuid.New(),
+			OrderID:   o.ID,
+			AccountID: o.AccountID,
+			OutletID:  o.OutletID,
+			Amount:    o.Total,
+			Currency:  uc.currency(),
+			Status:    payment.StatusPending,
+			Provider:  uc.provider.Name(),
+			Attempts:  1,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}, false, nil
+	}
+
+	if p.Status == payment.StatusPending && p.ProviderRef == nil {
+		return p, true, nil
+	}
+
+	status := p.Status
+	if p.ProviderRef != nil && status != payment.StatusSucceeded && status != payment.StatusRefunded {
+		charge, err := uc.reconcile(ctx, p)
+		if err != nil {
+			return nil, false, errshttp.New(errshttp.Unavailable, "Payment provider is unavailable")
+		}
+		status = charge.Status
+	}
+
+	switch status {
+	case payment.StatusPending:
+		e := errshttp.New(errshttp.FailedPrecondition, "Payment of the order is still pending")
+		e.AddDetail(fmt.Sprintf("order_id: order is awaiting payment %s", p.ID))
+		return nil, false, e
+	case payment.StatusSucceeded, payment.StatusRefunded:
+		e := errshttp.New(errshttp.FailedPrecondition, "Order has been paid")
+		e.AddDetail(fmt.Sprintf("order_id: order has been paid by payment %s", p.ID))
+		return nil, false, e
+	}
+
+	if p.Attempts >= uc.maxAttempts() {
+		e := errshttp.New(errshttp.ResourceExhausted, "Payment attempts are exhausted")
+		e.AddDetail(fmt.Sprintf("order_id: payment could be attempted at most %d times", uc.maxAttempts()))
+		return nil, false, e
+	}
+
+	p.Attempts++
+	p.Status = payment.StatusPending
+	p.FailureReason = ""
+	p.UpdatedAt = now
+
+	return p, false, nil
+}
+
+// GetOne returns a payment to owner of its order, or to staff of its outlet.
+// Pending payment is synchronized with provider first, in case its webhook has not arrived yet
+func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*payment.PaymentDTO, error) {
+	p, err := uc.getOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.AccountID != claims.AccountID {
+		if err := uc.staffUC.Authorize(ctx, claims, p.OutletID); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.Status != payment.StatusPending || p.ProviderRef == nil {
+		return p, nil
+	}
+
+	// stale status is still worth returning, webhook settles it later on
+	charge, err := uc.reconcile(ctx, p)
+	if err != nil || charge.Status == p.Status {
+		return p, nil
+	}
+
+	return uc.getOne(ctx, p.ID)
+}
+
+// Refund gives money of a succeeded payment back, only manager of its outlet is allowed to do so
+func (uc *Usecase) Refund(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*payment.PaymentDTO, error) {
+	p, err := uc.getOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.staffUC.Authorize(ctx, claims, p.OutletID, outletstaff.RoleManager); err != nil {
+		return nil, err
+	}
+
+	if p.Status != payment.StatusSucceeded || p.ProviderRef == nil {
+		e := errshttp.New(errshttp.FailedPrecondition, "Payment could not be refunded")
+		e.AddDetail(fmt.Sprintf("status: payment which is %s could not be refunded", p.Status))
+		return nil, e
+	}
+
+	if _, err := uc.provider.Refund(ctx, *p.ProviderRef); err != nil {
+		if err == paymentprovider.ErrNotRefundable {
+			e := errshttp.New(errshttp.FailedPrecondition, "Payment could not be refunded")
+			e.AddDetail("status: charge is not refundable on provider")
+			return nil, e
+		}
+
+		uc.log.Errorf("failed to refund charge %s of payment %s: %v", *p.ProviderRef, p.ID, err)
+		return nil, errshttp.New(errshttp.Unavailable, "Payment provider is unavailable")
+	}
+
+	moved, err := uc.repo.UpdateStatus(ctx, *p.ProviderRef, []string{payment.StatusSucceeded}, payment.StatusRefunded, "")
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !moved {
+		e := errshttp.New(errshttp.Aborted, "Payment has been updated by another request")
+		e.AddDetail("status: payment status has changed, fetch the payment and try again")
+		return nil, e
+	}
+
+	return uc.getOne(ctx, p.ID)
+}
+
+// HandleWebhook verifies signature of a webhook payload and applies its event. Event which has been applied before,
+// or which does not move its charge forward, is acknowledged without changing anything
+func (uc *Usecase) HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error {
+	if provider != uc.provider.Name() {
+		e := errshttp.New(errshttp.NotFound, "Payment provider not found")
+		e.AddDetail(fmt.Sprintf("provider: %s is not configured", provider))
+		return e
+	}
+
+	if !paymentprovider.VerifySignature(uc.conf.Payment.WebhookSecret, payload, signature) {
+		e := errshttp.New(errshttp.InvalidCredentials, "Webhook signature is invalid")
+		e.AddDetail("signature: invalid")
+		return e
+	}
+
+	ev := new(payment.WebhookEventDTO)
+	if err := sonic.Unmarshal(payload, ev); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := ev.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	moved, err := uc.repo.ApplyEvent(ctx, provider, ev, payment.SourceStatuses(ev.Status))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Payment not found")
+			e.AddDetail(fmt.Sprintf("reference: charge %s not found", ev.Reference))
+			return e
+		}
+
+		uc.log.Errorf("failed to apply webhook event %s of charge %s: %v", ev.ID, ev.Reference, err)
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if !moved {
+		uc.log.Infof("webhook event %s of charge %s is ignored, it is either redelivered or outdated", ev.ID, ev.Reference)
+	}
+
+	return nil
+}
+
+func (uc *Usecase) getOne(ctx context.Context, id uuid.UUID) (*payment.PaymentDTO, error) {
+	p, err := uc.repo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Payment not found")
+			e.AddDetail(fmt.Sprintf("data: payment with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return p, nil
+}
+
+// reconcile queries latest charge of a payment on provider and moves the payment along with it,
+// in case its webhook has not arrived yet. Charge is returned even if the payment could not be moved
+func (uc *Usecase) reconcile(ctx context.Context, p *payment.PaymentDTO) (*paymentprovider.Charge, error) {
+	charge, err := uc.provider.GetCharge(ctx, *p.ProviderRef)
+	if err != nil {
+		uc.log.Warnf("failed to query charge %s of payment %s: %v", *p.ProviderRef, p.ID, err)
+		return nil, err
+	}
+
+	if charge.Status == p.Status {
+		return charge, nil
+	}
+
+	if _, err := uc.repo.UpdateStatus(ctx, charge.Reference, payment.SourceStatuses(charge.Status), charge.Status, charge.FailureReason); err != nil {
+		uc.log.Warnf("failed to synchronize charge %s of payment %s: %v", charge.Reference, p.ID, err)
+	}
+
+	return charge, nil
+}
+
+func (uc *Usecase) currency() string {
+	if uc.conf.Payment.Currency == "" {
+		return "IDR"
+	}
+	return uc.conf.Payment.Currency
+}
+
+func (uc *Usecase) maxAttempts() int {
+	if uc.conf.Payment.MaxAttempts <= 0 {
+		return 3
+	}
+	return uc.conf.Payment.MaxAttempts
+}
@@ -0,0 +1,115 @@
+package paymentweb
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/payment"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// signatureHeader holds hex encoded HMAC-SHA256 of raw webhook payload
+const signatureHeader = "X-Payment-Signature"
+
+type iUsecase interface {
+	Create(ctx context.Context, np *payment.NewPaymentDTO, claims *tokenutil.AccessTokenClaims) (*payment.PaymentDTO, error)
+	GetOne(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*payment.PaymentDTO, error)
+	Refund(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*payment.PaymentDTO, error)
+	HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) error
+}
+
+type controller struct {
+	paymentUC iUsecase
+	log       *logger.Log
+}
+
+func newController(paymentUC iUsecase, log *logger.Log) *controller {
+	return &controller{paymentUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	dto := new(payment.NewPaymentDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.paymentUC.Create(c.Request().Context(), dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, p)
+}
+
+func (con *controller) getOne(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Payment id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.paymentUC.GetOne(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
+
+func (con *controller) refund(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Payment id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.paymentUC.Refund(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
+
+// webhook keeps payload as raw bytes, since signature is computed over the exact bytes which provider has sent
+func (con *controller) webhook(c echo.Context) error {
+	payload, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	signature := c.Request().Header.Get(signatureHeader)
+
+	if err := con.paymentUC.HandleWebhook(c.Request().Context(), c.Param("provider"), payload, signature); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
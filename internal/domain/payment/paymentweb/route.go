@@ -0,0 +1,24 @@
+package paymentweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log       *logger.Log
+	PaymentUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.PaymentUC, opts.Log)
+
+	// owner of an order pays it, access to each payment is scoped on usecase
+	g := web.Echo.Group("/api/v1/payment", web.Mid.Authenticated)
+	g.POST("", con.create)
+	g.GET("/:id", con.getOne)
+	g.POST("/:id/refund", con.refund)
+
+	// provider calls webhook without any account, every payload is verified by its signature instead
+	web.Echo.POST("/api/v1/webhook/payment/:provider", con.webhook)
+}
@@ -0,0 +1,204 @@
+package promotion
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/sdk/validate"
+)
+
+// kinds and scopes which are available on promotions table, see their enums on migration
+const (
+	KindPercentage = "percentage"
+	KindFixed      = "fixed"
+
+	ScopeOutlet = "outlet"
+	ScopeMenu   = "menu"
+)
+
+var Kinds = []string{KindPercentage, KindFixed}
+
+var codePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// PromotionDTO is what we send to client
+type PromotionDTO struct {
+	ID              uuid.UUID  `json:"id"`
+	OutletID        uuid.UUID  `json:"outlet_id"`
+	MenuID          *uuid.UUID `json:"menu_id"` // only set on menu scope
+	Code            *string    `json:"code"`    // nil whenever promotion applies without code
+	Name            string     `json:"name"`
+	Kind            string     `json:"kind"`
+	Scope           string     `json:"scope"`
+	Value           float64    `json:"value"`
+	MinSpend        float64    `json:"min_spend"`
+	StartsAt        time.Time  `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	UsageLimit      *int       `json:"usage_limit"`
+	PerAccountLimit *int       `json:"per_account_limit"`
+	UsedCount       int        `json:"used_count"`
+	IsActive        bool       `json:"is_active"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// IsRunning tells whether promotion is active and within its validity window at given time
+func (p *PromotionDTO) IsRunning(at time.Time) bool {
+	return p.IsActive && !p.StartsAt.After(at) && (p.EndsAt == nil || p.EndsAt.After(at))
+}
+
+// RedemptionDTO is a record of a promotion which has been applied on an order of an account
+type RedemptionDTO struct {
+	ID          uuid.UUID `json:"id"`
+	PromotionID uuid.UUID `json:"promotion_id"`
+	AccountID   uuid.UUID `json:"account_id"`
+	OrderID     uuid.UUID `json:"order_id"`
+	Subtotal    float64   `json:"subtotal"`
+	Discount    float64   `json:"discount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewPromotionDTO is what client should send to create new promotion, menu_id is required on menu scope
+type NewPromotionDTO struct {
+	OutletID        string  `json:"outlet_id"`
+	MenuID          string  `json:"menu_id"`
+	Code            string  `json:"code"`
+	Name            string  `json:"name"`
+	Kind            string  `json:"kind"`
+	Scope           string  `json:"scope"`
+	Value           float64 `json:"value"`
+	MinSpend        float64 `json:"min_spend"`
+	StartsAt        string  `json:"starts_at"`
+	EndsAt          string  `json:"ends_at"`
+	UsageLimit      *int    `json:"usage_limit"`
+	PerAccountLimit *int    `json:"per_account_limit"`
+	IsActive        bool    `json:"is_active"`
+}
+
+func (d NewPromotionDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.OutletID, validation.Required, is.UUID),
+		validation.Field(&d.MenuID,
+			validation.When(d.Scope == ScopeMenu, validation.Required, is.UUID).
+				Else(validation.Empty.Error("must be blank unless scope is menu"))),
+		validation.Field(&d.Code, validation.Length(3, 30), validation.Match(codePattern)),
+		validation.Field(&d.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&d.Kind, validation.Required, validation.In(KindPercentage, KindFixed)),
+		validation.Field(&d.Scope, validation.Required, validation.In(ScopeOutlet, ScopeMenu)),
+		validation.Field(&d.Value, validation.Required, validation.Min(0.01), validation.When(d.Kind == KindPercentage, validation.Max(100.0))),
+		validation.Field(&d.MinSpend, validation.Min(0.0)),
+		validation.Field(&d.StartsAt, validation.Required, validate.Timestamp),
+		validation.Field(&d.EndsAt, validate.Timestamp, validation.By(endsAfter(d.StartsAt))),
+		validation.Field(&d.UsageLimit, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&d.PerAccountLimit, validation.NilOrNotEmpty, validation.Min(1)),
+	)
+}
+
+// UpdatePromotionDTO is what client should send to change a promotion. Outlet, scope, kind and code are kept as is,
+// since redemptions which have been recorded refer to them
+type UpdatePromotionDTO struct {
+	Name            string  `json:"name"`
+	Value           float64 `json:"value"`
+	MinSpend        float64 `json:"min_spend"`
+	StartsAt        string  `json:"starts_at"`
+	EndsAt          string  `json:"ends_at"`
+	UsageLimit      *int    `json:"usage_limit"`
+	PerAccountLimit *int    `json:"per_account_limit"`
+	IsActive        bool    `json:"is_active"`
+}
+
+func (d UpdatePromotionDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Name, validation.Required, validation.Length(1, 50)),
+		validation.Field(&d.Value, validation.Required, validation.Min(0.01)),
+		validation.Field(&d.MinSpend, validation.Min(0.0)),
+		validation.Field(&d.StartsAt, validation.Required, validate.Timestamp),
+		validation.Field(&d.EndsAt, validate.Timestamp, validation.By(endsAfter(d.StartsAt))),
+		validation.Field(&d.UsageLimit, validation.NilOrNotEmpty, validation.Min(1)),
+		validation.Field(&d.PerAccountLimit, validation.NilOrNotEmpty, validation.Min(1)),
+	)
+}
+
+func endsAfter(startsAt string) validation.RuleFunc {
+	return func(value interface{}) error {
+		endsAt, _ := value.(string)
+		if endsAt == "" {
+			return nil
+		}
+
+		start, err := time.Parse(time.RFC3339, startsAt)
+		if err != nil {
+			return nil
+		}
+
+		end, err := time.Parse(time.RFC3339, endsAt)
+		if err != nil {
+			return nil
+		}
+
+		if !end.After(start) {
+			return errors.New("must be after starts_at")
+		}
+		return nil
+	}
+}
+
+// EvaluateDTO is what client should send to find the best promotion of a basket, prices are never taken from client.
+// Promotions without code are always considered, while a promotion with code is only considered once its code is given
+type EvaluateDTO struct {
+	OutletID string            `json:"outlet_id"`
+	Code     string            `json:"code"`
+	Items    []EvaluateItemDTO `json:"items"`
+}
+
+func (d EvaluateDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.OutletID, validation.Required, is.UUID),
+		validation.Field(&d.Code, validation.Length(3, 30), validation.Match(codePattern)),
+		validation.Field(&d.Items, validation.Required, validation.Length(1, 50)),
+	)
+}
+
+type EvaluateItemDTO struct {
+	MenuID   string `json:"menu_id"`
+	Quantity int    `json:"quantity"`
+}
+
+func (d EvaluateItemDTO) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.MenuID, validation.Required, is.UUID),
+		validation.Field(&d.Quantity, validation.Required, validation.Min(1), validation.Max(99)),
+	)
+}
+
+// QuoteDTO is a basket priced from current price of its menus, along with the best promotion which applies on it.
+// Nothing is redeemed by a quote, promotion is only redeemed once an order is placed
+type QuoteDTO struct {
+	OutletID  uuid.UUID      `json:"outlet_id"`
+	Items     []QuoteItemDTO `json:"items"`
+	Subtotal  float64        `json:"subtotal"`
+	Discount  float64        `json:"discount"`
+	Total     float64        `json:"total"`
+	Promotion *AppliedDTO    `json:"promotion"` // nil whenever no promotion applies
+}
+
+type QuoteItemDTO struct {
+	MenuID   uuid.UUID `json:"menu_id"`
+	Name     string    `json:"name"`
+	Price    float64   `json:"price"`
+	Quantity int       `json:"quantity"`
+	Total    float64   `json:"total"`
+}
+
+// AppliedDTO is a summary of the promotion which applies on a quote
+type AppliedDTO struct {
+	ID    uuid.UUID `json:"id"`
+	Code  *string   `json:"code"`
+	Name  string    `json:"name"`
+	Kind  string    `json:"kind"`
+	Scope string    `json:"scope"`
+	Value float64   `json:"value"`
+}
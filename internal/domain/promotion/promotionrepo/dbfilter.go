@@ -0,0 +1,30 @@
+package promotionrepo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goplateframework/internal/domain/promotion/promotionweb"
+)
+
+func (dbrepo *repository) buildFilter(args map[string]any, qp *promotionweb.QueryParams) string {
+	args["outlet_id"] = qp.Filter.OutletID
+	filters := []string{" outlet_id = :outlet_id"}
+
+	if qp.Filter.Kind != "" {
+		args["kind"] = qp.Filter.Kind
+		filters = append(filters, " kind = :kind")
+	}
+
+	if qp.Filter.Code != "" {
+		args["code"] = qp.Filter.Code
+		filters = append(filters, " upper(code) = upper(:code)")
+	}
+
+	if qp.Filter.IsActive != nil {
+		args["is_active"] = *qp.Filter.IsActive
+		filters = append(filters, " is_active = :is_active")
+	}
+
+	return fmt.Sprintf(" WHERE %s", strings.Join(filters, " AND "))
+}
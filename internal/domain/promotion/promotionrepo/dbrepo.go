@@ -0,0 +1,302 @@
+package promotionrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/promotion"
+	"github.com/goplateframework/internal/domain/promotion/promotionweb"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrNotRedeemable is returned whenever a promotion has been deactivated or its validity window has passed
+	ErrNotRedeemable = errors.New("promotion is not redeemable")
+
+	// ErrUsageLimitReached is returned whenever a promotion has been used up, either by everyone or by the account
+	ErrUsageLimitReached = errors.New("promotion usage limit reached")
+)
+
+type repository struct {
+	*sqlx.DB
+}
+
+func NewDB(db *sqlx.DB) *repository {
+	return &repository{db}
+}
+
+func (dbrepo *repository) Create(ctx context.Context, p *promotion.PromotionDTO) error {
+	q := `
+	INSERT INTO promotions
+		(id, outlet_id, menu_id, code, name, kind, scope, value, min_spend, starts_at, ends_at,
+		usage_limit, per_account_limit, used_count, is_active, created_at, updated_at)
+	VALUES
+		(:id, :outlet_id, :menu_id, :code, :name, :kind, :scope, :value, :min_spend, :starts_at, :ends_at,
+		:usage_limit, :per_account_limit, :used_count, :is_active, :created_at, :updated_at)`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(p))
+	return err
+}
+
+func (dbrepo *repository) GetOne(ctx context.Context, id uuid.UUID) (*promotion.PromotionDTO, error) {
+	m := new(Model)
+
+	q := `
+	SELECT * FROM promotions
+	WHERE id = $1
+	LIMIT 1`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, id).StructScan(m); err != nil {
+		return nil, err
+	}
+
+	return m.intoDTO(), nil
+}
+
+// CodeExists tells whether an outlet already has a promotion of given code, codes are case insensitive
+func (dbrepo *repository) CodeExists(ctx context.Context, outletID uuid.UUID, code string) (bool, error) {
+	var exists bool
+
+	q := `SELECT EXISTS (SELECT 1 FROM promotions WHERE outlet_id = $1 AND upper(code) = upper($2))`
+
+	if err := dbrepo.QueryRowxContext(ctx, q, outletID, code).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// Count returns total of promotions which match filters of given query params
+func (dbrepo *repository) Count(ctx context.Context, qp *promotionweb.QueryParams) (int, error) {
+	args := map[string]any{}
+	q := "SELECT COUNT(*) AS total FROM promotions" + dbrepo.buildFilter(args, qp)
+
+	stmt, err := dbrepo.PrepareNamedContext(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var count struct {
+		Total int `db:"total"`
+	}
+
+	if err := stmt.GetContext(ctx, &count, args); err != nil {
+		return 0, err
+	}
+
+	return count.Total, nil
+}
+
+func (dbrepo *repository) GetAll(ctx context.Context, qp *promotionweb.QueryParams) ([]promotion.PromotionDTO, error) {
+	args := map[string]any{
+		"size":   qp.Page.Size,
+		"offset": qp.Page.Offset,
+	}
+
+	var qb strings.Builder
+	qb.WriteString(`
+		SELECT * FROM promotions
+	`)
+
+	qb.WriteString(dbrepo.buildFilter(args, qp))
+	qb.WriteString(fmt.Sprintf(" ORDER BY %s %s", qp.OrderBy.Field, qp.OrderBy.Direction))
+	qb.WriteString(" OFFSET :offset LIMIT :size")
+
+	rows, err := dbrepo.NamedQueryContext(ctx, qb.String(), args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []promotion.PromotionDTO
+	for rows.Next() {
+		p := new(Model)
+		if err := rows.StructScan(p); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p.intoDTO())
+	}
+
+	return promotions, nil
+}
+
+// Update changes details of a promotion, outlet, scope, kind and code are left as is
+func (dbrepo *repository) Update(ctx context.Context, p *promotion.PromotionDTO) error {
+	q := `
+	UPDATE
+		promotions
+	SET
+		name = :name,
+		value = :value,
+		min_spend = :min_spend,
+		starts_at = :starts_at,
+		ends_at = :ends_at,
+		usage_limit = :usage_limit,
+		per_account_limit = :per_account_limit,
+		is_active = :is_active,
+		updated_at = :updated_at
+	WHERE id = :id`
+
+	_, err := dbrepo.NamedExecContext(ctx, q, intoModel(p))
+	return err
+}
+
+// Delete removes a promotion which has never been redeemed, sql.ErrNoRows is returned otherwise
+func (dbrepo *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := dbrepo.ExecContext(ctx, `DELETE FROM promotions WHERE id = $1 AND used_count = 0`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetRunning returns promotions of an outlet which are redeemable at given time, those which have a code
+// are only returned whenever their code is given
+func (dbrepo *repository) GetRunning(ctx context.Context, outletID uuid.UUID, code string, at time.Time) ([]promotion.PromotionDTO, error) {
+	q := `
+	SELECT * FROM promotions
+	WHERE
+		outlet_id = $1
+		AND is_active
+		AND starts_at <= $3
+		AND (ends_at IS NULL OR ends_at > $3)
+		AND (usage_limit IS NULL OR used_count < usage_limit)
+		AND (code IS NULL OR upper(code) = upper($2))`
+
+	rows, err := dbrepo.QueryxContext(ctx, q, outletID, code, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []promotion.PromotionDTO
+	for rows.Next() {
+		p := new(Model)
+		if err := rows.StructScan(p); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p.intoDTO())
+	}
+
+	return promotions, nil
+}
+
+// CountRedemptions returns how many times an account has redeemed each of given promotions
+func (dbrepo *repository) CountRedemptions(ctx context.Context, accountID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	q, args, err := sqlx.In(`
+	SELECT promotion_id, COUNT(*) FROM promotion_redemptions
+	WHERE account_id = ? AND promotion_id IN (?)
+	GROUP BY promotion_id`, accountID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbrepo.QueryxContext(ctx, dbrepo.Rebind(q), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+
+	return counts, nil
+}
+
+// Redeem records a redemption within transaction of the order it discounts, while its promotion is locked,
+// so usage limits hold under concurrent orders and a redemption never exists without its order.
+// ErrNotRedeemable or ErrUsageLimitReached is returned whenever the promotion could no longer be redeemed
+func Redeem(ctx context.Context, tx *sqlx.Tx, r *promotion.RedemptionDTO) error {
+	m := new(Model)
+
+	q := `SELECT * FROM promotions WHERE id = $1 FOR UPDATE`
+
+	if err := tx.QueryRowxContext(ctx, q, r.PromotionID).StructScan(m); err != nil {
+		return err
+	}
+
+	p := m.intoDTO()
+	if !p.IsRunning(r.CreatedAt) {
+		return ErrNotRedeemable
+	}
+
+	if p.UsageLimit != nil && p.UsedCount >= *p.UsageLimit {
+		return ErrUsageLimitReached
+	}
+
+	if p.PerAccountLimit != nil {
+		var used int
+
+		q = `SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = $1 AND account_id = $2`
+
+		if err := tx.QueryRowxContext(ctx, q, r.PromotionID, r.AccountID).Scan(&used); err != nil {
+			return err
+		}
+
+		if used >= *p.PerAccountLimit {
+			return ErrUsageLimitReached
+		}
+	}
+
+	q = `UPDATE promotions SET used_count = used_count + 1 WHERE id = $1`
+
+	if _, err := tx.ExecContext(ctx, q, r.PromotionID); err != nil {
+		return err
+	}
+
+	q = `
+	INSERT INTO promotion_redemptions
+		(id, promotion_id, account_id, order_id, subtotal, discount, created_at)
+	VALUES
+		(:id, :promotion_id, :account_id, :order_id, :subtotal, :discount, :created_at)`
+
+	_, err := tx.NamedExecContext(ctx, q, intoRedemptionModel(r))
+	return err
+}
+
+// Release gives redemption of an order back to its promotion within transaction of the order, nothing happens
+// whenever the order has redeemed none
+func Release(ctx context.Context, tx *sqlx.Tx, orderID uuid.UUID) error {
+	var promotionID uuid.UUID
+
+	q := `DELETE FROM promotion_redemptions WHERE order_id = $1 RETURNING promotion_id`
+
+	if err := tx.QueryRowxContext(ctx, q, orderID).Scan(&promotionID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	q = `UPDATE promotions SET used_count = used_count - 1 WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, q, promotionID)
+	return err
+}
@@ -0,0 +1,94 @@
+package promotionrepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/promotion"
+)
+
+type Model struct {
+	ID              uuid.UUID  `db:"id"`
+	OutletID        uuid.UUID  `db:"outlet_id"`
+	MenuID          *uuid.UUID `db:"menu_id"`
+	Code            *string    `db:"code"`
+	Name            string     `db:"name"`
+	Kind            string     `db:"kind"`
+	Scope           string     `db:"scope"`
+	Value           float64    `db:"value"`
+	MinSpend        float64    `db:"min_spend"`
+	StartsAt        time.Time  `db:"starts_at"`
+	EndsAt          *time.Time `db:"ends_at"`
+	UsageLimit      *int       `db:"usage_limit"`
+	PerAccountLimit *int       `db:"per_account_limit"`
+	UsedCount       int        `db:"used_count"`
+	IsActive        bool       `db:"is_active"`
+	CreatedAt       time.Time  `db:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at"`
+}
+
+func (m *Model) intoDTO() *promotion.PromotionDTO {
+	return &promotion.PromotionDTO{
+		ID:              m.ID,
+		OutletID:        m.OutletID,
+		MenuID:          m.MenuID,
+		Code:            m.Code,
+		Name:            m.Name,
+		Kind:            m.Kind,
+		Scope:           m.Scope,
+		Value:           m.Value,
+		MinSpend:        m.MinSpend,
+		StartsAt:        m.StartsAt,
+		EndsAt:          m.EndsAt,
+		UsageLimit:      m.UsageLimit,
+		PerAccountLimit: m.PerAccountLimit,
+		UsedCount:       m.UsedCount,
+		IsActive:        m.IsActive,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+func intoModel(p *promotion.PromotionDTO) *Model {
+	return &Model{
+		ID:              p.ID,
+		OutletID:        p.OutletID,
+		MenuID:          p.MenuID,
+		Code:            p.Code,
+		Name:            p.Name,
+		Kind:            p.Kind,
+		Scope:           p.Scope,
+		Value:           p.Value,
+		MinSpend:        p.MinSpend,
+		StartsAt:        p.StartsAt,
+		EndsAt:          p.EndsAt,
+		UsageLimit:      p.UsageLimit,
+		PerAccountLimit: p.PerAccountLimit,
+		UsedCount:       p.UsedCount,
+		IsActive:        p.IsActive,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+type RedemptionModel struct {
+	ID          uuid.UUID `db:"id"`
+	PromotionID uuid.UUID `db:"promotion_id"`
+	AccountID   uuid.UUID `db:"account_id"`
+	OrderID     uuid.UUID `db:"order_id"`
+	Subtotal    float64   `db:"subtotal"`
+	Discount    float64   `db:"discount"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func intoRedemptionModel(r *promotion.RedemptionDTO) *RedemptionModel {
+	return &RedemptionModel{
+		ID:          r.ID,
+		PromotionID: r.PromotionID,
+		AccountID:   r.AccountID,
+		OrderID:     r.OrderID,
+		Subtotal:    r.Subtotal,
+		Discount:    r.Discount,
+		CreatedAt:   r.CreatedAt,
+	}
+}
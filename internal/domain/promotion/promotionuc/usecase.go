@@ -0,0 +1,410 @@
+package promotionuc
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/menu"
+	"github.com/goplateframework/internal/domain/order"
+	"github.com/goplateframework/internal/domain/outletstaff"
+	"github.com/goplateframework/internal/domain/promotion"
+	"github.com/goplateframework/internal/domain/promotion/promotionweb"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/pkg/logger"
+)
+
+// required iRepository methods which this usecase needs to store or retrieve data
+type iRepository interface {
+	Create(ctx context.Context, p *promotion.PromotionDTO) error
+	GetOne(ctx context.Context, id uuid.UUID) (*promotion.PromotionDTO, error)
+	CodeExists(ctx context.Context, outletID uuid.UUID, code string) (bool, error)
+	Count(ctx context.Context, qp *promotionweb.QueryParams) (int, error)
+	GetAll(ctx context.Context, qp *promotionweb.QueryParams) ([]promotion.PromotionDTO, error)
+	Update(ctx context.Context, p *promotion.PromotionDTO) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetRunning(ctx context.Context, outletID uuid.UUID, code string, at time.Time) ([]promotion.PromotionDTO, error)
+	CountRedemptions(ctx context.Context, accountID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]int, error)
+}
+
+// required menu repository methods to price baskets from current price of menus
+type iMenuRepository interface {
+	GetMany(ctx context.Context, ids []uuid.UUID) ([]menu.MenuDTO, error)
+}
+
+// required staff usecase methods to scope promotion management into staff of its outlet
+type iStaffUsecase interface {
+	Authorize(ctx context.Context, claims *tokenutil.AccessTokenClaims, outletID uuid.UUID, staffRoles ...string) error
+}
+
+type Usecase struct {
+	conf     *config.Config
+	log      *logger.Log
+	repo     iRepository
+	menuRepo iMenuRepository
+	staffUC  iStaffUsecase
+}
+
+func New(conf *config.Config, log *logger.Log, repo iRepository, menuRepo iMenuRepository, staffUC iStaffUsecase) *Usecase {
+	return &Usecase{
+		conf:     conf,
+		log:      log,
+		repo:     repo,
+		menuRepo: menuRepo,
+		staffUC:  staffUC,
+	}
+}
+
+func (uc *Usecase) Create(ctx context.Context, np *promotion.NewPromotionDTO, claims *tokenutil.AccessTokenClaims) (*promotion.PromotionDTO, error) {
+	outletID := uuid.MustParse(np.OutletID)
+
+	if err := uc.staffUC.Authorize(ctx, claims, outletID, outletstaff.RoleManager); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	p := &promotion.PromotionDTO{
+		ID:              uuid.New(),
+		OutletID:        outletID,
+		Name:            np.Name,
+		Kind:            np.Kind,
+		Scope:           np.Scope,
+		Value:           np.Value,
+		MinSpend:        np.MinSpend,
+		StartsAt:        parseTime(np.StartsAt),
+		UsageLimit:      np.UsageLimit,
+		PerAccountLimit: np.PerAccountLimit,
+		IsActive:        np.IsActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if np.EndsAt != "" {
+		endsAt := parseTime(np.EndsAt)
+		p.EndsAt = &endsAt
+	}
+
+	if np.Scope == promotion.ScopeMenu {
+		menuID := uuid.MustParse(np.MenuID)
+
+		menus, err := uc.menuRepo.GetMany(ctx, []uuid.UUID{menuID})
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		if len(menus) == 0 || menus[0].OutletID != outletID.String() {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("menu_id: menu %s not found on outlet %s", menuID, outletID))
+			return nil, e
+		}
+		p.MenuID = &menuID
+	}
+
+	if np.Code != "" {
+		code := strings.ToUpper(np.Code)
+
+		exists, err := uc.repo.CodeExists(ctx, outletID, code)
+		if err != nil {
+			return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		if exists {
+			e := errshttp.New(errshttp.AlreadyExists, "Promotion code already exists")
+			e.AddDetail(fmt.Sprintf("code: %s is already used by another promotion of the outlet", code))
+			return nil, e
+		}
+		p.Code = &code
+	}
+
+	if err := uc.repo.Create(ctx, p); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return p, nil
+}
+
+// GetAll lists promotions of an outlet to its staff
+func (uc *Usecase) GetAll(ctx context.Context, qp *promotionweb.QueryParams, claims *tokenutil.AccessTokenClaims) (*result.Result[promotion.PromotionDTO], error) {
+	if err := uc.staffUC.Authorize(ctx, claims, qp.Filter.OutletID); err != nil {
+		return nil, err
+	}
+
+	total, err := uc.repo.Count(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if total > 0 && !qp.Page.CanPaginate(total) {
+		e := errshttp.New(errshttp.InvalidArgument, "Page requested is out of range")
+		e.AddDetail(fmt.Sprintf("pagination: page number must be between 1 and %d", total))
+		return nil, e
+	}
+
+	p, err := uc.repo.GetAll(ctx, qp)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return result.New(p, total, qp.Page.Number, qp.Page.Size), nil
+}
+
+func (uc *Usecase) GetOne(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*promotion.PromotionDTO, error) {
+	return uc.authorizedPromotion(ctx, id, claims)
+}
+
+func (uc *Usecase) Update(ctx context.Context, id uuid.UUID, up *promotion.UpdatePromotionDTO, claims *tokenutil.AccessTokenClaims) (*promotion.PromotionDTO, error) {
+	p, err := uc.authorizedPromotion(ctx, id, claims, outletstaff.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Kind == promotion.KindPercentage && up.Value > 100 {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+		e.AddDetail("value: must be no greater than 100")
+		return nil, e
+	}
+
+	p.Name = up.Name
+	p.Value = up.Value
+	p.MinSpend = up.MinSpend
+	p.StartsAt = parseTime(up.StartsAt)
+	p.EndsAt = nil
+	p.UsageLimit = up.UsageLimit
+	p.PerAccountLimit = up.PerAccountLimit
+	p.IsActive = up.IsActive
+	p.UpdatedAt = time.Now()
+
+	if up.EndsAt != "" {
+		endsAt := parseTime(up.EndsAt)
+		p.EndsAt = &endsAt
+	}
+
+	if p.UsageLimit != nil && *p.UsageLimit < p.UsedCount {
+		e := errshttp.New(errshttp.FailedPrecondition, "Usage limit is below usage of the promotion")
+		e.AddDetail(fmt.Sprintf("usage_limit: promotion has been used %d times", p.UsedCount))
+		return nil, e
+	}
+
+	if err := uc.repo.Update(ctx, p); err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return p, nil
+}
+
+func (uc *Usecase) Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error {
+	p, err := uc.authorizedPromotion(ctx, id, claims, outletstaff.RoleManager)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, p.ID); err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.FailedPrecondition, "Redeemed promotion could not be deleted")
+			e.AddDetail("data: promotion has been redeemed, deactivate it instead")
+			return e
+		}
+
+		return errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	return nil
+}
+
+// Evaluate prices given items from current price of their menus and applies the best promotion on them,
+// nothing is recorded so it could be called as often as the basket changes
+func (uc *Usecase) Evaluate(ctx context.Context, ev *promotion.EvaluateDTO, claims *tokenutil.AccessTokenClaims) (*promotion.QuoteDTO, error) {
+	quote, err := uc.price(ctx, ev)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.Apply(ctx, quote, ev.Code, claims.AccountID); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// Apply picks the promotion which gives the biggest discount on a priced quote and takes it off the quote,
+// nil whenever none applies. It is used by orders, which price their lines along with topings on their own
+func (uc *Usecase) Apply(ctx context.Context, quote *promotion.QuoteDTO, code string, accountID uuid.UUID) (*promotion.PromotionDTO, error) {
+	running, err := uc.repo.GetRunning(ctx, quote.OutletID, code, time.Now())
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if code != "" && !hasCode(running, code) {
+		e := errshttp.New(errshttp.NotFound, "Promotion code is not valid")
+		e.AddDetail(fmt.Sprintf("code: %s is unknown, expired or used up", code))
+		return nil, e
+	}
+
+	ids := make([]uuid.UUID, 0, len(running))
+	for _, p := range running {
+		ids = append(ids, p.ID)
+	}
+
+	used, err := uc.repo.CountRedemptions(ctx, accountID, ids)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	var best *promotion.PromotionDTO
+	for i := range running {
+		p := &running[i]
+
+		if p.PerAccountLimit != nil && used[p.ID] >= *p.PerAccountLimit {
+			continue
+		}
+
+		if d := discount(p, quote); d > quote.Discount {
+			best = p
+			quote.Discount = d
+		}
+	}
+
+	quote.Total = order.RoundPrice(quote.Subtotal - quote.Discount)
+
+	if best != nil {
+		quote.Promotion = &promotion.AppliedDTO{
+			ID:    best.ID,
+			Code:  best.Code,
+			Name:  best.Name,
+			Kind:  best.Kind,
+			Scope: best.Scope,
+			Value: best.Value,
+		}
+	}
+
+	return best, nil
+}
+
+// price builds a quote of given items from current price of their menus, which should all be available on the outlet
+func (uc *Usecase) price(ctx context.Context, ev *promotion.EvaluateDTO) (*promotion.QuoteDTO, error) {
+	outletID := uuid.MustParse(ev.OutletID)
+
+	ids := make([]uuid.UUID, 0, len(ev.Items))
+	for _, i := range ev.Items {
+		ids = append(ids, uuid.MustParse(i.MenuID))
+	}
+
+	menus, err := uc.menuRepo.GetMany(ctx, ids)
+	if err != nil {
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	byID := make(map[uuid.UUID]menu.MenuDTO, len(menus))
+	for _, m := range menus {
+		byID[m.ID] = m
+	}
+
+	quote := &promotion.QuoteDTO{
+		OutletID: outletID,
+		Items:    []promotion.QuoteItemDTO{},
+	}
+
+	for _, i := range ev.Items {
+		menuID := uuid.MustParse(i.MenuID)
+
+		m, ok := byID[menuID]
+		if !ok {
+			e := errshttp.New(errshttp.NotFound, "Menu not found")
+			e.AddDetail(fmt.Sprintf("items: menu %s not found", menuID))
+			return nil, e
+		}
+
+		if m.OutletID != outletID.String() {
+			e := errshttp.New(errshttp.InvalidArgument, "Menu does not belong to the outlet")
+			e.AddDetail(fmt.Sprintf("items: menu %s does not belong to outlet %s", menuID, outletID))
+			return nil, e
+		}
+
+		if !m.IsAvailable {
+			e := errshttp.New(errshttp.FailedPrecondition, "Menu is not available")
+			e.AddDetail(fmt.Sprintf("items: menu %s is not available", menuID))
+			return nil, e
+		}
+
+		item := promotion.QuoteItemDTO{
+			MenuID:   m.ID,
+			Name:     m.Name,
+			Price:    m.Price,
+			Quantity: i.Quantity,
+			Total:    order.RoundPrice(m.Price * float64(i.Quantity)),
+		}
+
+		quote.Subtotal = order.RoundPrice(quote.Subtotal + item.Total)
+		quote.Items = append(quote.Items, item)
+	}
+
+	quote.Total = quote.Subtotal
+	return quote, nil
+}
+
+// discount calculates how much a promotion takes off a quote, zero whenever minimum spend is not met
+// or none of the items is within its scope. Discount never exceeds what the promotion applies on
+func discount(p *promotion.PromotionDTO, quote *promotion.QuoteDTO) float64 {
+	if quote.Subtotal < p.MinSpend {
+		return 0
+	}
+
+	base := quote.Subtotal
+	if p.Scope == promotion.ScopeMenu {
+		base = 0
+		for _, i := range quote.Items {
+			if p.MenuID != nil && i.MenuID == *p.MenuID {
+				base += i.Total
+			}
+		}
+	}
+
+	switch p.Kind {
+	case promotion.KindPercentage:
+		return order.RoundPrice(base * p.Value / 100)
+	case promotion.KindFixed:
+		return order.RoundPrice(min(p.Value, base))
+	default:
+		return 0
+	}
+}
+
+func hasCode(promotions []promotion.PromotionDTO, code string) bool {
+	for _, p := range promotions {
+		if p.Code != nil && strings.EqualFold(*p.Code, code) {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *Usecase) authorizedPromotion(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims, staffRoles ...string) (*promotion.PromotionDTO, error) {
+	p, err := uc.repo.GetOne(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			e := errshttp.New(errshttp.NotFound, "Promotion not found")
+			e.AddDetail(fmt.Sprintf("data: promotion with id %s not found", id))
+			return nil, e
+		}
+
+		return nil, errshttp.New(errshttp.Internal, "Something went wrong")
+	}
+
+	if err := uc.staffUC.Authorize(ctx, claims, p.OutletID, staffRoles...); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// parseTime parses a timestamp which has been validated as RFC3339
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
@@ -0,0 +1,188 @@
+package promotionweb
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/promotion"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/sdk/validate"
+	"github.com/goplateframework/internal/web/result"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/goplateframework/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+type iUsecase interface {
+	Create(ctx context.Context, np *promotion.NewPromotionDTO, claims *tokenutil.AccessTokenClaims) (*promotion.PromotionDTO, error)
+	GetAll(ctx context.Context, qp *QueryParams, claims *tokenutil.AccessTokenClaims) (*result.Result[promotion.PromotionDTO], error)
+	GetOne(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) (*promotion.PromotionDTO, error)
+	Update(ctx context.Context, id uuid.UUID, up *promotion.UpdatePromotionDTO, claims *tokenutil.AccessTokenClaims) (*promotion.PromotionDTO, error)
+	Delete(ctx context.Context, id uuid.UUID, claims *tokenutil.AccessTokenClaims) error
+	Evaluate(ctx context.Context, ev *promotion.EvaluateDTO, claims *tokenutil.AccessTokenClaims) (*promotion.QuoteDTO, error)
+}
+
+type controller struct {
+	promotionUC iUsecase
+	log         *logger.Log
+}
+
+func newController(promotionUC iUsecase, log *logger.Log) *controller {
+	return &controller{promotionUC, log}
+}
+
+func (con *controller) create(c echo.Context) error {
+	dto := new(promotion.NewPromotionDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.promotionUC.Create(c.Request().Context(), dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, p)
+}
+
+func (con *controller) getAll(c echo.Context) error {
+	qp, err := getQueryParams(c).Parse()
+
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given query params are invalid")
+		e.AddDetail(err.Error())
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.promotionUC.GetAll(c.Request().Context(), qp, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
+
+func (con *controller) getOne(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Promotion id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.promotionUC.GetOne(c.Request().Context(), id, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
+
+func (con *controller) update(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Promotion id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	dto := new(promotion.UpdatePromotionDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	p, err := con.promotionUC.Update(c.Request().Context(), id, dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, p)
+}
+
+func (con *controller) delete(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Promotion id is invalid, should be valid UUID")
+		e.AddDetail("id: invalid")
+		return e
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	if err := con.promotionUC.Delete(c.Request().Context(), id, claims); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusNoContent, nil)
+}
+
+func (con *controller) evaluate(c echo.Context) error {
+	dto, err := bindEvaluate(c)
+	if err != nil {
+		return err
+	}
+
+	claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+	q, err := con.promotionUC.Evaluate(c.Request().Context(), dto, claims)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, q)
+}
+
+func bindEvaluate(c echo.Context) (*promotion.EvaluateDTO, error) {
+	dto := new(promotion.EvaluateDTO)
+
+	if err := c.Bind(dto); err != nil {
+		return nil, errshttp.New(errshttp.InvalidArgument, "Given JSON is invalid")
+	}
+
+	if err := dto.Validate(); err != nil {
+		e := errshttp.New(errshttp.InvalidArgument, "Given JSON is out of validation rules")
+
+		validationErrs := validate.SplitErrors(err)
+		for _, s := range validationErrs {
+			e.AddDetail(s)
+		}
+
+		return nil, e
+	}
+
+	return dto, nil
+}
@@ -0,0 +1,122 @@
+package promotionweb
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/goplateframework/internal/domain/promotion"
+	"github.com/goplateframework/internal/web/queryparams"
+	"github.com/labstack/echo/v4"
+)
+
+// Supported query params for this promotion web layer
+type UnparsedQueryParams struct {
+	page     string
+	size     string
+	orderBy  string
+	outletID string
+	kind     string
+	code     string
+	isActive string
+}
+
+func getQueryParams(c echo.Context) *UnparsedQueryParams {
+	return &UnparsedQueryParams{
+		page:     c.QueryParam("page"),
+		size:     c.QueryParam("size"),
+		orderBy:  c.QueryParam("order_by"),
+		outletID: c.QueryParam("outlet_id"),
+		kind:     c.QueryParam("kind"),
+		code:     c.QueryParam("code"),
+		isActive: c.QueryParam("is_active"),
+	}
+}
+
+// Populated query params to send to repository
+type QueryParams struct {
+	Page    *queryparams.Page
+	OrderBy *queryparams.OrderBy
+	Filter  struct {
+		OutletID uuid.UUID
+		Kind     string
+		Code     string
+		IsActive *bool
+	}
+}
+
+func (uqp *UnparsedQueryParams) Parse() (*QueryParams, error) {
+	qp := new(QueryParams)
+
+	if err := uqp.setPage(qp); err != nil {
+		return nil, err
+	}
+
+	if err := uqp.setOrderBy(qp); err != nil {
+		return nil, err
+	}
+
+	if err := uqp.setFilter(qp); err != nil {
+		return nil, err
+	}
+
+	return qp, nil
+}
+
+func (uqp *UnparsedQueryParams) setPage(qp *QueryParams) error {
+	page, err := queryparams.ParsePage(uqp.page, uqp.size)
+	if err != nil {
+		return err
+	}
+
+	qp.Page = page
+	return nil
+}
+
+var allowedOrderByFields = []string{"name", "starts_at", "used_count", "created_at"}
+
+func (uqp *UnparsedQueryParams) setOrderBy(qp *QueryParams) error {
+	defaultOrderBy := queryparams.NewOrderBy(
+		"created_at",
+		queryparams.DescOrder,
+	)
+
+	orderBy, err := queryparams.ParseOrderBy(allowedOrderByFields, uqp.orderBy, defaultOrderBy)
+	if err != nil {
+		return err
+	}
+
+	qp.OrderBy = orderBy
+	return nil
+}
+
+func (uqp *UnparsedQueryParams) setFilter(qp *QueryParams) error {
+	// promotions are only listed per outlet, to staff of the outlet
+	if uqp.outletID == "" {
+		return errors.New("filter: outlet_id cannot be empty")
+	}
+
+	id, err := uuid.Parse(uqp.outletID)
+	if err != nil {
+		return errors.New("outlet_id: should be valid UUID")
+	}
+	qp.Filter.OutletID = id
+
+	if uqp.kind != "" && !slices.Contains(promotion.Kinds, uqp.kind) {
+		return errors.New("kind: unknown promotion kind")
+	}
+	qp.Filter.Kind = uqp.kind
+
+	if uqp.isActive != "" {
+		isActive, err := strconv.ParseBool(uqp.isActive)
+		if err != nil {
+			return errors.New("is_active: should be either true or false")
+		}
+		qp.Filter.IsActive = &isActive
+	}
+
+	qp.Filter.Code = uqp.code
+
+	return nil
+}
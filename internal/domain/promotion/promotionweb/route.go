@@ -0,0 +1,25 @@
+package promotionweb
+
+import (
+	"github.com/goplateframework/internal/web"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Options struct {
+	Log         *logger.Log
+	PromotionUC iUsecase
+}
+
+func Route(web *web.Web, opts *Options) {
+	con := newController(opts.PromotionUC, opts.Log)
+
+	// promotions are managed by staff of their outlet, scoped on usecase, while every account is able to apply them
+	// by placing an order
+	g := web.Echo.Group("/api/v1/promotion", web.Mid.Authenticated)
+	g.POST("", con.create)
+	g.GET("", con.getAll)
+	g.GET("/:id", con.getOne)
+	g.PUT("/:id", con.update)
+	g.DELETE("/:id", con.delete)
+	g.POST("/evaluate", con.evaluate)
+}
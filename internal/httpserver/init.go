@@ -5,6 +5,9 @@ import (
 	"github.com/goplateframework/internal/web"
 	"github.com/goplateframework/internal/worker/pb"
 	"github.com/goplateframework/pkg/logger"
+	"github.com/goplateframework/pkg/mailer"
+	"github.com/goplateframework/pkg/oidcprovider"
+	"github.com/goplateframework/pkg/paymentprovider"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/redis/go-redis/v9"
@@ -16,6 +19,9 @@ type Options struct {
 	Log      *logger.Log
 	ServConf *config.Config
 	Worker   pb.WorkerClient
+	Mailer   mailer.Mailer
+	OIDC     *oidcprovider.Registry
+	Payment  paymentprovider.Provider
 }
 
 func Init(opts *Options) *echo.Echo {
@@ -24,6 +30,9 @@ func Init(opts *Options) *echo.Echo {
 	w.Echo.HideBanner = true
 	w.Echo.HidePort = true
 
+	// client ip is resolved before any middleware keys on it
+	w.EnableIPExtractor(opts.ServConf.Server.TrustedProxies)
+
 	// middleware setup
 	w.InitCustomMware(opts.ServConf, opts.Cache)
 	w.EnableCORSMware(opts.ServConf.Server.AllowedOrigins)
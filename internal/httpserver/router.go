@@ -7,63 +7,157 @@ import (
 	"github.com/goplateframework/internal/domain/address/addressrepo"
 	"github.com/goplateframework/internal/domain/address/addressuc"
 	"github.com/goplateframework/internal/domain/address/addressweb"
+	"github.com/goplateframework/internal/domain/apikey/apikeyrepo"
+	"github.com/goplateframework/internal/domain/apikey/apikeyuc"
+	"github.com/goplateframework/internal/domain/apikey/apikeyweb"
 	"github.com/goplateframework/internal/domain/auth/authrepo"
 	"github.com/goplateframework/internal/domain/auth/authuc"
 	"github.com/goplateframework/internal/domain/auth/authweb"
+	"github.com/goplateframework/internal/domain/cart/cartrepo"
+	"github.com/goplateframework/internal/domain/cart/cartuc"
+	"github.com/goplateframework/internal/domain/cart/cartweb"
+	"github.com/goplateframework/internal/domain/catalog/catalogrepo"
+	"github.com/goplateframework/internal/domain/catalog/cataloguc"
+	"github.com/goplateframework/internal/domain/catalog/catalogweb"
 	"github.com/goplateframework/internal/domain/menu/menurepo"
 	"github.com/goplateframework/internal/domain/menu/menuuc"
 	"github.com/goplateframework/internal/domain/menu/menuweb"
+	"github.com/goplateframework/internal/domain/menucategory/menucategoryrepo"
+	"github.com/goplateframework/internal/domain/menucategory/menucategoryuc"
+	"github.com/goplateframework/internal/domain/menucategory/menucategoryweb"
 	"github.com/goplateframework/internal/domain/menutoping/menutopingrepo"
 	"github.com/goplateframework/internal/domain/menutoping/menutopinguc"
 	"github.com/goplateframework/internal/domain/menutoping/menutopingweb"
+	"github.com/goplateframework/internal/domain/menutopinggroup/menutopinggrouprepo"
+	"github.com/goplateframework/internal/domain/menutopinggroup/menutopinggroupuc"
+	"github.com/goplateframework/internal/domain/menutopinggroup/menutopinggroupweb"
+	"github.com/goplateframework/internal/domain/order/orderrepo"
+	"github.com/goplateframework/internal/domain/order/orderuc"
+	"github.com/goplateframework/internal/domain/order/orderweb"
 	"github.com/goplateframework/internal/domain/outlet/outletrepo"
 	"github.com/goplateframework/internal/domain/outlet/outletuc"
 	"github.com/goplateframework/internal/domain/outlet/outletweb"
+	"github.com/goplateframework/internal/domain/outletstaff/outletstaffrepo"
+	"github.com/goplateframework/internal/domain/outletstaff/outletstaffuc"
+	"github.com/goplateframework/internal/domain/outletstaff/outletstaffweb"
+	"github.com/goplateframework/internal/domain/payment/paymentrepo"
+	"github.com/goplateframework/internal/domain/payment/paymentuc"
+	"github.com/goplateframework/internal/domain/payment/paymentweb"
+	"github.com/goplateframework/internal/domain/promotion/promotionrepo"
+	"github.com/goplateframework/internal/domain/promotion/promotionuc"
+	"github.com/goplateframework/internal/domain/promotion/promotionweb"
 	"github.com/goplateframework/internal/web"
 )
 
 func router(w *web.Web, conf *Options) {
+	authCacheRepo := authrepo.NewCache(conf.Cache)
+
 	accountDBRepo := accountrepo.NewDB(conf.DB)
 	accountCacheRepo := accountrepo.NewCache(conf.Cache)
-	accountUC := accountuc.New(conf.ServConf, conf.Log, accountDBRepo, accountCacheRepo)
+	accountUC := accountuc.New(conf.ServConf, conf.Log, accountDBRepo, accountCacheRepo, authCacheRepo, conf.Mailer)
 	accountweb.Route(w, &accountweb.Options{
 		Log:       conf.Log,
 		AccountUC: accountUC,
 	})
 
-	authCacheRepo := authrepo.NewCache(conf.Cache)
-	authUC := authuc.New(conf.ServConf, conf.Log, authCacheRepo, accountDBRepo)
+	authDBRepo := authrepo.NewDB(conf.DB)
+	authUC := authuc.New(conf.ServConf, conf.Log, authCacheRepo, authDBRepo, accountDBRepo, conf.OIDC)
 	authweb.Route(w, &authweb.Options{
 		Log:    conf.Log,
 		AuthUC: authUC,
 	})
 
-	addressDBRepo := addressrepo.NewDB(conf.DB)
+	apiKeyDBRepo := apikeyrepo.NewDB(conf.DB)
+	apiKeyUC := apikeyuc.New(conf.ServConf, conf.Log, apiKeyDBRepo)
+	w.Mid.SetAPIKeyAuthenticator(apiKeyUC)
+	apikeyweb.Route(w, &apikeyweb.Options{
+		Log:      conf.Log,
+		APIKeyUC: apiKeyUC,
+	})
+
+	addressDBRepo := addressrepo.NewCachedDB(conf.DB, conf.Cache, conf.Log)
 	addressUC := addressuc.New(conf.ServConf, conf.Log, addressDBRepo)
 	addressweb.Route(w, &addressweb.Options{
 		Log:       conf.Log,
 		AddressUC: addressUC,
 	})
 
-	outletDBRepo := outletrepo.NewDB(conf.DB)
-	outletUC := outletuc.New(conf.ServConf, conf.Log, outletDBRepo)
+	outletStaffDBRepo := outletstaffrepo.NewDB(conf.DB)
+	outletStaffUC := outletstaffuc.New(conf.ServConf, conf.Log, outletStaffDBRepo, accountDBRepo)
+	outletstaffweb.Route(w, &outletstaffweb.Options{
+		Log:     conf.Log,
+		StaffUC: outletStaffUC,
+	})
+
+	outletDBRepo := outletrepo.NewCachedDB(conf.DB, conf.Cache, conf.Log)
+	outletUC := outletuc.New(conf.ServConf, conf.Log, outletDBRepo, outletStaffUC)
 	outletweb.Route(w, &outletweb.Options{
 		AddressUC: addressUC,
 		OutletUC:  outletUC,
 		Log:       conf.Log,
 	})
 
-	menuDBRepo := menurepo.NewDB(conf.DB)
-	menuUC := menuuc.New(conf.ServConf, conf.Log, conf.Worker, menuDBRepo)
+	menuDBRepo := menurepo.NewCachedDB(conf.DB, conf.Cache, conf.Log)
+	menuCategoryDBRepo := menucategoryrepo.NewDB(conf.DB)
+	menuUC := menuuc.New(conf.ServConf, conf.Log, conf.Worker, menuDBRepo, menuCategoryDBRepo, outletStaffUC)
 	menuweb.Route(w, &menuweb.Options{
 		Log:    conf.Log,
 		MenuUC: menuUC,
 	})
 
+	menuCategoryUC := menucategoryuc.New(conf.ServConf, conf.Log, menuCategoryDBRepo, menuDBRepo, outletStaffUC)
+	menucategoryweb.Route(w, &menucategoryweb.Options{
+		Log:        conf.Log,
+		CategoryUC: menuCategoryUC,
+	})
+
 	menuTopingDBRepo := menutopingrepo.NewDB(conf.DB)
-	menuTopingUC := menutopinguc.New(conf.ServConf, conf.Log, menuTopingDBRepo, conf.Worker)
+	menuTopingUC := menutopinguc.New(conf.ServConf, conf.Log, menuTopingDBRepo, conf.Worker, outletStaffUC)
 	menutopingweb.Route(w, &menutopingweb.Options{
 		Log:          conf.Log,
 		MenuTopingUC: menuTopingUC,
 	})
+
+	menuTopingGroupDBRepo := menutopinggrouprepo.NewDB(conf.DB)
+	menuTopingGroupUC := menutopinggroupuc.New(conf.ServConf, conf.Log, menuTopingGroupDBRepo, outletStaffUC)
+	menutopinggroupweb.Route(w, &menutopinggroupweb.Options{
+		Log:     conf.Log,
+		GroupUC: menuTopingGroupUC,
+	})
+
+	promotionDBRepo := promotionrepo.NewDB(conf.DB)
+	promotionUC := promotionuc.New(conf.ServConf, conf.Log, promotionDBRepo, menuDBRepo, outletStaffUC)
+	promotionweb.Route(w, &promotionweb.Options{
+		Log:         conf.Log,
+		PromotionUC: promotionUC,
+	})
+
+	orderDBRepo := orderrepo.NewDB(conf.DB)
+	orderUC := orderuc.New(conf.ServConf, conf.Log, orderDBRepo, menuTopingGroupDBRepo, outletStaffUC, promotionUC)
+	orderweb.Route(w, &orderweb.Options{
+		Log:     conf.Log,
+		OrderUC: orderUC,
+	})
+
+	cartCacheRepo := cartrepo.NewCache(conf.Cache)
+	cartUC := cartuc.New(conf.ServConf, conf.Log, cartCacheRepo, menuDBRepo, menuTopingDBRepo, menuTopingGroupDBRepo)
+	cartweb.Route(w, &cartweb.Options{
+		Log:    conf.Log,
+		CartUC: cartUC,
+	})
+
+	paymentDBRepo := paymentrepo.NewDB(conf.DB)
+	paymentUC := paymentuc.New(conf.ServConf, conf.Log, paymentDBRepo, orderDBRepo, conf.Payment, outletStaffUC)
+	paymentweb.Route(w, &paymentweb.Options{
+		Log:       conf.Log,
+		PaymentUC: paymentUC,
+	})
+
+	catalogDBRepo := catalogrepo.NewDB(conf.DB)
+	catalogUC := cataloguc.New(conf.ServConf, conf.Log, catalogDBRepo, outletUC)
+	catalogweb.Route(w, &catalogweb.Options{
+		Log:       conf.Log,
+		CatalogUC: catalogUC,
+		MaxAge:    conf.ServConf.Public.MaxAge,
+	})
 }
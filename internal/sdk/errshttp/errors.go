@@ -18,6 +18,9 @@ type ErrorResponse struct {
 		FuncName string `json:"-"`
 		FileName string `json:"-"`
 	} `json:"error"`
+
+	// response headers which are sent along with the error, such as Retry-After
+	Headers map[string]string `json:"-"`
 }
 
 type ErrorDetail struct {
@@ -55,6 +58,13 @@ func (e *ErrorResponse) AddDetail(detail string) {
 	}
 }
 
+func (e *ErrorResponse) AddHeader(key, value string) {
+	if e.Headers == nil {
+		e.Headers = make(map[string]string)
+	}
+	e.Headers[key] = value
+}
+
 func (err *ErrorResponse) AddRequestID(requestID string) {
 	err.Err.RequestID = requestID
 }
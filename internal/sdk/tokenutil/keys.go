@@ -0,0 +1,205 @@
+package tokenutil
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/goplateframework/config"
+)
+
+// asymmetric key to sign and verify access tokens, private is nil for retired keys
+type signingKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey
+	public  crypto.PublicKey
+}
+
+type keySet struct {
+	signing *signingKey
+	keys    map[string]*signingKey
+}
+
+// loaded asymmetric keys, it stays nil whenever no key is configured,
+// in which access tokens are signed using shared secret
+var keys *keySet
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeys reads every configured key from its file, it should be called once on startup.
+// Multiple keys could be active at the same time for rotation, yet only JWTSigningKeyID signs new tokens
+func LoadKeys(conf *config.Config) error {
+	if len(conf.Server.JWTKeys) == 0 {
+		return nil
+	}
+
+	ks := &keySet{keys: make(map[string]*signingKey)}
+
+	for _, kc := range conf.Server.JWTKeys {
+		if kc.ID == "" {
+			return errors.New("jwt key: id cannot be empty")
+		}
+
+		if _, exists := ks.keys[kc.ID]; exists {
+			return fmt.Errorf("jwt key %s: id is duplicated", kc.ID)
+		}
+
+		k, err := loadKey(kc.ID, kc.Algorithm, kc.PrivateKeyPath, kc.PublicKeyPath)
+		if err != nil {
+			return fmt.Errorf("jwt key %s: %v", kc.ID, err)
+		}
+
+		ks.keys[kc.ID] = k
+	}
+
+	signing, ok := ks.keys[conf.Server.JWTSigningKeyID]
+	if !ok {
+		return fmt.Errorf("jwt signing key %s is not configured", conf.Server.JWTSigningKeyID)
+	}
+
+	if signing.private == nil {
+		return fmt.Errorf("jwt signing key %s has no private key", signing.id)
+	}
+
+	ks.signing = signing
+	keys = ks
+
+	return nil
+}
+
+func loadKey(id, algorithm, privatePath, publicPath string) (*signingKey, error) {
+	k := &signingKey{id: id}
+
+	var privatePEM, publicPEM []byte
+	var err error
+
+	if privatePath != "" {
+		if privatePEM, err = os.ReadFile(privatePath); err != nil {
+			return nil, fmt.Errorf("read private key, %v", err)
+		}
+	}
+
+	if publicPath != "" {
+		if publicPEM, err = os.ReadFile(publicPath); err != nil {
+			return nil, fmt.Errorf("read public key, %v", err)
+		}
+	}
+
+	if privatePEM == nil && publicPEM == nil {
+		return nil, errors.New("either private or public key path is required")
+	}
+
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		k.method = jwt.SigningMethodRS256
+
+		if privatePEM != nil {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			k.private, k.public = private, &private.PublicKey
+		} else if k.public, err = jwt.ParseRSAPublicKeyFromPEM(publicPEM); err != nil {
+			return nil, err
+		}
+
+	case jwt.SigningMethodEdDSA.Alg():
+		k.method = jwt.SigningMethodEdDSA
+
+		if privatePEM != nil {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, err
+			}
+			k.private, k.public = private, private.(ed25519.PrivateKey).Public()
+		} else if k.public, err = jwt.ParseEdPublicKeyFromPEM(publicPEM); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s, expected RS256 or EdDSA", algorithm)
+	}
+
+	return k, nil
+}
+
+// signAccess signs access token claims using current signing key, along with kid header
+func signAccess(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(keys.signing.method, claims)
+	token.Header["kid"] = keys.signing.id
+
+	return token.SignedString(keys.signing.private)
+}
+
+// verificationKey looks up public key by kid header of given token
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	k, ok := keys.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id, %s", kid)
+	}
+
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method, %v", token.Method.Alg())
+	}
+
+	return k.public, nil
+}
+
+// JWKS exposes public part of every loaded key, so other services could verify access tokens independently
+func JWKS() *JWKSet {
+	set := &JWKSet{Keys: []JWK{}}
+
+	if keys == nil {
+		return set
+	}
+
+	for _, k := range keys.keys {
+		jwk := JWK{
+			Kid: k.id,
+			Use: "sig",
+			Alg: k.method.Alg(),
+		}
+
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
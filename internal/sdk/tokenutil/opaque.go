@@ -0,0 +1,26 @@
+package tokenutil
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaque creates a random url-safe token, along with its hash. Only the hash should be stored,
+// so leaked storage could not be used to forge requests
+func GenerateOpaque() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashOpaque(token), nil
+}
+
+// HashOpaque hashes an opaque token to look it up on storage
+func HashOpaque(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -11,22 +11,29 @@ import (
 	"github.com/goplateframework/config"
 )
 
+const (
+	DefaultAccessTokenTTL  = 10 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
 var (
-	// AccessTokenExpiredTime  = time.Now().Add(time.Minute * 10) // 10 min from now
-	AccessTokenExpiredTime  = time.Now().AddDate(0, 0, 30)
-	RefreshTokenExpiredTime = time.Now().AddDate(0, 0, 30) // 30 days from now
-	Method                  = jwt.GetSigningMethod(jwt.SigningMethodHS256.Name)
-	ErrInvalidToken         = errors.New("invalid token")
+	Method          = jwt.GetSigningMethod(jwt.SigningMethodHS256.Name)
+	ErrInvalidToken = errors.New("invalid token")
 )
 
 type AccessTokenPayload struct {
 	Email     string    `json:"email"`
 	Role      string    `json:"role"`
 	AccountID uuid.UUID `json:"account_id"`
+	SessionID uuid.UUID `json:"sid"`
+
+	// only set whenever the request is authenticated by api key, which limits what it could access
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 type RefreshTokenPayload struct {
 	AccountID uuid.UUID `json:"account_id"`
+	SessionID uuid.UUID `json:"sid"` // every rotated refresh token since login shares the same session
 }
 
 type AccessTokenClaims struct {
@@ -39,13 +46,71 @@ type RefreshTokenClaims struct {
 	RefreshTokenPayload
 }
 
+// AccessTokenTTL returns configured lifetime of access token, or its default when it is not configured
+func AccessTokenTTL(conf *config.Config) time.Duration {
+	if conf.Server.AccessTokenTTL <= 0 {
+		return DefaultAccessTokenTTL
+	}
+	return conf.Server.AccessTokenTTL * time.Second
+}
+
+// RefreshTokenTTL returns configured lifetime of refresh token, or its default when it is not configured
+func RefreshTokenTTL(conf *config.Config) time.Duration {
+	if conf.Server.RefreshTokenTTL <= 0 {
+		return DefaultRefreshTokenTTL
+	}
+	return conf.Server.RefreshTokenTTL * time.Second
+}
+
+// RefreshTokenExpiry computes expiration of the next refresh token. On sliding window, or when there is
+// no current refresh token (login), it expires a full lifetime from now, otherwise it keeps expiration of current one
+func RefreshTokenExpiry(conf *config.Config, current *RefreshTokenClaims) time.Time {
+	if current == nil || current.ExpiresAt == nil || conf.Server.RefreshTokenSliding {
+		return time.Now().Add(RefreshTokenTTL(conf))
+	}
+	return current.ExpiresAt.Time
+}
+
+func registeredClaims(conf *config.Config, exp time.Time) jwt.RegisteredClaims {
+	now := time.Now()
+
+	claims := jwt.RegisteredClaims{
+		Issuer:    conf.Server.JWTIssuer,
+		ExpiresAt: jwt.NewNumericDate(exp),
+		NotBefore: jwt.NewNumericDate(now),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+
+	if conf.Server.JWTAudience != "" {
+		claims.Audience = jwt.ClaimStrings{conf.Server.JWTAudience}
+	}
+
+	return claims
+}
+
+// parserOptions requires expiration on every token, as well as issuer and audience whenever they are configured
+func parserOptions(conf *config.Config) []jwt.ParserOption {
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired(), jwt.WithIssuedAt()}
+
+	if conf.Server.JWTIssuer != "" {
+		opts = append(opts, jwt.WithIssuer(conf.Server.JWTIssuer))
+	}
+
+	if conf.Server.JWTAudience != "" {
+		opts = append(opts, jwt.WithAudience(conf.Server.JWTAudience))
+	}
+
+	return opts
+}
+
 func GenerateAccess(conf *config.Config, payload AccessTokenPayload) (string, error) {
 	claims := &AccessTokenClaims{
 		AccessTokenPayload: payload,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(AccessTokenExpiredTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+		RegisteredClaims:   registeredClaims(conf, time.Now().Add(AccessTokenTTL(conf))),
+	}
+
+	if keys != nil {
+		return signAccess(claims)
 	}
 
 	token := jwt.NewWithClaims(Method, claims)
@@ -56,35 +121,42 @@ func GenerateAccess(conf *config.Config, payload AccessTokenPayload) (string, er
 	return tokenString, nil
 }
 
-func GenerateRefresh(conf *config.Config, payload RefreshTokenPayload) (string, error) {
+// GenerateRefresh signs a refresh token which expires at given time, along with a unique token id (jti),
+// which is returned as well
+func GenerateRefresh(conf *config.Config, payload RefreshTokenPayload, exp time.Time) (string, string, error) {
+	tokenID := uuid.NewString()
+
 	claims := &RefreshTokenClaims{
 		RefreshTokenPayload: payload,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(RefreshTokenExpiredTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+		RegisteredClaims:    registeredClaims(conf, exp),
 	}
+	claims.ID = tokenID
 
 	token := jwt.NewWithClaims(Method, claims)
 	tokenString, err := token.SignedString([]byte(conf.Server.JWTRefreshTokenSecret))
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	return tokenString, nil
+	return tokenString, tokenID, nil
 }
 
 func ValidateAccess(conf *config.Config, requestToken string) (*AccessTokenClaims, error) {
 	claims := new(AccessTokenClaims)
 
 	token, err := jwt.ParseWithClaims(requestToken, claims, func(token *jwt.Token) (interface{}, error) {
+		// shared secret is no longer accepted once asymmetric keys are configured
+		if keys != nil {
+			return verificationKey(token)
+		}
+
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method, %v", token.Method)
 		}
 		return []byte(conf.Server.JWTAccessTokenSecret), nil
-	})
+	}, parserOptions(conf)...)
 
 	if err != nil {
-		return nil, fmt.Errorf("token signature is invalid")
+		return nil, fmt.Errorf("%w, %v", ErrInvalidToken, err)
 	}
 
 	if !token.Valid {
@@ -102,10 +174,10 @@ func ValidateRefresh(conf *config.Config, requestToken string) (*RefreshTokenCla
 			return nil, fmt.Errorf("unexpected signing method, %v", token.Method)
 		}
 		return []byte(conf.Server.JWTRefreshTokenSecret), nil
-	})
+	}, parserOptions(conf)...)
 
 	if err != nil {
-		return nil, fmt.Errorf("token signature is invalid")
+		return nil, fmt.Errorf("%w, %v", ErrInvalidToken, err)
 	}
 
 	if !token.Valid {
@@ -25,6 +25,21 @@ var (
 		}
 		return nil
 	})
+
+	// Timezone accepts IANA timezone names such as Asia/Jakarta, UTC is accepted as well
+	Timezone = validation.By(func(value interface{}) error {
+		s, _ := value.(string)
+		if s == "" {
+			return nil
+		}
+		if s == "Local" {
+			return errors.New("must be an IANA timezone")
+		}
+		if _, err := time.LoadLocation(s); err != nil {
+			return errors.New("must be an IANA timezone")
+		}
+		return nil
+	})
 )
 
 func SplitErrors(err error) []string {
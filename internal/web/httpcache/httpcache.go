@@ -0,0 +1,47 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JSON sends v along with an ETag of its body, so clients could revalidate it cheaply. Whenever If-None-Match of the request
+// matches, only 304 is sent. Response is public, it must never be used for anything which depends on who is asking
+func JSON(c echo.Context, maxAge time.Duration, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	etag := fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:16]))
+
+	h := c.Response().Header()
+	h.Set(echo.HeaderCacheControl, fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", int(maxAge.Seconds()), int(maxAge.Seconds())))
+	h.Set("ETag", etag)
+	h.Set(echo.HeaderVary, "Accept-Encoding")
+
+	if matches(c.Request().Header.Get("If-None-Match"), etag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSONBlob(http.StatusOK, body)
+}
+
+// matches tells whether If-None-Match holds given etag, weak comparison is used as RFC 9110 requires
+func matches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
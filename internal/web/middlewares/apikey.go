@@ -0,0 +1,97 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/sdk/tokenutil"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/labstack/echo/v4"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator resolves an api key into claims of its owner
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*tokenutil.AccessTokenClaims, error)
+}
+
+// resource which is guarded by scopes of api keys, keyed by route prefix. First matching prefix wins,
+// and empty resource means the route could not be accessed by api key at all. Any route which is not
+// listed is also denied, so api keys could never reach account, auth or api key management
+var apiKeyResources = []struct {
+	prefix   string
+	resource string
+}{
+	{"/api/v1/outlet/:id/staff", ""},
+	{"/api/v1/outlet", "outlet"},
+	{"/api/v1/menu-topings", "menu"},
+	{"/api/v1/menu-toping-groups", "menu"},
+	{"/api/v1/menu-categories", "menu"},
+	{"/api/v1/menu", "menu"},
+}
+
+// SetAPIKeyAuthenticator enables api key as an alternative of bearer token on Authenticated
+func (mid *Middleware) SetAPIKeyAuthenticator(a APIKeyAuthenticator) {
+	mid.apiKeys = a
+}
+
+func (mid *Middleware) authenticateAPIKey(c echo.Context, key string) error {
+	if mid.apiKeys == nil {
+		e := errshttp.New(errshttp.Unauthenticated, "API key is not supported")
+		e.AddDetail("key: use bearer authentication header instead")
+		return e
+	}
+
+	claims, err := mid.apiKeys.Authenticate(c.Request().Context(), key)
+	if err != nil {
+		return err
+	}
+
+	if err := checkScope(c, claims.Scopes); err != nil {
+		return err
+	}
+
+	ctx := webcontext.SetAccessTokenClaims(c.Request().Context(), claims)
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return nil
+}
+
+// checkScope requires <resource>:read scope for safe methods and <resource>:write for the others,
+// against resource of the matched route
+func checkScope(c echo.Context, scopes []string) error {
+	path := c.Path()
+	resource := ""
+
+	for _, r := range apiKeyResources {
+		if path == r.prefix || strings.HasPrefix(path, r.prefix+"/") {
+			resource = r.resource
+			break
+		}
+	}
+
+	if resource == "" {
+		e := errshttp.New(errshttp.PermissionDenied, "Could not give access to this resource")
+		e.AddDetail("key: this route could not be accessed by api key")
+		return e
+	}
+
+	action := "write"
+	if m := c.Request().Method; m == http.MethodGet || m == http.MethodHead {
+		action = "read"
+	}
+
+	required := resource + ":" + action
+	if !slices.Contains(scopes, required) {
+		e := errshttp.New(errshttp.PermissionDenied, "Could not give access to this resource")
+		e.AddDetail(fmt.Sprintf("scopes: api key requires %s %s scope", resource, action))
+		return e
+	}
+
+	return nil
+}
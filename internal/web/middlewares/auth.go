@@ -14,6 +14,14 @@ func (mid *Middleware) Authenticated(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		authHeader := c.Request().Header.Get("Authorization")
 
+		// api key is only considered whenever bearer token is absent
+		if key := c.Request().Header.Get(APIKeyHeader); key != "" && authHeader == "" {
+			if err := mid.authenticateAPIKey(c, key); err != nil {
+				return err
+			}
+			return next(c)
+		}
+
 		token, err := tokenutil.ExtractBearerToken(authHeader)
 		if err != nil {
 			e := errshttp.New(errshttp.Unauthenticated, "Authorization header missing")
@@ -39,6 +47,18 @@ func (mid *Middleware) Authenticated(next echo.HandlerFunc) echo.HandlerFunc {
 			return errshttp.New(errshttp.Internal, "Something went wrong")
 		}
 
+		// whenever session of the token has been revoked, it will return error
+		active, err := mid.authCache.IsSessionActive(c.Request().Context(), claims.SessionID)
+		if err != nil {
+			return errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		if !active {
+			e := errshttp.New(errshttp.Unauthenticated, "Session has been revoked")
+			e.AddDetail("token: session of access_token is no longer active")
+			return e
+		}
+
 		ctx := webcontext.SetAccessTokenClaims(c.Request().Context(), claims)
 		ctx = webcontext.SetAccessToken(ctx, token)
 		c.SetRequest(c.Request().WithContext(ctx))
@@ -53,6 +73,7 @@ func (mid *Middleware) RefreshAuth(next echo.HandlerFunc) echo.HandlerFunc {
 		if refreshToken == "" {
 			e := errshttp.New(errshttp.Unauthenticated, "RT-Token header missing")
 			e.AddDetail("token: expected RT-Token header with refresh token as its value")
+			return e
 		}
 
 		claims, err := tokenutil.ValidateRefresh(mid.conf, refreshToken)
@@ -62,10 +83,15 @@ func (mid *Middleware) RefreshAuth(next echo.HandlerFunc) echo.HandlerFunc {
 			return e
 		}
 
-		// whenever token exist in blacklist, it will return error
-		if val, _ := mid.cache.Get(c.Request().Context(), claims.AccountID.String()).Result(); val != "" {
+		// whenever session of the token has been revoked, it will return error
+		active, err := mid.authCache.IsSessionActive(c.Request().Context(), claims.SessionID)
+		if err != nil {
+			return errshttp.New(errshttp.Internal, "Something went wrong")
+		}
+
+		if !active {
 			e := errshttp.New(errshttp.Unauthenticated, "User already logged out")
-			e.AddDetail(fmt.Sprintf("data: %s", err))
+			e.AddDetail("token: refresh_token has been revoked")
 			return e
 		}
 
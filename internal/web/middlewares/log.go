@@ -44,6 +44,10 @@ func (mid *Middleware) ErrorLoggingMware(next echo.HandlerFunc) echo.HandlerFunc
 				e.AddRequestID(reqId)
 				mid.log.Error(e.LogForDebug())
 
+				for k, v := range e.Headers {
+					c.Response().Header().Set(k, v)
+				}
+
 				return c.JSON(e.HTTPStatus(), e)
 			default:
 				mid.log.Error(err.Error())
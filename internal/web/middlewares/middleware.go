@@ -4,22 +4,26 @@ import (
 	"net/http"
 
 	"github.com/goplateframework/config"
+	"github.com/goplateframework/internal/domain/auth/authrepo"
 	"github.com/goplateframework/pkg/logger"
 	"github.com/redis/go-redis/v9"
 )
 
 type Middleware struct {
-	conf  *config.Config
-	log   *logger.Log
-	cache *redis.Client
+	conf      *config.Config
+	log       *logger.Log
+	cache     *redis.Client
+	authCache *authrepo.Cache
+	apiKeys   APIKeyAuthenticator
 }
 
 type MiddlewareFunc func(h http.Handler) http.Handler
 
 func New(conf *config.Config, log *logger.Log, cache *redis.Client) *Middleware {
 	return &Middleware{
-		conf:  conf,
-		log:   log,
-		cache: cache,
+		conf:      conf,
+		log:       log,
+		cache:     cache,
+		authCache: authrepo.NewCache(cache),
 	}
 }
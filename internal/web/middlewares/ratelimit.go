@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/labstack/echo/v4"
+)
+
+// PublicRateLimit limits anonymous routes per client ip, separately from any other limit
+func (mid *Middleware) PublicRateLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	limit := mid.conf.Public.RateLimit
+	if limit <= 0 {
+		limit = 120
+	}
+
+	window := mid.conf.Public.RateWindow * time.Second
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	return mid.rateLimit("public", limit, window)(next)
+}
+
+// rateLimit counts requests of a client ip on fixed windows in cache. Whenever cache is unreachable requests are let through,
+// since refusing every client is worse than letting some of them exceed the limit
+func (mid *Middleware) rateLimit(scope string, limit int, window time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			now := time.Now()
+			start := now.Truncate(window)
+			key := fmt.Sprintf("ratelimit:%s:%s:%d", scope, c.RealIP(), start.Unix())
+
+			pipe := mid.cache.TxPipeline()
+			incr := pipe.Incr(ctx, key)
+			pipe.ExpireNX(ctx, key, window)
+
+			if _, err := pipe.Exec(ctx); err != nil {
+				mid.log.Errorf("failed to count requests of %s, %v", key, err)
+				return next(c)
+			}
+
+			count := int(incr.Val())
+			remaining := max(limit-count, 0)
+			reset := int(start.Add(window).Sub(now).Seconds()) + 1
+
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			h.Set("X-RateLimit-Reset", strconv.Itoa(reset))
+
+			if count > limit {
+				e := errshttp.New(errshttp.ResourceExhausted, "Too many requests")
+				e.AddDetail(fmt.Sprintf("rate: limited to %d requests per %d seconds, try again in %d seconds", limit, int(window.Seconds()), reset))
+				e.AddHeader("Retry-After", strconv.Itoa(reset))
+				return e
+			}
+
+			return next(c)
+		}
+	}
+}
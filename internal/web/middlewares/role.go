@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/goplateframework/internal/domain/account"
+	"github.com/goplateframework/internal/sdk/errshttp"
+	"github.com/goplateframework/internal/web/webcontext"
+	"github.com/labstack/echo/v4"
+)
+
+// Policy maps http method into roles which are allowed to call it,
+// method which is not listed on the policy is denied for every role
+type Policy map[string][]string
+
+var (
+	AnyRole         = []string{account.RoleUser, account.RoleAdmin, account.RoleSuperadmin}
+	AdminRoles      = []string{account.RoleAdmin, account.RoleSuperadmin}
+	SuperadminRoles = []string{account.RoleSuperadmin}
+)
+
+// ReadAnyWriteAdmin lets every account read, yet only admins are able to mutate
+var ReadAnyWriteAdmin = Policy{
+	http.MethodGet:    AnyRole,
+	http.MethodHead:   AnyRole,
+	http.MethodPost:   AdminRoles,
+	http.MethodPut:    AdminRoles,
+	http.MethodPatch:  AdminRoles,
+	http.MethodDelete: AdminRoles,
+}
+
+// RequireRole only lets accounts with one of given roles through,
+// it relies on access token claims, so it should be placed after Authenticated
+func (mid *Middleware) RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+			if !slices.Contains(roles, claims.Role) {
+				return permissionDenied(claims.Role)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// Authorize checks role on access token claims against roles listed for the request method on given policy
+func (mid *Middleware) Authorize(policy Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims := webcontext.GetAccessTokenClaims(c.Request().Context())
+
+			roles, ok := policy[c.Request().Method]
+			if !ok || !slices.Contains(roles, claims.Role) {
+				return permissionDenied(claims.Role)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func permissionDenied(role string) error {
+	if role == "" {
+		role = "unknown"
+	}
+
+	e := errshttp.New(errshttp.PermissionDenied, "Could not give access to this resource")
+	e.AddDetail(fmt.Sprintf("role: %s is not allowed to perform this action", role))
+	return e
+}
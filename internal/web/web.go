@@ -1,6 +1,7 @@
 package web
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/goplateframework/config"
@@ -34,17 +35,42 @@ func (w *Web) EnableCORSMware(origins []string) {
 		AllowMethods: []string{
 			http.MethodGet,
 			http.MethodPut,
+			http.MethodPatch,
 			http.MethodPost,
 			http.MethodDelete,
 			http.MethodOptions,
 		},
-		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "RF-Token"},
-		ExposeHeaders:    []string{"Link"},
+		AllowHeaders:     []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "RF-Token", "X-API-Key", "If-None-Match"},
+		ExposeHeaders:    []string{"Link", "ETag", "Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 }
 
+// EnableIPExtractor decides where client ip comes from, rate limits and login throttle are keyed by it.
+// X-Forwarded-For is trusted only from given proxy ranges, otherwise any client could forge its ip through headers
+func (w *Web) EnableIPExtractor(trustedProxies []string) {
+	if len(trustedProxies) == 0 {
+		w.Echo.IPExtractor = echo.ExtractIPDirect()
+		return
+	}
+
+	opts := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range trustedProxies {
+		_, ipRange, err := net.ParseCIDR(cidr)
+		if err != nil {
+			w.log.Fatalf("invalid trusted proxy range %s, %v", cidr, err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipRange))
+	}
+
+	w.Echo.IPExtractor = echo.ExtractIPFromXFFHeader(opts...)
+}
+
 func (w *Web) EnableRecovererMware() {
 	w.Echo.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
 		StackSize:         1 << 10, // 1 KB
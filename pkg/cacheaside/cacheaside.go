@@ -0,0 +1,199 @@
+package cacheaside
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// setScript stores an entry and adds its key into every tag set, a tag set lives as long as its longest entry.
+// Whenever a generation is given, nothing is stored if one of the tags has been invalidated after it
+//
+// KEYS: entry, then tag set and its generation of every tag
+// ARGV: value, ttl in milliseconds, generation which is read before the value was loaded or empty
+var setScript = redis.NewScript(`
+if ARGV[3] ~= '' then
+	for i = 3, #KEYS, 2 do
+		if tonumber(redis.call('GET', KEYS[i]) or '0') > tonumber(ARGV[3]) then
+			return 0
+		end
+	end
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+for i = 2, #KEYS, 2 do
+	redis.call('SADD', KEYS[i], KEYS[1])
+	if redis.call('PTTL', KEYS[i]) < tonumber(ARGV[2]) then
+		redis.call('PEXPIRE', KEYS[i], ARGV[2])
+	end
+end
+return 1
+`)
+
+// invalidateScript removes every entry listed on given tag sets, along with the tag sets themselves.
+// Every tag is stamped with a new generation, so loads which have started before are not cached
+//
+// KEYS: generation sequence, then tag set and its generation of every tag
+// ARGV: ttl of generations in milliseconds
+var invalidateScript = redis.NewScript(`
+local gen = redis.call('INCR', KEYS[1])
+for i = 2, #KEYS, 2 do
+	for _, key in ipairs(redis.call('SMEMBERS', KEYS[i])) do
+		redis.call('DEL', key)
+	end
+	redis.call('DEL', KEYS[i])
+	redis.call('SET', KEYS[i + 1], gen, 'PX', ARGV[1])
+end
+return 1
+`)
+
+// Cache keeps values of type T on redis under its own namespace, entries are loaded from their source on miss.
+// Entries could be tagged, so every entry which relates to a tag is invalidated at once, regardless of its namespace
+type Cache[T any] struct {
+	client    *redis.Client
+	namespace string
+	ttl       time.Duration
+	group     singleflight.Group
+}
+
+func New[T any](client *redis.Client, namespace string, ttl time.Duration) *Cache[T] {
+	return &Cache[T]{
+		client:    client,
+		namespace: namespace,
+		ttl:       ttl,
+	}
+}
+
+// Get returns cached value of given key, ok is false whenever the key is missing
+func (c *Cache[T]) Get(ctx context.Context, key string) (v T, ok bool, err error) {
+	data, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if err == redis.Nil {
+		return v, false, nil
+	}
+
+	if err != nil {
+		return v, false, err
+	}
+
+	if err := sonic.Unmarshal(data, &v); err != nil {
+		return v, false, err
+	}
+
+	return v, true, nil
+}
+
+func (c *Cache[T]) Set(ctx context.Context, key string, v T, tags ...string) error {
+	return c.set(ctx, key, v, "", tags...)
+}
+
+// set stores an entry unless one of its tags has been invalidated after given generation, empty generation always stores it
+func (c *Cache[T]) set(ctx context.Context, key string, v T, gen string, tags ...string) error {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(tags)*2+1)
+	keys = append(keys, c.key(key))
+	for _, t := range tags {
+		keys = append(keys, tagKey(t), tagGenKey(t))
+	}
+
+	return setScript.Run(ctx, c.client, keys, data, c.ttl.Milliseconds(), gen).Err()
+}
+
+// loadTimeout bounds a shared load, since it no longer ends along with the caller which started it
+const loadTimeout = 10 * time.Second
+
+// genSequenceKey counts invalidations, generation of a tag is the sequence of its latest invalidation.
+// Tags are only known once a value is loaded, so the sequence is read before loading instead of every tag generation
+const genSequenceKey = "cache_gen_seq"
+
+// genTTL keeps generation of a tag as long as a load which has started before its invalidation could still be running
+const genTTL = 2 * loadTimeout
+
+// Fetch returns cached value of given key, or loads it from its source and caches it on miss.
+// Concurrent misses of the same key share a single load. Entry is tagged by tags of the loaded value,
+// which could be nil. Value is not cached whenever one of its tags is invalidated while it is loading, since it may be stale.
+// Cache is never a reason to fail, value is served from its source whenever redis fails
+func (c *Cache[T]) Fetch(ctx context.Context, key string, load func(ctx context.Context) (T, error), tags func(v T) []string) (T, error) {
+	if v, ok, err := c.Get(ctx, key); err == nil && ok {
+		return v, nil
+	}
+
+	res, err, _ := c.group.Do(key, func() (interface{}, error) {
+		// callers which wait on the same load should not fail whenever the first caller goes away
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		gen, genErr := c.client.Get(ctx, genSequenceKey).Result()
+		if genErr == redis.Nil {
+			gen, genErr = "0", nil
+		}
+
+		v, err := load(ctx)
+		if err != nil {
+			return v, err
+		}
+
+		// without generation, value could not be told apart from a stale one, so it is served without being cached
+		if genErr != nil {
+			return v, nil
+		}
+
+		var t []string
+		if tags != nil {
+			t = tags(v)
+		}
+
+		_ = c.set(ctx, key, v, gen, t...)
+		return v, nil
+	})
+
+	v, _ := res.(T)
+	return v, err
+}
+
+// Invalidate removes entries of given keys
+func (c *Cache[T]) Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	namespaced := make([]string, len(keys))
+	for i, k := range keys {
+		namespaced[i] = c.key(k)
+	}
+
+	return c.client.Del(ctx, namespaced...).Err()
+}
+
+// InvalidateTags removes every entry tagged by one of given tags, in any namespace
+func InvalidateTags(ctx context.Context, client *redis.Client, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags)*2+1)
+	keys = append(keys, genSequenceKey)
+	for _, t := range tags {
+		keys = append(keys, tagKey(t), tagGenKey(t))
+	}
+
+	return invalidateScript.Run(ctx, client, keys, genTTL.Milliseconds()).Err()
+}
+
+func (c *Cache[T]) key(key string) string {
+	return fmt.Sprintf("%s:%s", c.namespace, key)
+}
+
+func tagKey(tag string) string {
+	return fmt.Sprintf("cache_tag:%s", tag)
+}
+
+func tagGenKey(tag string) string {
+	return fmt.Sprintf("cache_tag_gen:%s", tag)
+}
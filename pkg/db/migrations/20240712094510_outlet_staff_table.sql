@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outlet_staff;
+DROP TYPE IF EXISTS staff_role;
+DROP INDEX IF EXISTS outlet_staff_account_idx;
+
+CREATE TYPE staff_role AS ENUM('manager', 'cashier', 'kitchen');
+
+CREATE TABLE IF NOT EXISTS
+    outlet_staff (
+        outlet_id       uuid                        NOT NULL,
+        account_id      uuid                        NOT NULL,
+        role            staff_role                  NOT NULL    DEFAULT 'manager',
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        PRIMARY KEY (outlet_id, account_id),
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE CASCADE,
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS outlet_staff_account_idx ON outlet_staff (account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outlet_staff;
+DROP INDEX IF EXISTS outlet_staff_account_idx;
+DROP TYPE IF EXISTS staff_role;
+-- +goose StatementEnd
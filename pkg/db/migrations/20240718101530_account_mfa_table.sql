@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_mfa;
+DROP TABLE IF EXISTS account_recovery_codes;
+DROP INDEX IF EXISTS account_recovery_codes_account_idx;
+
+CREATE TABLE IF NOT EXISTS
+    account_mfa (
+        account_id      uuid PRIMARY KEY            NOT NULL,
+        secret          varchar(255)                NOT NULL,
+        enabled_at      TIMESTAMP WITH TIME ZONE    NULL,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+    );
+
+CREATE TABLE IF NOT EXISTS
+    account_recovery_codes (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        account_id      uuid                        NOT NULL,
+        code_hash       varchar(64)                 NOT NULL,
+        used_at         TIMESTAMP WITH TIME ZONE    NULL,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS account_recovery_codes_account_idx ON account_recovery_codes (account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_recovery_codes;
+DROP INDEX IF EXISTS account_recovery_codes_account_idx;
+DROP TABLE IF EXISTS account_mfa;
+-- +goose StatementEnd
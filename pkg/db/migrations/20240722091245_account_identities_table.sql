@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_identities;
+DROP INDEX IF EXISTS account_identities_provider_subject_idx;
+DROP INDEX IF EXISTS account_identities_account_idx;
+
+CREATE TABLE IF NOT EXISTS
+    account_identities (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        account_id      uuid                        NOT NULL,
+        provider        varchar(50)                 NOT NULL,
+        subject         varchar(255)                NOT NULL,
+        email           varchar(255)                NOT NULL,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+    );
+CREATE UNIQUE INDEX IF NOT EXISTS account_identities_provider_subject_idx ON account_identities (provider, subject);
+CREATE INDEX IF NOT EXISTS account_identities_account_idx ON account_identities (account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS account_identities;
+DROP INDEX IF EXISTS account_identities_provider_subject_idx;
+DROP INDEX IF EXISTS account_identities_account_idx;
+-- +goose StatementEnd
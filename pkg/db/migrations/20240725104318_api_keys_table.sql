@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+DROP INDEX IF EXISTS api_keys_prefix_idx;
+DROP INDEX IF EXISTS api_keys_account_idx;
+
+CREATE TABLE IF NOT EXISTS
+    api_keys (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        account_id      uuid                        NOT NULL,
+        name            varchar(100)                NOT NULL,
+        prefix          varchar(16)                 NOT NULL,
+        key_hash        varchar(64)                 NOT NULL,
+        scopes          text                        NOT NULL    DEFAULT '',
+        expires_at      TIMESTAMP WITH TIME ZONE    NULL,
+        last_used_at    TIMESTAMP WITH TIME ZONE    NULL,
+        revoked_at      TIMESTAMP WITH TIME ZONE    NULL,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+    );
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_prefix_idx ON api_keys (prefix);
+CREATE INDEX IF NOT EXISTS api_keys_account_idx ON api_keys (account_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+DROP INDEX IF EXISTS api_keys_prefix_idx;
+DROP INDEX IF EXISTS api_keys_account_idx;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMP WITH TIME ZONE NULL;
+CREATE INDEX IF NOT EXISTS accounts_created_at_idx ON accounts (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS accounts_created_at_idx;
+ALTER TABLE accounts DROP COLUMN IF EXISTS suspended_at;
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_item_topings;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TYPE IF EXISTS order_status;
+DROP INDEX IF EXISTS orders_account_idx;
+DROP INDEX IF EXISTS orders_outlet_idx;
+
+CREATE TYPE order_status AS ENUM('pending', 'accepted', 'preparing', 'ready', 'completed', 'cancelled');
+
+CREATE TABLE IF NOT EXISTS
+    orders (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        account_id      uuid                        NOT NULL,
+        outlet_id       uuid                        NOT NULL,
+        status          order_status                NOT NULL    DEFAULT 'pending',
+        total           numeric(12,2)               NOT NULL,
+        note            varchar(255)                NOT NULL    DEFAULT '',
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT,
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE RESTRICT
+    );
+CREATE INDEX IF NOT EXISTS orders_account_idx ON orders (account_id, created_at);
+CREATE INDEX IF NOT EXISTS orders_outlet_idx ON orders (outlet_id, status, created_at);
+
+-- lines snapshot name and price at the time of ordering, so they outlive changes of menus and topings
+CREATE TABLE IF NOT EXISTS
+    order_items (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        order_id        uuid                        NOT NULL,
+        menu_id         uuid                        NULL,
+        name            varchar(50)                 NOT NULL,
+        price           numeric(10,2)               NOT NULL,
+        quantity        integer                     NOT NULL,
+        total           numeric(12,2)               NOT NULL,
+
+        FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+        FOREIGN KEY (menu_id) REFERENCES menus(id) ON DELETE SET NULL
+    );
+
+CREATE TABLE IF NOT EXISTS
+    order_item_topings (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        order_item_id   uuid                        NOT NULL,
+        menu_toping_id  uuid                        NULL,
+        name            varchar(50)                 NOT NULL,
+        price           numeric(10,2)               NOT NULL,
+
+        FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+        FOREIGN KEY (menu_toping_id) REFERENCES menu_topings(id) ON DELETE SET NULL
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_item_topings;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP INDEX IF EXISTS orders_account_idx;
+DROP INDEX IF EXISTS orders_outlet_idx;
+DROP TYPE IF EXISTS order_status;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS menu_toping_stock_movements;
+DROP TYPE IF EXISTS stock_movement_kind;
+DROP INDEX IF EXISTS menu_toping_stock_movements_toping_idx;
+
+CREATE TYPE stock_movement_kind AS ENUM('reserve', 'release', 'adjust');
+
+-- ledger of every stock change of a toping, stock_after is stock of the toping right after the movement
+CREATE TABLE IF NOT EXISTS
+    menu_toping_stock_movements (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        menu_toping_id  uuid                        NOT NULL,
+        kind            stock_movement_kind         NOT NULL,
+        delta           integer                     NOT NULL,
+        stock_after     integer                     NOT NULL,
+        reason          varchar(255)                NOT NULL,
+        account_id      uuid                        NULL,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (menu_toping_id) REFERENCES menu_topings(id) ON DELETE CASCADE,
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE SET NULL
+    );
+CREATE INDEX IF NOT EXISTS menu_toping_stock_movements_toping_idx ON menu_toping_stock_movements (menu_toping_id, created_at);
+
+-- topings which exist before the ledger open it with their current stock, so every ledger adds up to its stock
+INSERT INTO menu_toping_stock_movements
+    (menu_toping_id, kind, delta, stock_after, reason, created_at)
+SELECT id, 'adjust', stock, stock, 'initial stock', created_at
+FROM menu_topings;
+
+ALTER TABLE menu_topings ADD CONSTRAINT menu_topings_stock_check CHECK (stock >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE menu_topings DROP CONSTRAINT IF EXISTS menu_topings_stock_check;
+DROP TABLE IF EXISTS menu_toping_stock_movements;
+DROP INDEX IF EXISTS menu_toping_stock_movements_toping_idx;
+DROP TYPE IF EXISTS stock_movement_kind;
+-- +goose StatementEnd
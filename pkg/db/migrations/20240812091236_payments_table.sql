@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payment_webhook_events;
+DROP TABLE IF EXISTS payment_attempts;
+DROP TABLE IF EXISTS payments;
+DROP TYPE IF EXISTS payment_status;
+
+CREATE TYPE payment_status AS ENUM('pending', 'succeeded', 'failed', 'refunded');
+
+-- amount is taken from total of the order, which is priced from menus.price on ordering and never from client
+CREATE TABLE IF NOT EXISTS
+    payments (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        order_id        uuid                        NOT NULL    UNIQUE,
+        account_id      uuid                        NOT NULL,
+        outlet_id       uuid                        NOT NULL,
+        amount          numeric(12,2)               NOT NULL,
+        currency        varchar(3)                  NOT NULL,
+        status          payment_status              NOT NULL    DEFAULT 'pending',
+        provider        varchar(20)                 NOT NULL,
+        provider_ref    varchar(100)                NULL, -- charge of the latest attempt
+        attempts        integer                     NOT NULL    DEFAULT 0,
+        failure_reason  varchar(255)                NOT NULL    DEFAULT '',
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE RESTRICT,
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT,
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE RESTRICT
+    );
+
+-- every charge which has been created on provider, a failed payment is retried through a new attempt
+CREATE TABLE IF NOT EXISTS
+    payment_attempts (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        payment_id      uuid                        NOT NULL,
+        provider_ref    varchar(100)                NOT NULL    UNIQUE,
+        status          payment_status              NOT NULL    DEFAULT 'pending',
+        failure_reason  varchar(255)                NOT NULL    DEFAULT '',
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE
+    );
+
+-- webhook events which have been handled, so a redelivered event is never applied twice
+CREATE TABLE IF NOT EXISTS
+    payment_webhook_events (
+        id              varchar(100)                NOT NULL,
+        provider        varchar(20)                 NOT NULL,
+        provider_ref    varchar(100)                NOT NULL,
+        status          payment_status              NOT NULL,
+        received_at     TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        PRIMARY KEY (provider, id)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS payment_webhook_events;
+DROP TABLE IF EXISTS payment_attempts;
+DROP TABLE IF EXISTS payments;
+DROP TYPE IF EXISTS payment_status;
+-- +goose StatementEnd
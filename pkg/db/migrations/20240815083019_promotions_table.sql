@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS discount;
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
+DROP TYPE IF EXISTS promotion_kind;
+DROP TYPE IF EXISTS promotion_scope;
+DROP INDEX IF EXISTS promotions_outlet_code_idx;
+DROP INDEX IF EXISTS promotions_outlet_idx;
+DROP INDEX IF EXISTS promotion_redemptions_account_idx;
+
+CREATE TYPE promotion_kind AS ENUM('percentage', 'fixed');
+CREATE TYPE promotion_scope AS ENUM('outlet', 'menu');
+
+-- promotion without code applies on its own, otherwise customer should enter its code.
+-- value is a percentage between 0 and 100 for percentage kind, or an amount for fixed kind
+CREATE TABLE IF NOT EXISTS
+    promotions (
+        id                  uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        outlet_id           uuid                        NOT NULL,
+        menu_id             uuid                        NULL,
+        code                varchar(30)                 NULL,
+        name                varchar(50)                 NOT NULL,
+        kind                promotion_kind              NOT NULL,
+        scope               promotion_scope             NOT NULL,
+        value               numeric(10,2)               NOT NULL,
+        min_spend           numeric(12,2)               NOT NULL    DEFAULT 0,
+        starts_at           TIMESTAMP WITH TIME ZONE    NOT NULL,
+        ends_at             TIMESTAMP WITH TIME ZONE    NULL,
+        usage_limit         integer                     NULL,
+        per_account_limit   integer                     NULL,
+        used_count          integer                     NOT NULL    DEFAULT 0,
+        is_active           boolean                     NOT NULL    DEFAULT true,
+        created_at          TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at          TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE CASCADE,
+        FOREIGN KEY (menu_id) REFERENCES menus(id) ON DELETE CASCADE,
+        CHECK ((scope = 'menu') = (menu_id IS NOT NULL)),
+        CHECK (usage_limit IS NULL OR used_count <= usage_limit)
+    );
+CREATE UNIQUE INDEX IF NOT EXISTS promotions_outlet_code_idx ON promotions (outlet_id, upper(code)) WHERE code IS NOT NULL;
+CREATE INDEX IF NOT EXISTS promotions_outlet_idx ON promotions (outlet_id, is_active, starts_at);
+
+-- promotion is redeemed along with the order it discounts, an order redeems at most one promotion
+CREATE TABLE IF NOT EXISTS
+    promotion_redemptions (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        promotion_id    uuid                        NOT NULL,
+        account_id      uuid                        NOT NULL,
+        order_id        uuid                        NOT NULL    UNIQUE,
+        subtotal        numeric(12,2)               NOT NULL,
+        discount        numeric(12,2)               NOT NULL,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE CASCADE,
+        FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT,
+        FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+    );
+CREATE INDEX IF NOT EXISTS promotion_redemptions_account_idx ON promotion_redemptions (promotion_id, account_id);
+
+-- discount of the promotion which has been redeemed on an order, total of the order is what is charged after it
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount numeric(12,2) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS discount;
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
+DROP INDEX IF EXISTS promotions_outlet_code_idx;
+DROP INDEX IF EXISTS promotions_outlet_idx;
+DROP INDEX IF EXISTS promotion_redemptions_account_idx;
+DROP TYPE IF EXISTS promotion_kind;
+DROP TYPE IF EXISTS promotion_scope;
+-- +goose StatementEnd
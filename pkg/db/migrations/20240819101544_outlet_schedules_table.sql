@@ -0,0 +1,88 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outlet_closures;
+DROP TABLE IF EXISTS outlet_schedules;
+DROP INDEX IF EXISTS outlet_schedules_outlet_idx;
+
+ALTER TABLE outlets ADD COLUMN IF NOT EXISTS timezone varchar(64) NOT NULL DEFAULT 'Asia/Jakarta';
+
+-- weekly opening hours in local time of the outlet, weekday 0 is sunday. A day could have several intervals,
+-- closes_at is exclusive and 24:00 closes at midnight, so hours which cross midnight are split into two days
+CREATE TABLE IF NOT EXISTS
+    outlet_schedules (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        outlet_id       uuid                        NOT NULL,
+        weekday         smallint                    NOT NULL,
+        opens_at        time                        NOT NULL,
+        closes_at       time                        NOT NULL,
+
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE CASCADE,
+        CHECK (weekday BETWEEN 0 AND 6),
+        CHECK (opens_at < closes_at)
+    );
+CREATE INDEX IF NOT EXISTS outlet_schedules_outlet_idx ON outlet_schedules (outlet_id, weekday);
+
+-- dates on which an outlet is closed all day regardless of its schedule, such as holidays
+CREATE TABLE IF NOT EXISTS
+    outlet_closures (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        outlet_id       uuid                        NOT NULL,
+        date            date                        NOT NULL,
+        reason          varchar(255)                NOT NULL    DEFAULT '',
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE CASCADE,
+        UNIQUE (outlet_id, date)
+    );
+
+-- previous opening and closing time become the same hours on every weekday, in Asia/Jakarta time which was assumed so far
+INSERT INTO outlet_schedules (outlet_id, weekday, opens_at, closes_at)
+SELECT id, weekday, opens_at, CASE WHEN closes_at > opens_at THEN closes_at ELSE '24:00'::time END
+FROM (
+    SELECT o.id, d.weekday,
+        (o.opening_time AT TIME ZONE 'Asia/Jakarta')::time AS opens_at,
+        (o.closing_time AT TIME ZONE 'Asia/Jakarta')::time AS closes_at
+    FROM outlets o
+    CROSS JOIN generate_series(0, 6) AS d(weekday)
+) hours;
+
+INSERT INTO outlet_schedules (outlet_id, weekday, opens_at, closes_at)
+SELECT id, (weekday + 1) % 7, '00:00'::time, closes_at
+FROM (
+    SELECT o.id, d.weekday,
+        (o.opening_time AT TIME ZONE 'Asia/Jakarta')::time AS opens_at,
+        (o.closing_time AT TIME ZONE 'Asia/Jakarta')::time AS closes_at
+    FROM outlets o
+    CROSS JOIN generate_series(0, 6) AS d(weekday)
+) hours
+WHERE closes_at <= opens_at AND closes_at > '00:00'::time;
+
+ALTER TABLE outlets DROP COLUMN IF EXISTS opening_time;
+ALTER TABLE outlets DROP COLUMN IF EXISTS closing_time;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outlets ADD COLUMN IF NOT EXISTS opening_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE outlets ADD COLUMN IF NOT EXISTS closing_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- only the widest hours of a day survive, since a single opening and closing time could not hold a schedule
+UPDATE outlets o
+SET
+    opening_time = (CURRENT_DATE + s.opens_at) AT TIME ZONE o.timezone,
+    closing_time = (CURRENT_DATE + s.closes_at) AT TIME ZONE o.timezone
+FROM (
+    SELECT outlet_id, MIN(opens_at) AS opens_at, MAX(closes_at) AS closes_at
+    FROM outlet_schedules
+    GROUP BY outlet_id
+) s
+WHERE s.outlet_id = o.id;
+
+ALTER TABLE outlets ALTER COLUMN opening_time DROP DEFAULT;
+ALTER TABLE outlets ALTER COLUMN closing_time DROP DEFAULT;
+ALTER TABLE outlets DROP COLUMN IF EXISTS timezone;
+
+DROP TABLE IF EXISTS outlet_closures;
+DROP TABLE IF EXISTS outlet_schedules;
+DROP INDEX IF EXISTS outlet_schedules_outlet_idx;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP INDEX IF EXISTS addresses_coordinates_idx;
+
+-- coordinates are in degrees of WGS84, an address either has both of them or none
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS latitude double precision;
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS longitude double precision;
+ALTER TABLE addresses ADD CONSTRAINT addresses_coordinates_check CHECK (
+    (latitude IS NULL AND longitude IS NULL)
+    OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+);
+
+-- bounding box of nearby search narrows candidates through this index, before exact distance is computed
+CREATE INDEX IF NOT EXISTS addresses_coordinates_idx ON addresses (latitude, longitude) WHERE latitude IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS addresses_coordinates_idx;
+ALTER TABLE addresses DROP CONSTRAINT IF EXISTS addresses_coordinates_check;
+ALTER TABLE addresses DROP COLUMN IF EXISTS longitude;
+ALTER TABLE addresses DROP COLUMN IF EXISTS latitude;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP INDEX IF EXISTS menus_category_idx;
+DROP INDEX IF EXISTS menu_categories_outlet_name_idx;
+ALTER TABLE menus DROP COLUMN IF EXISTS category_id;
+ALTER TABLE menus DROP COLUMN IF EXISTS position;
+DROP TABLE IF EXISTS menu_categories;
+
+-- categories group menus of an outlet, they are shown by their position in ascending order
+CREATE TABLE IF NOT EXISTS
+    menu_categories (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        outlet_id       uuid                        NOT NULL,
+        name            varchar(50)                 NOT NULL,
+        position        integer                     NOT NULL    DEFAULT 0,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (outlet_id) REFERENCES outlets(id) ON DELETE CASCADE
+    );
+CREATE UNIQUE INDEX IF NOT EXISTS menu_categories_outlet_name_idx ON menu_categories (outlet_id, lower(name));
+
+-- menu without category is shown after every category, position orders menus within their category
+ALTER TABLE menus ADD COLUMN IF NOT EXISTS category_id uuid NULL REFERENCES menu_categories(id) ON DELETE SET NULL;
+ALTER TABLE menus ADD COLUMN IF NOT EXISTS position integer NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS menus_category_idx ON menus (category_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS menus_category_idx;
+ALTER TABLE menus DROP COLUMN IF EXISTS position;
+ALTER TABLE menus DROP COLUMN IF EXISTS category_id;
+DROP INDEX IF EXISTS menu_categories_outlet_name_idx;
+DROP TABLE IF EXISTS menu_categories;
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP INDEX IF EXISTS menu_topings_group_idx;
+DROP INDEX IF EXISTS menu_toping_groups_menu_name_idx;
+ALTER TABLE menu_topings DROP COLUMN IF EXISTS group_id;
+ALTER TABLE menu_topings DROP COLUMN IF EXISTS position;
+DROP TABLE IF EXISTS menu_toping_groups;
+
+-- groups are choices of toppings on a menu, such as "choose 1 size" or "up to 3 extras". Selection of a group is counted
+-- against min_select and max_select, max_select of null is unlimited. Optional group could be skipped entirely,
+-- while required one should always be chosen from
+CREATE TABLE IF NOT EXISTS
+    menu_toping_groups (
+        id              uuid PRIMARY KEY            NOT NULL    DEFAULT gen_random_uuid(),
+        menu_id         uuid                        NOT NULL,
+        name            varchar(50)                 NOT NULL,
+        min_select      integer                     NOT NULL    DEFAULT 0,
+        max_select      integer                     NULL,
+        is_required     boolean                     NOT NULL    DEFAULT false,
+        position        integer                     NOT NULL    DEFAULT 0,
+        created_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+        updated_at      TIMESTAMP WITH TIME ZONE    NOT NULL    DEFAULT CURRENT_TIMESTAMP,
+
+        FOREIGN KEY (menu_id) REFERENCES menus(id) ON DELETE CASCADE,
+        CHECK (min_select >= 0),
+        CHECK (max_select IS NULL OR (max_select >= 1 AND max_select >= min_select)),
+        CHECK (NOT is_required OR min_select >= 1)
+    );
+CREATE UNIQUE INDEX IF NOT EXISTS menu_toping_groups_menu_name_idx ON menu_toping_groups (menu_id, lower(name));
+
+-- topping without group is an extra which could be freely chosen, position orders toppings within their group
+ALTER TABLE menu_topings ADD COLUMN IF NOT EXISTS group_id uuid NULL REFERENCES menu_toping_groups(id) ON DELETE SET NULL;
+ALTER TABLE menu_topings ADD COLUMN IF NOT EXISTS position integer NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS menu_topings_group_idx ON menu_topings (group_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS menu_topings_group_idx;
+ALTER TABLE menu_topings DROP COLUMN IF EXISTS position;
+ALTER TABLE menu_topings DROP COLUMN IF EXISTS group_id;
+DROP INDEX IF EXISTS menu_toping_groups_menu_name_idx;
+DROP TABLE IF EXISTS menu_toping_groups;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goplateframework/config"
+	"github.com/goplateframework/pkg/logger"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string // plain text
+}
+
+// Mailer delivers a message to its recipient, implementation is chosen by driver on config
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+func Init(conf *config.Config, log *logger.Log) (Mailer, error) {
+	switch conf.Mailer.Driver {
+	case "smtp":
+		return newSMTP(conf), nil
+	case "file":
+		if conf.Mailer.Filepath == "" {
+			return nil, fmt.Errorf("mailer file driver requires filepath")
+		}
+		return newFile(conf.Mailer.Filepath), nil
+	case "log", "":
+		return newLog(log), nil
+	default:
+		return nil, fmt.Errorf("unsupported mailer driver %s, expected smtp, file or log", conf.Mailer.Driver)
+	}
+}
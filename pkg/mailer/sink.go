@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/goplateframework/pkg/logger"
+)
+
+// logMailer prints every message to logger instead of delivering it, intended for local development
+type logMailer struct {
+	log *logger.Log
+}
+
+func newLog(log *logger.Log) *logMailer {
+	return &logMailer{log}
+}
+
+func (m *logMailer) Send(_ context.Context, msg *Message) error {
+	m.log.Infof("mail to %s, subject: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// fileMailer appends every message to a file instead of delivering it, so it could be inspected on tests
+type fileMailer struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newFile(path string) *fileMailer {
+	return &fileMailer{path: path}
+}
+
+func (m *fileMailer) Send(_ context.Context, msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n---\n\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/goplateframework/config"
+)
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func newSMTP(conf *config.Config) *smtpMailer {
+	m := &smtpMailer{
+		addr: net.JoinHostPort(conf.Mailer.Host, conf.Mailer.Port),
+		from: conf.Mailer.From,
+	}
+
+	// servers without authentication, such as local relay, are allowed
+	if conf.Mailer.Username != "" {
+		m.auth = smtp.PlainAuth("", conf.Mailer.Username, conf.Mailer.Password, conf.Mailer.Host)
+	}
+
+	return m
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	errCh := make(chan error, 1)
+
+	// net/smtp is not aware of context, so the caller is released whenever context is done
+	go func() {
+		errCh <- smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, m.compose(msg))
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *smtpMailer) compose(msg *Message) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,76 @@
+package oidcprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/goplateframework/config"
+	"golang.org/x/oauth2"
+)
+
+var ErrUnknownProvider = errors.New("unknown oidc provider")
+
+type Provider struct {
+	Name     string
+	OAuth2   *oauth2.Config
+	Verifier *oidc.IDTokenVerifier
+}
+
+// Registry holds configured providers, each provider is discovered on its first use,
+// so server could start even though an issuer is unreachable
+type Registry struct {
+	mu        sync.Mutex
+	providers map[string]*Provider
+	conf      *config.Config
+}
+
+func Init(conf *config.Config) *Registry {
+	return &Registry{
+		providers: make(map[string]*Provider),
+		conf:      conf,
+	}
+}
+
+func (r *Registry) Get(ctx context.Context, name string) (*Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.providers[name]; ok {
+		return p, nil
+	}
+
+	pc, ok := r.conf.OIDC.Providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	// provider keeps given context to fetch signing keys later on, so it should outlive the request which triggers it
+	op, err := oidc.NewProvider(context.WithoutCancel(ctx), pc.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discover oidc provider %s, %v", name, err)
+	}
+
+	scopes := pc.Scopes
+	if !slices.Contains(scopes, oidc.ScopeOpenID) {
+		scopes = append([]string{oidc.ScopeOpenID}, scopes...)
+	}
+
+	p := &Provider{
+		Name: name,
+		OAuth2: &oauth2.Config{
+			ClientID:     pc.ClientID,
+			ClientSecret: pc.ClientSecret,
+			RedirectURL:  pc.RedirectURL,
+			Endpoint:     op.Endpoint(),
+			Scopes:       scopes,
+		},
+		Verifier: op.Verifier(&oidc.Config{ClientID: pc.ClientID}),
+	}
+
+	r.providers[name] = p
+	return p, nil
+}
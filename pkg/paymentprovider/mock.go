@@ -0,0 +1,105 @@
+package paymentprovider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Mock keeps charges in memory instead of taking money, intended for local development and tests.
+// Charges stay pending until they are settled, just like a customer who has not paid yet
+type Mock struct {
+	mu      sync.Mutex
+	charges map[string]*Charge
+	keys    map[string]string // idempotency key into charge reference
+}
+
+func NewMock() *Mock {
+	return &Mock{
+		charges: make(map[string]*Charge),
+		keys:    make(map[string]string),
+	}
+}
+
+func (m *Mock) Name() string {
+	return "mock"
+}
+
+func (m *Mock) CreateCharge(_ context.Context, req *ChargeRequest) (*Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ref, ok := m.keys[req.IdempotencyKey]; ok {
+		c := *m.charges[ref]
+		return &c, nil
+	}
+
+	c := &Charge{
+		Reference: fmt.Sprintf("mock_%s", uuid.NewString()),
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Status:    StatusPending,
+	}
+	m.charges[c.Reference] = c
+	m.keys[req.IdempotencyKey] = c.Reference
+
+	res := *c
+	return &res, nil
+}
+
+func (m *Mock) GetCharge(_ context.Context, reference string) (*Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	res := *c
+	return &res, nil
+}
+
+func (m *Mock) Refund(_ context.Context, reference string) (*Charge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	if c.Status != StatusSucceeded {
+		return nil, ErrNotRefundable
+	}
+	c.Status = StatusRefunded
+
+	res := *c
+	return &res, nil
+}
+
+// Settle moves a pending charge into succeeded or failed, as a real provider does once the customer pays or gives up
+func (m *Mock) Settle(reference, status, failureReason string) (*Charge, error) {
+	if status != StatusSucceeded && status != StatusFailed {
+		return nil, fmt.Errorf("charge could not be settled as %s", status)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.charges[reference]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	if c.Status != StatusPending {
+		return nil, fmt.Errorf("charge is already %s", c.Status)
+	}
+	c.Status = status
+	c.FailureReason = failureReason
+
+	res := *c
+	return &res, nil
+}
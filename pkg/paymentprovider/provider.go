@@ -0,0 +1,82 @@
+package paymentprovider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/goplateframework/config"
+)
+
+// charge statuses which every provider reports, see payment_status enum on migration
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusRefunded  = "refunded"
+)
+
+var (
+	ErrChargeNotFound = errors.New("charge not found")
+	ErrNotRefundable  = errors.New("charge is not refundable")
+)
+
+type ChargeRequest struct {
+	IdempotencyKey string // provider returns the same charge whenever a key is reused
+	Amount         float64
+	Currency       string
+	Description    string
+}
+
+type Charge struct {
+	Reference     string // id of the charge on provider
+	Amount        float64
+	Currency      string
+	Status        string
+	FailureReason string
+}
+
+// Provider takes money on behalf of the API, implementation is chosen by driver on config.
+// Charges are settled asynchronously, provider reports their outcome through signed webhooks
+type Provider interface {
+	Name() string
+	CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error)
+	GetCharge(ctx context.Context, reference string) (*Charge, error)
+	Refund(ctx context.Context, reference string) (*Charge, error)
+}
+
+func Init(conf *config.Config) (Provider, error) {
+	switch conf.Payment.Driver {
+	case "mock", "":
+		return NewMock(), nil
+	default:
+		return nil, fmt.Errorf("unsupported payment driver %s, expected mock", conf.Payment.Driver)
+	}
+}
+
+// Sign returns hex encoded HMAC-SHA256 of a webhook payload
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature tells whether signature belongs to given webhook payload, in constant time.
+// Nothing is verified whenever secret is not configured, so unsigned webhooks are never trusted
+func VerifySignature(secret string, payload []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
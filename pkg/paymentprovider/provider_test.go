@@ -0,0 +1,33 @@
+package paymentprovider
+
+import "testing"
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "webhook-secret"
+	payload := []byte(`{"reference":"abc","status":"paid"}`)
+	valid := Sign(secret, payload)
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		signature string
+		want      bool
+	}{
+		{"valid signature", secret, payload, valid, true},
+		{"signed by another secret", secret, payload, Sign("another-secret", payload), false},
+		{"tampered payload", secret, []byte(`{"reference":"abc","status":"failed"}`), valid, false},
+		{"bad signature", secret, payload, "deadbeef", false},
+		{"signature is not hex", secret, payload, "not-a-signature", false},
+		{"empty signature", secret, payload, "", false},
+		{"secret not configured", "", payload, Sign("", payload), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifySignature(tt.secret, tt.payload, tt.signature); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}